@@ -0,0 +1,208 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records an audit trail of the actions performed through the REST API
+//
+// Records are stored in Consul by day and could be queried by time range and by deployment.
+// Records older than a configurable retention period are purged by the leader Yorc server.
+package audit
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+const (
+	// OutcomeSuccess is the outcome of an action whose response has a status code lower than 400
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of an action whose response has a status code greater or equal to 400
+	OutcomeFailure = "failure"
+)
+
+// keyTimeLayout is a fixed length variant of time.RFC3339Nano, it allows to sort keys by time
+const keyTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// dayLayout is the layout of the days under which records are stored
+const dayLayout = "2006-01-02"
+
+// A Record is the audit trail of an action performed through the REST API
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Principal is the name of the authenticated client, it is empty if authentication is disabled or failed
+	Principal string `json:"principal,omitempty"`
+	ClientIP  string `json:"client_ip"`
+	Method    string `json:"method"`
+	Route     string `json:"route"`
+	// DeploymentID is the deployment targeted by the action if any
+	DeploymentID string `json:"deployment_id,omitempty"`
+	// TaskID is the task created or targeted by the action if any
+	TaskID  string `json:"task_id,omitempty"`
+	Status  int    `json:"status"`
+	Outcome string `json:"outcome"`
+}
+
+// A Filter restricts the records returned by Query
+//
+// Zero values mean no restriction.
+type Filter struct {
+	// From is the inclusive lower bound of records timestamps
+	From time.Time
+	// To is the exclusive upper bound of records timestamps
+	To           time.Time
+	DeploymentID string
+}
+
+// OutcomeFromStatus returns the outcome of an action given the status code of its response
+func OutcomeFromStatus(status int) string {
+	if status >= 400 {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// Store stores a record into Consul
+func Store(kv *api.KV, r Record) error {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+	if r.Outcome == "" {
+		r.Outcome = OutcomeFromStatus(r.Status)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+	// The suffix prevents records of concurrent requests from overriding each other
+	ts := r.Timestamp.UTC()
+	key := path.Join(consulutil.AuditKVPrefix, ts.Format(dayLayout), ts.Format(keyTimeLayout)+"_"+uuid.NewV4().String())
+	_, err = kv.Put(&api.KVPair{Key: key, Value: b}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// Query returns the records matching the given filter sorted by timestamp
+//
+// Only the days in the time range of the filter are read.
+func Query(kv *api.KV, filter Filter) ([]Record, error) {
+	days, err := listDays(kv)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0)
+	for _, day := range days {
+		if !filter.matchesDay(day) {
+			continue
+		}
+		dayRecords, err := queryDay(kv, day, filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, dayRecords...)
+	}
+	return records, nil
+}
+
+// Purge removes the records of the days ending before the given time
+func Purge(kv *api.KV, before time.Time) error {
+	days, err := listDays(kv)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		if day.Add(24 * time.Hour).After(before) {
+			continue
+		}
+		_, err = kv.DeleteTree(path.Join(consulutil.AuditKVPrefix, day.Format(dayLayout))+"/", nil)
+		if err != nil {
+			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+	}
+	return nil
+}
+
+// listDays returns the sorted days having records
+func listDays(kv *api.KV) ([]time.Time, error) {
+	keys, _, err := kv.Keys(consulutil.AuditKVPrefix+"/", "/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	days := make([]time.Time, 0, len(keys))
+	for _, key := range keys {
+		day, err := time.Parse(dayLayout, path.Base(key))
+		if err != nil {
+			// Not a day prefix
+			continue
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func queryDay(kv *api.KV, day time.Time, filter Filter) ([]Record, error) {
+	kvps, _, err := kv.List(path.Join(consulutil.AuditKVPrefix, day.Format(dayLayout))+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	records := make([]Record, 0)
+	for _, kvp := range kvps {
+		key := path.Base(kvp.Key)
+		// Filter on the key timestamp first to avoid unmarshalling records out of the time range
+		if i := strings.Index(key, "_"); i > 0 {
+			ts, err := time.Parse(keyTimeLayout, key[:i])
+			if err == nil && !filter.matchesTime(ts) {
+				continue
+			}
+		}
+		var r Record
+		if err = json.Unmarshal(kvp.Value, &r); err != nil {
+			return nil, errors.Wrapf(err, "failed to read audit record %q", key)
+		}
+		if !filter.matchesTime(r.Timestamp) {
+			continue
+		}
+		if filter.DeploymentID != "" && r.DeploymentID != filter.DeploymentID {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// matchesDay checks if some records of the given day may match the filter time range
+func (f Filter) matchesDay(day time.Time) bool {
+	if !f.From.IsZero() && !day.Add(24*time.Hour).After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !day.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+func (f Filter) matchesTime(t time.Time) bool {
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !t.Before(f.To) {
+		return false
+	}
+	return true
+}
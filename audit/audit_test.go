@@ -0,0 +1,105 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+func TestOutcomeFromStatus(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, OutcomeFromStatus(http.StatusAccepted))
+	assert.Equal(t, OutcomeSuccess, OutcomeFromStatus(http.StatusFound))
+	assert.Equal(t, OutcomeFailure, OutcomeFromStatus(http.StatusForbidden))
+	assert.Equal(t, OutcomeFailure, OutcomeFromStatus(http.StatusInternalServerError))
+}
+
+func testStoreAndQuery(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	base := time.Date(2018, time.October, 1, 10, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Timestamp: base, Principal: "ops", ClientIP: "10.0.0.1", Method: http.MethodDelete, Route: "/deployments/dep1", DeploymentID: "dep1", TaskID: "t1", Status: http.StatusAccepted},
+		{Timestamp: base.Add(time.Hour), ClientIP: "10.0.0.2", Method: http.MethodPost, Route: "/deployments/dep2/scale/Compute", DeploymentID: "dep2", Status: http.StatusForbidden},
+		{Timestamp: base.Add(2 * time.Hour), Principal: "ops", ClientIP: "10.0.0.1", Method: http.MethodPut, Route: "/deployments/dep1/tasks/t1/steps/Compute_install", DeploymentID: "dep1", TaskID: "t1", Status: http.StatusOK},
+	}
+	// Store them in reverse order to check that results are sorted by time
+	for i := len(records) - 1; i >= 0; i-- {
+		require.NoError(t, Store(kv, records[i]))
+	}
+
+	all, err := Query(kv, Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	for i, r := range all {
+		assert.True(t, records[i].Timestamp.Equal(r.Timestamp), "unexpected timestamp for record %d", i)
+		assert.Equal(t, records[i].Route, r.Route)
+	}
+	assert.Equal(t, OutcomeSuccess, all[0].Outcome)
+	assert.Equal(t, OutcomeFailure, all[1].Outcome)
+
+	byDeployment, err := Query(kv, Filter{DeploymentID: "dep1"})
+	require.NoError(t, err)
+	require.Len(t, byDeployment, 2)
+	assert.Equal(t, "t1", byDeployment[0].TaskID)
+	assert.Equal(t, "ops", byDeployment[1].Principal)
+
+	byTime, err := Query(kv, Filter{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, byTime, 1)
+	assert.Equal(t, "dep2", byTime[0].DeploymentID)
+
+	none, err := Query(kv, Filter{From: base.Add(time.Hour), DeploymentID: "unknown"})
+	require.NoError(t, err)
+	assert.Len(t, none, 0)
+}
+
+func testQueryByDayAndPurge(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	base := time.Date(2018, time.November, 1, 23, 30, 0, 0, time.UTC)
+	records := []Record{
+		{Timestamp: base, ClientIP: "10.0.0.1", Method: http.MethodPost, Route: "/deployments/dep3", DeploymentID: "dep3", Status: http.StatusCreated},
+		{Timestamp: base.Add(time.Hour), ClientIP: "10.0.0.1", Method: http.MethodDelete, Route: "/deployments/dep3", DeploymentID: "dep3", Status: http.StatusAccepted},
+		{Timestamp: base.Add(48 * time.Hour), ClientIP: "10.0.0.1", Method: http.MethodPost, Route: "/deployments/dep3/workflows/run", DeploymentID: "dep3", Status: http.StatusCreated},
+	}
+	for _, r := range records {
+		require.NoError(t, Store(kv, r))
+	}
+
+	// Records are stored by day
+	keys, _, err := kv.Keys(consulutil.AuditKVPrefix+"/2018-11-02/", "/", nil)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	overMidnight, err := Query(kv, Filter{From: base.Add(-time.Minute), To: base.Add(2 * time.Hour), DeploymentID: "dep3"})
+	require.NoError(t, err)
+	require.Len(t, overMidnight, 2)
+	assert.Equal(t, http.MethodPost, overMidnight[0].Method)
+	assert.Equal(t, http.MethodDelete, overMidnight[1].Method)
+
+	// Days partially covered by the retention are kept
+	require.NoError(t, Purge(kv, base.Add(30*time.Minute)))
+	remaining, err := Query(kv, Filter{DeploymentID: "dep3"})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.True(t, records[1].Timestamp.Equal(remaining[0].Timestamp))
+	assert.True(t, records[2].Timestamp.Equal(remaining[1].Timestamp))
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+// defaultRetention is the default duration audit records are kept
+const defaultRetention = 90 * 24 * time.Hour
+
+// defaultPurgeInterval is the default delay between two purges of expired audit records
+const defaultPurgeInterval = time.Hour
+
+var defaultPurger *purger
+
+// A purger removes audit records older than the retention period
+//
+// Only the leader Yorc server purges records.
+type purger struct {
+	kv           *api.KV
+	retention    time.Duration
+	interval     time.Duration
+	serviceKey   string
+	chShutdown   chan struct{}
+	chStopPurges chan struct{}
+	isActive     bool
+	isActiveLock sync.Mutex
+}
+
+// StartPurger allows to instantiate a default purger removing expired audit records when this server is the leader
+//
+// Records are kept for the retention defined in the audit configuration, they are kept forever if this retention is negative.
+func StartPurger(cfg config.Configuration, cc *api.Client) {
+	retention := cfg.Audit.Retention
+	if retention < 0 {
+		log.Debugf("Negative audit retention, audit records are never purged")
+		return
+	}
+	if retention == 0 {
+		retention = defaultRetention
+	}
+	interval := cfg.Audit.PurgeInterval
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+	defaultPurger = &purger{
+		kv:         cc.KV(),
+		retention:  retention,
+		interval:   interval,
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/audit_purge/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	// Watch leader election for audit purger
+	go consulutil.WatchLeaderElection(cc, defaultPurger.serviceKey, defaultPurger.chShutdown, defaultPurger.startPurges, defaultPurger.stopPurges)
+}
+
+// StopPurger allows to stop purging expired audit records
+func StopPurger() {
+	if defaultPurger == nil {
+		return
+	}
+	defaultPurger.stopPurges()
+
+	// Stop watch leader election
+	close(defaultPurger.chShutdown)
+}
+
+func (p *purger) startPurges() {
+	p.isActiveLock.Lock()
+	defer p.isActiveLock.Unlock()
+	if p.isActive {
+		log.Println("Audit records purger is already running.")
+		return
+	}
+	log.Debugf("Audit records purger is now running.")
+	p.isActive = true
+	p.chStopPurges = make(chan struct{})
+	go p.run(p.chStopPurges)
+}
+
+func (p *purger) stopPurges() {
+	p.isActiveLock.Lock()
+	defer p.isActiveLock.Unlock()
+	if p.isActive {
+		log.Debugf("Audit records purger is about to be stopped")
+		close(p.chStopPurges)
+		p.isActive = false
+	}
+}
+
+func (p *purger) run(chStop chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-chStop:
+			log.Debugf("Ending audit records purger has been requested: stop it now.")
+			return
+		case <-p.chShutdown:
+			log.Debugf("Shutdown has been sent: stop audit records purger now.")
+			return
+		case <-ticker.C:
+			if err := Purge(p.kv, time.Now().Add(-p.retention)); err != nil {
+				err = errors.Wrap(err, "[WARN] Error during audit records purge")
+				log.Print(err)
+				log.Debugf("%+v", err)
+			}
+		}
+	}
+}
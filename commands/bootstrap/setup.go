@@ -176,7 +176,7 @@ func setupYorcServer(workingDirectoryPath string) error {
 			return err
 		}
 
-		request, err := client.NewRequest("PUT", "/hosts_pool",
+		request, err := client.NewRequest("PUT", "/hosts_pool/default",
 			bytes.NewBuffer(bArray))
 		if err != nil {
 			return err
@@ -0,0 +1,167 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v3/commands/httputil"
+	"github.com/ystia/yorc/v3/helper/tabutil"
+	"github.com/ystia/yorc/v3/rest"
+)
+
+func init() {
+	var nodeName string
+	var instance string
+	var watch bool
+	var refreshTime time.Duration
+
+	var checksCmd = &cobra.Command{
+		Use:   "checks <DeploymentId>",
+		Short: "Get monitoring checks reports of a deployment",
+		Long: `Display the reports of the monitoring checks of a given deployment.
+It prints for each monitored node instance the check type, interval and status and, for the last status change, its time and the message reported by the check.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			if instance != "" && nodeName == "" {
+				return errors.New("The --instance flag requires the --node flag to be set")
+			}
+			client, err := httputil.GetClient(ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+
+			err = DisplayCheckReports(client, args[0], nodeName, instance, watch, refreshTime)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			return nil
+		},
+	}
+	checksCmd.Flags().StringVarP(&nodeName, "node", "n", "", "Display only checks reports of the given node.")
+	checksCmd.Flags().StringVarP(&instance, "instance", "i", "", "Display only the check report of the given instance (requires --node).")
+	checksCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch checks reports, refreshing them periodically until interrupted.")
+	checksCmd.Flags().DurationVar(&refreshTime, "refresh", 3*time.Second, "Refresh period of checks reports in watch mode.")
+
+	DeploymentsCmd.AddCommand(checksCmd)
+}
+
+// DisplayCheckReports displays the monitoring checks reports of a deployment
+//
+// If nodeName is not empty only the reports of this node are displayed, and if instance is also set only the
+// report of this instance. In watch mode reports are refreshed every refreshTime until the command is interrupted.
+func DisplayCheckReports(client *httputil.YorcClient, deploymentID, nodeName, instance string, watch bool, refreshTime time.Duration) error {
+	colorize := !NoColor
+	if colorize {
+		defer color.Unset()
+	}
+	reqPath := path.Join("/deployments", deploymentID, "checks", nodeName, instance)
+	for {
+		reports, err := getCheckReports(client, deploymentID, reqPath, instance != "")
+		if err != nil {
+			return err
+		}
+		if watch {
+			// Clear the screen and set the cursor to row 0, column 0
+			fmt.Printf("\033[H\033[2J")
+			fmt.Printf("Every %s: checks of deployment %s\t%s\n\n", refreshTime, deploymentID, time.Now().Format(time.RFC1123))
+		}
+		if len(reports) == 0 {
+			fmt.Println("No monitoring checks")
+		} else {
+			fmt.Println(renderCheckReports(reports, colorize))
+		}
+		if !watch {
+			return nil
+		}
+		time.Sleep(refreshTime)
+	}
+}
+
+func getCheckReports(client *httputil.YorcClient, deploymentID, reqPath string, single bool) ([]rest.CheckReport, error) {
+	request, err := client.NewRequest("GET", reqPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if single {
+		httputil.HandleHTTPStatusCode(response, reqPath, "check", http.StatusOK)
+	} else {
+		httputil.HandleHTTPStatusCode(response, deploymentID, "deployment", http.StatusOK, http.StatusNoContent)
+	}
+	if response.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if single {
+		var report rest.CheckReport
+		err = json.Unmarshal(body, &report)
+		return []rest.CheckReport{report}, err
+	}
+	var reportsCol rest.CheckReportsCollection
+	err = json.Unmarshal(body, &reportsCol)
+	return reportsCol.Reports, err
+}
+
+func renderCheckReports(reports []rest.CheckReport, colorize bool) string {
+	checksTable := tabutil.NewTable()
+	checksTable.AddHeaders("Node", "Instance", "Type", "Interval", "Status", "Last Transition", "Message")
+	for _, report := range reports {
+		var lastTransition string
+		if report.LastTransition != nil {
+			lastTransition = report.LastTransition.Local().Format(time.RFC3339)
+		}
+		checksTable.AddRow(report.NodeName, report.Instance, report.Type, report.Interval,
+			getColoredCheckStatus(colorize, report.Status), lastTransition, report.Message)
+	}
+	return checksTable.Render()
+}
+
+func getColoredCheckStatus(colorize bool, status string) string {
+	if !colorize {
+		return status
+	}
+	switch strings.ToLower(status) {
+	case "passing":
+		return color.New(color.FgHiGreen, color.Bold).SprintFunc()(status)
+	case "critical":
+		return color.New(color.FgHiRed, color.Bold).SprintFunc()(status)
+	case "warning":
+		return color.New(color.FgHiYellow, color.Bold).SprintFunc()(status)
+	default:
+		return color.New(color.Bold).SprintFunc()(status)
+	}
+}
@@ -59,6 +59,7 @@ func init() {
 				return err
 			}
 			var location = ""
+			var submitted bool
 			if !fileInfo.IsDir() {
 				file, err := os.Open(absPath)
 				if err != nil {
@@ -77,10 +78,11 @@ func init() {
 					if err != nil {
 						httputil.ErrExit(err)
 					}
+					submitted = true
 				}
 			}
 
-			if location == "" {
+			if !submitted {
 				csarZip, err := ziputil.ZipPath(absPath)
 				if err != nil {
 					httputil.ErrExit(err)
@@ -91,6 +93,11 @@ func init() {
 					httputil.ErrExit(err)
 				}
 			}
+			if location == "" {
+				// Update of an existing deployment that didn't require to run a workflow
+				fmt.Printf("Deployment %s updated.\n", deploymentID)
+				return nil
+			}
 			taskID := path.Base(location)
 			if deploymentID == "" {
 				deploymentID = path.Base(path.Clean(location + "/../.."))
@@ -110,7 +117,7 @@ func init() {
 	deployCmd.PersistentFlags().BoolVarP(&shouldStreamEvents, "stream-events", "e", false, "Stream events after deploying the CSAR.")
 	// Do not impose a max id length as it doesn't have a concrete impact for now
 	//deployCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a id for this deployment. This id should not already exists, should respect the following format: %q and should be less than %d characters long", rest.YorcDeploymentIDPattern, rest.YorcDeploymentIDMaxLength))
-	deployCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a id for this deployment. This id should not already exists, should respect the following format: %q", rest.YorcDeploymentIDPattern))
+	deployCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a id for this deployment. This id should respect the following format: %q. If a deployment with this id already exists, it is updated.", rest.YorcDeploymentIDPattern))
 	DeploymentsCmd.AddCommand(deployCmd)
 }
 
@@ -132,13 +139,17 @@ func SubmitCSAR(csarZip []byte, client *httputil.YorcClient, deploymentID string
 		return "", err
 	}
 	defer response.Body.Close()
-	if response.StatusCode != 201 {
+	if response.StatusCode != http.StatusCreated && (deploymentID == "" || response.StatusCode != http.StatusOK) {
 		// Try to get the reason
 		httputil.PrintErrors(response.Body)
-		return "", errors.Errorf("POST failed: Expecting HTTP Status code 201 got %d, reason %q", response.StatusCode, response.Status)
+		return "", errors.Errorf("%s failed: Expecting HTTP Status code 201 got %d, reason %q", request.Method, response.StatusCode, response.Status)
 	}
 	if location := response.Header.Get("Location"); location != "" {
 		return location, nil
 	}
+	if response.StatusCode == http.StatusOK {
+		// A deployment update may not require to run a task
+		return "", nil
+	}
 	return "", errors.New("No \"Location\" header returned in Yorc response")
 }
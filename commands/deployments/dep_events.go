@@ -34,6 +34,8 @@ import (
 func init() {
 	var fromBeginning bool
 	var noStream bool
+	var sse bool
+	var filters entriesFilters
 	var eventCmd = &cobra.Command{
 		Use:     "events [<DeploymentId>]",
 		Short:   "Stream events for a deployment or all deployments",
@@ -56,17 +58,38 @@ func init() {
 			}
 			colorize := !NoColor
 
-			StreamsEvents(client, deploymentID, colorize, fromBeginning, noStream)
+			if sse {
+				if noStream {
+					return errors.New("--stream and --no-stream flags are mutually exclusive")
+				}
+				var fromIndex uint64
+				if !fromBeginning {
+					fromIndex = getLastIndex(client, deploymentID, "events")
+					fmt.Println("Streaming new events...")
+				}
+				streamSSE(client, deploymentID, "events", fromIndex, filters, func(event json.RawMessage) {
+					fmt.Printf("%s\n", formatEvent(event, colorize))
+				})
+				return nil
+			}
+			streamsEvents(client, deploymentID, colorize, fromBeginning, noStream, filters.queryParams())
 			return nil
 		},
 	}
 	eventCmd.PersistentFlags().BoolVarP(&fromBeginning, "from-beginning", "b", false, "Show events from the beginning of deployments")
 	eventCmd.PersistentFlags().BoolVarP(&noStream, "no-stream", "n", false, "Show events then exit. Do not stream events. It implies --from-beginning")
+	eventCmd.PersistentFlags().BoolVar(&sse, "stream", false, "Use a server push stream (Server-Sent Events) instead of long polling requests to receive events")
+	eventCmd.PersistentFlags().StringVar(&filters.node, "node", "", "Show only events related to the given node")
+	eventCmd.PersistentFlags().StringVar(&filters.task, "task", "", "Show only events related to the given task ID")
 	DeploymentsCmd.AddCommand(eventCmd)
 }
 
 // StreamsEvents allows to stream events
 func StreamsEvents(client *httputil.YorcClient, deploymentID string, colorize, fromBeginning, stop bool) {
+	streamsEvents(client, deploymentID, colorize, fromBeginning, stop, "")
+}
+
+func streamsEvents(client *httputil.YorcClient, deploymentID string, colorize, fromBeginning, stop bool, filtersParam string) {
 	if colorize {
 		defer color.Unset()
 	}
@@ -98,9 +121,9 @@ func StreamsEvents(client *httputil.YorcClient, deploymentID string, colorize, f
 	}
 	for {
 		if deploymentID != "" {
-			request, err = client.NewRequest("GET", fmt.Sprintf("/deployments/%s/events?index=%d", deploymentID, lastIdx), nil)
+			request, err = client.NewRequest("GET", fmt.Sprintf("/deployments/%s/events?index=%d%s", deploymentID, lastIdx, filtersParam), nil)
 		} else {
-			request, err = client.NewRequest("GET", fmt.Sprintf("/events?index=%d", lastIdx), nil)
+			request, err = client.NewRequest("GET", fmt.Sprintf("/events?index=%d%s", lastIdx, filtersParam), nil)
 		}
 		if err != nil {
 			httputil.ErrExit(err)
@@ -198,6 +221,8 @@ func formatEvent(event json.RawMessage, colorize bool) string {
 			data[events.ETaskID.String()], data[events.ETaskExecutionID.String()], data[events.EWorkflowID.String()], data[events.EInstanceID.String()], data[events.EWorkflowStepID.String()], data[events.ENodeID.String()], data[events.EOperationName.String()], formatOptionalInfo(data), data[events.EStatus.String()])
 	case events.StatusChangeTypeAttributeValue:
 		ret = fmt.Sprintf("%s:\t Deployment: %s\t Node: %s\t Instance: %s\t Attribute: %s\t Value: %s\t Status: %s\t\n", ts, data[events.EDeploymentID.String()], data[events.ENodeID.String()], data[events.EInstanceID.String()], data[events.EAttributeName.String()], data[events.EAttributeValue.String()], data[events.EStatus.String()])
+	case events.StatusChangeTypeCheck:
+		ret = fmt.Sprintf("%s:\t Deployment: %s\t Node: %s\t Instance: %s\t Check Status: %s\t Message: %s\n", ts, data[events.EDeploymentID.String()], data[events.ENodeID.String()], data[events.EInstanceID.String()], data[events.EStatus.String()], data[events.EMessage.String()])
 
 	}
 
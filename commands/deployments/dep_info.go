@@ -145,7 +145,9 @@ func DisplayInfo(client *httputil.YorcClient, deploymentID string, detailed, fol
 			dep.Status == deployments.DEPLOYED.String() ||
 			dep.Status == deployments.UNDEPLOYED.String() ||
 			dep.Status == deployments.DEPLOYMENT_FAILED.String() ||
-			dep.Status == deployments.UNDEPLOYMENT_FAILED.String()
+			dep.Status == deployments.UNDEPLOYMENT_FAILED.String() ||
+			dep.Status == deployments.UPDATED.String() ||
+			dep.Status == deployments.UPDATE_FAILURE.String()
 		if !finished {
 			time.Sleep(refreshTime)
 		}
@@ -34,6 +34,8 @@ import (
 func init() {
 	var fromBeginning bool
 	var noStream bool
+	var sse bool
+	var filters entriesFilters
 	var logCmd = &cobra.Command{
 		Use:     "logs [<DeploymentId>]",
 		Short:   "Stream logs for a deployment or all deployments",
@@ -56,17 +58,39 @@ func init() {
 			}
 			colorize := !NoColor
 
-			StreamsLogs(client, deploymentID, colorize, fromBeginning, noStream)
+			if sse {
+				if noStream {
+					return errors.New("--stream and --no-stream flags are mutually exclusive")
+				}
+				var fromIndex uint64
+				if !fromBeginning {
+					fromIndex = getLastIndex(client, deploymentID, "logs")
+					fmt.Println("Streaming new logs...")
+				}
+				streamSSE(client, deploymentID, "logs", fromIndex, filters, func(log json.RawMessage) {
+					printLog(log, colorize)
+				})
+				return nil
+			}
+			streamsLogs(client, deploymentID, colorize, fromBeginning, noStream, filters.queryParams())
 			return nil
 		},
 	}
 	logCmd.PersistentFlags().BoolVarP(&fromBeginning, "from-beginning", "b", false, "Show logs from the beginning of deployments")
 	logCmd.PersistentFlags().BoolVarP(&noStream, "no-stream", "n", false, "Show logs then exit. Do not stream logs. It implies --from-beginning")
+	logCmd.PersistentFlags().BoolVar(&sse, "stream", false, "Use a server push stream (Server-Sent Events) instead of long polling requests to receive logs")
+	logCmd.PersistentFlags().StringVar(&filters.node, "node", "", "Show only logs related to the given node")
+	logCmd.PersistentFlags().StringVar(&filters.task, "task", "", "Show only logs related to the given task ID")
+	logCmd.PersistentFlags().StringVar(&filters.level, "level", "", "Show only logs of the given comma-separated levels (INFO, DEBUG, WARN, ERROR)")
 	DeploymentsCmd.AddCommand(logCmd)
 }
 
 // StreamsLogs allows to stream logs
 func StreamsLogs(client *httputil.YorcClient, deploymentID string, colorize, fromBeginning, stop bool) {
+	streamsLogs(client, deploymentID, colorize, fromBeginning, stop, "")
+}
+
+func streamsLogs(client *httputil.YorcClient, deploymentID string, colorize, fromBeginning, stop bool, filtersParam string) {
 	if colorize {
 		defer color.Unset()
 	}
@@ -97,7 +121,6 @@ func StreamsLogs(client *httputil.YorcClient, deploymentID string, colorize, fro
 			fmt.Fprint(os.Stderr, "Failed to get latest log index from Yorc, logs will appear from the beginning.")
 		}
 	}
-	var filtersParam string
 	for {
 		if deploymentID != "" {
 			request, err = client.NewRequest("GET", fmt.Sprintf("/deployments/%s/logs?index=%d%s", deploymentID, lastIdx, filtersParam), nil)
@@ -129,11 +152,7 @@ func StreamsLogs(client *httputil.YorcClient, deploymentID string, colorize, fro
 
 		lastIdx = logs.LastIndex
 		for _, log := range logs.Logs {
-			if colorize {
-				fmt.Printf("%s\n", color.CyanString("%s", format(log)))
-			} else {
-				fmt.Printf("%s\n", format(log))
-			}
+			printLog(log, colorize)
 		}
 
 		response.Body.Close()
@@ -144,6 +163,14 @@ func StreamsLogs(client *httputil.YorcClient, deploymentID string, colorize, fro
 	}
 }
 
+func printLog(log json.RawMessage, colorize bool) {
+	if colorize {
+		fmt.Printf("%s\n", color.CyanString("%s", format(log)))
+	} else {
+		fmt.Printf("%s\n", format(log))
+	}
+}
+
 func format(log json.RawMessage) string {
 	var data map[string]interface{}
 	err := json.Unmarshal(log, &data)
@@ -32,6 +32,8 @@ func init() {
 	var shouldStreamEvents bool
 	var nodeName string
 	var instancesDelta int32
+	var batchSize int
+	var pauseBetweenBatches string
 	var scaleCmd = &cobra.Command{
 		Use:   "scale <id>",
 		Short: "Scale a node",
@@ -55,7 +57,7 @@ func init() {
 			}
 			deploymentID := args[0]
 
-			location, err := postScalingRequest(client, deploymentID, nodeName, instancesDelta)
+			location, err := postScalingRequest(client, deploymentID, nodeName, instancesDelta, batchSize, pauseBetweenBatches)
 			if err != nil {
 				return err
 			}
@@ -73,12 +75,14 @@ func init() {
 	}
 	scaleCmd.PersistentFlags().StringVarP(&nodeName, "node", "n", "", "The name of the node that should be scaled.")
 	scaleCmd.PersistentFlags().Int32VarP(&instancesDelta, "delta", "d", 0, "The non-zero number of instance to add (if > 0) or remove (if < 0).")
+	scaleCmd.PersistentFlags().IntVarP(&batchSize, "batch-size", "", 0, "Maximum number of instances processed at once. Instances are processed by batches and the scaling stops on the first failing batch.")
+	scaleCmd.PersistentFlags().StringVarP(&pauseBetweenBatches, "pause-between-batches", "", "", "Delay between two batches of instances (ex: 30s).")
 	scaleCmd.PersistentFlags().BoolVarP(&shouldStreamLogs, "stream-logs", "l", false, "Stream logs after issuing the scaling request. In this mode logs can't be filtered, to use this feature see the \"log\" command.")
 	scaleCmd.PersistentFlags().BoolVarP(&shouldStreamEvents, "stream-events", "e", false, "Stream events after  issuing the scaling request.")
 	DeploymentsCmd.AddCommand(scaleCmd)
 }
 
-func postScalingRequest(client *httputil.YorcClient, deploymentID, nodeName string, instancesDelta int32) (string, error) {
+func postScalingRequest(client *httputil.YorcClient, deploymentID, nodeName string, instancesDelta int32, batchSize int, pauseBetweenBatches string) (string, error) {
 	request, err := client.NewRequest("POST", path.Join("/deployments", deploymentID, "scale", nodeName), nil)
 	if err != nil {
 		httputil.ErrExit(errors.Wrap(err, httputil.YorcAPIDefaultErrorMsg))
@@ -86,6 +90,12 @@ func postScalingRequest(client *httputil.YorcClient, deploymentID, nodeName stri
 
 	query := request.URL.Query()
 	query.Set("delta", strconv.Itoa(int(instancesDelta)))
+	if batchSize > 0 {
+		query.Set("batch_size", strconv.Itoa(batchSize))
+	}
+	if pauseBetweenBatches != "" {
+		query.Set("pause_between_batches", pauseBetweenBatches)
+	}
 
 	request.URL.RawQuery = query.Encode()
 
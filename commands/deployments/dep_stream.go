@@ -0,0 +1,143 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ystia/yorc/v3/commands/httputil"
+	"github.com/ystia/yorc/v3/rest"
+)
+
+// sseMaxEntrySize is the maximum size of a single Server-Sent Event, log entries stored in Consul may be up to 512KB
+const sseMaxEntrySize = 1024 * 1024
+
+// entriesFilters holds the filters applied on events or logs by the Yorc server
+type entriesFilters struct {
+	node  string
+	task  string
+	level string
+}
+
+func (f entriesFilters) queryParams() string {
+	values := url.Values{}
+	if f.node != "" {
+		values.Set("node", f.node)
+	}
+	if f.task != "" {
+		values.Set("task", f.task)
+	}
+	if f.level != "" {
+		values.Set("level", f.level)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "&" + values.Encode()
+}
+
+// getLastIndex returns the current index of the given resource ("events" or "logs")
+func getLastIndex(client *httputil.YorcClient, deploymentID, resource string) uint64 {
+	var response *http.Response
+	var err error
+	if deploymentID != "" {
+		response, err = client.Head("/deployments/" + deploymentID + "/" + resource)
+		if err == nil {
+			httputil.HandleHTTPStatusCode(response, deploymentID, "deployment", http.StatusOK)
+		}
+	} else {
+		response, err = client.Head("/" + resource)
+	}
+	if err != nil {
+		httputil.ErrExit(err)
+	}
+	idxHd := response.Header.Get(rest.YorcIndexHeader)
+	if idxHd == "" {
+		fmt.Fprintf(os.Stderr, "Failed to get latest %s index from Yorc, %s will appear from the beginning.", resource, resource)
+		return 0
+	}
+	lastIdx, err := strconv.ParseUint(idxHd, 10, 64)
+	if err != nil {
+		httputil.ErrExit(err)
+	}
+	return lastIdx
+}
+
+// streamSSE consumes a Server-Sent Events endpoint of the Yorc REST API and calls handleFn for each received entry.
+//
+// If the connection is lost, the stream is resumed from the last received entry.
+func streamSSE(client *httputil.YorcClient, deploymentID, resource string, fromIndex uint64, filters entriesFilters, handleFn func(entry json.RawMessage)) {
+	path := "/" + resource
+	if deploymentID != "" {
+		path = "/deployments/" + deploymentID + path
+	}
+	path += "?index=" + strconv.FormatUint(fromIndex, 10) + filters.queryParams()
+	var lastEventID string
+	for {
+		request, err := client.NewRequest("GET", path, nil)
+		if err != nil {
+			httputil.ErrExit(err)
+		}
+		request.Header.Add("Accept", "text/event-stream")
+		if lastEventID != "" {
+			request.Header.Set("Last-Event-ID", lastEventID)
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			httputil.ErrExit(err)
+		}
+		httputil.HandleHTTPStatusCode(response, deploymentID, "deployment", http.StatusOK)
+
+		scanner := bufio.NewScanner(response.Body)
+		scanner.Buffer(make([]byte, 64*1024), sseMaxEntrySize)
+		var data bytes.Buffer
+		var pendingID string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				// End of an event, dispatch it
+				if data.Len() > 0 {
+					handleFn(json.RawMessage(data.String()))
+					data.Reset()
+				}
+				if pendingID != "" {
+					lastEventID = pendingID
+					pendingID = ""
+				}
+			case strings.HasPrefix(line, ":"):
+				// Comment used as a keep-alive
+			case strings.HasPrefix(line, "id:"):
+				pendingID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			case strings.HasPrefix(line, "data:"):
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+		}
+		response.Body.Close()
+		if err = scanner.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "Stream interrupted: %v, reconnecting...\n", err)
+		}
+		time.Sleep(time.Second)
+	}
+}
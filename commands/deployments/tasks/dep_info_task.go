@@ -141,7 +141,7 @@ func getColoredTaskStepStatus(colorize bool, status string) string {
 		return color.New(color.FgHiRed, color.Bold).SprintFunc()(status)
 	case "canceled", "running":
 		return color.New(color.FgHiYellow, color.Bold).SprintFunc()(status)
-	case "done":
+	case "done", "skipped":
 		return color.New(color.FgHiGreen, color.Bold).SprintFunc()(status)
 	default:
 		return status
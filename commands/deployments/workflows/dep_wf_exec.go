@@ -15,21 +15,29 @@
 package workflows
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/ystia/yorc/v3/commands/deployments"
 	"github.com/ystia/yorc/v3/commands/httputil"
+	"github.com/ystia/yorc/v3/tasks/workflow"
 )
 
 func init() {
 	var shouldStreamLogs bool
 	var shouldStreamEvents bool
 	var continueOnError bool
+	var dryRun bool
+	var batchSize int
+	var pauseBetweenBatches string
 	var workflowName string
 	var wfExecCmd = &cobra.Command{
 		Use:     "execute <id>",
@@ -47,13 +55,25 @@ func init() {
 				return errors.New("Missing mandatory \"workflow-name\" parameter")
 			}
 			url := fmt.Sprintf("/deployments/%s/workflows/%s", args[0], workflowName)
-			if continueOnError {
+			if dryRun {
+				url = url + "?dry_run=true"
+			} else if continueOnError {
 				url = url + "?continueOnError"
 			}
 			request, err := client.NewRequest("POST", url, nil)
 			if err != nil {
 				httputil.ErrExit(err)
 			}
+			if !dryRun {
+				query := request.URL.Query()
+				if batchSize > 0 {
+					query.Set("batch_size", strconv.Itoa(batchSize))
+				}
+				if pauseBetweenBatches != "" {
+					query.Set("pause_between_batches", pauseBetweenBatches)
+				}
+				request.URL.RawQuery = query.Encode()
+			}
 			request.Header.Add("Content-Type", "application/json")
 			response, err := client.Do(request)
 			defer response.Body.Close()
@@ -61,6 +81,20 @@ func init() {
 				httputil.ErrExit(err)
 			}
 			ids := args[0] + "/" + workflowName
+			if dryRun {
+				httputil.HandleHTTPStatusCode(response, ids, "deployment/workflow", http.StatusOK)
+				var plan workflow.Plan
+				body, err := ioutil.ReadAll(response.Body)
+				if err != nil {
+					httputil.ErrExit(err)
+				}
+				err = json.Unmarshal(body, &plan)
+				if err != nil {
+					httputil.ErrExit(err)
+				}
+				printPlan(plan)
+				return nil
+			}
 			httputil.HandleHTTPStatusCode(response, ids, "deployment/workflow", http.StatusAccepted, http.StatusCreated)
 
 			fmt.Println("New task ", path.Base(response.Header.Get("Location")), " created to execute ", workflowName)
@@ -76,7 +110,61 @@ func init() {
 	}
 	wfExecCmd.PersistentFlags().StringVarP(&workflowName, "workflow-name", "w", "", "The workflows name")
 	wfExecCmd.PersistentFlags().BoolVarP(&continueOnError, "continue-on-error", "", false, "By default if an error occurs in a step of a workflow then other running steps are cancelled and the workflow is stopped. This flag allows to continue to the next steps even if an error occurs.")
+	wfExecCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "", false, "Do not execute the workflow but display what it would do: steps in execution order, executors that would be used and resolved operations inputs (secrets are redacted).")
+	wfExecCmd.PersistentFlags().IntVarP(&batchSize, "batch-size", "", 0, "Maximum number of instances of a node processed at once by each step of the workflow. Instances are processed by batches and the workflow stops on the first failing batch. This overrides the batch_size defined on workflow steps.")
+	wfExecCmd.PersistentFlags().StringVarP(&pauseBetweenBatches, "pause-between-batches", "", "", "Delay between two batches of instances (ex: 30s). This overrides the pause_between_batches defined on workflow steps.")
 	wfExecCmd.PersistentFlags().BoolVarP(&shouldStreamLogs, "stream-logs", "l", false, "Stream logs after triggering a workflow. In this mode logs can't be filtered, to use this feature see the \"log\" command.")
 	wfExecCmd.PersistentFlags().BoolVarP(&shouldStreamEvents, "stream-events", "e", false, "Stream events after triggering a workflow.")
 	workflowsCmd.AddCommand(wfExecCmd)
 }
+
+func printPlan(plan workflow.Plan) {
+	fmt.Printf("Execution plan of workflow %s on deployment %s:\n", plan.WorkflowName, plan.DeploymentID)
+	for i, step := range plan.Steps {
+		fmt.Printf("  %d. Step %s", i+1, step.Name)
+		if step.IsOnFailurePath {
+			fmt.Print(" (on failure)")
+		} else if step.IsOnCancelPath {
+			fmt.Print(" (on cancel)")
+		}
+		fmt.Println(":")
+		if step.Target != "" {
+			fmt.Println("    Target:", step.Target)
+		}
+		if step.TargetRelationship != "" {
+			fmt.Println("    Target Relationship:", step.TargetRelationship)
+		}
+		if len(step.Instances) > 0 {
+			fmt.Println("    Instances:", strings.Join(step.Instances, ", "))
+		}
+		if len(step.Previous) > 0 {
+			fmt.Println("    After:", strings.Join(step.Previous, ", "))
+		}
+		fmt.Println("    Activities:")
+		for _, activity := range step.Activities {
+			fmt.Printf("      - %s: %s\n", activity.Type, activity.Value)
+			if activity.Skipped {
+				fmt.Println("        Skipped: operation not implemented")
+			}
+			if activity.ImplementationArtifact != "" {
+				fmt.Println("        Implementation Artifact:", activity.ImplementationArtifact)
+			}
+			if activity.Executor != nil {
+				fmt.Printf("        Executor: %s (from %s)\n", activity.Executor.Match, activity.Executor.Origin)
+			}
+			if len(activity.Inputs) > 0 {
+				fmt.Println("        Inputs:")
+				for _, input := range activity.Inputs {
+					if input.InstanceName != "" {
+						fmt.Printf("          %s (%s): %q\n", input.Name, input.InstanceName, input.Value)
+					} else {
+						fmt.Printf("          %s: %q\n", input.Name, input.Value)
+					}
+				}
+			}
+			if activity.Error != "" {
+				fmt.Println("        Error:", activity.Error)
+			}
+		}
+	}
+}
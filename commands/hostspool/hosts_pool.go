@@ -43,6 +43,7 @@ const (
 func init() {
 	commands.RootCmd.AddCommand(hostsPoolCmd)
 	commands.ConfigureYorcClientCommand(hostsPoolCmd, hpViper, &cfgFile, &noColor)
+	hostsPoolCmd.PersistentFlags().StringVarP(&poolName, "pool", "", "default", "Name of the hosts pool")
 }
 
 var hpViper = viper.New()
@@ -50,6 +51,7 @@ var clientConfig config.Client
 
 var noColor bool
 var cfgFile string
+var poolName string
 
 var hostsPoolCmd = &cobra.Command{
 	Use:           "hostspool",
@@ -79,7 +79,7 @@ func init() {
 				jsonParam = string(tmp)
 			}
 
-			request, err := client.NewRequest("PUT", "/hosts_pool/"+args[0], bytes.NewBuffer([]byte(jsonParam)))
+			request, err := client.NewRequest("PUT", "/hosts_pool/"+poolName+"/"+args[0], bytes.NewBuffer([]byte(jsonParam)))
 			if err != nil {
 				httputil.ErrExit(err)
 			}
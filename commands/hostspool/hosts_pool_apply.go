@@ -92,7 +92,7 @@ func init() {
 				httputil.ErrExit(err)
 			}
 
-			request, err := client.NewRequest("GET", "/hosts_pool", nil)
+			request, err := client.NewRequest("GET", "/hosts_pool/"+poolName, nil)
 			if err != nil {
 				httputil.ErrExit(err)
 			}
@@ -147,7 +147,7 @@ func init() {
 						// This is an update
 						//  Check if there is any change before registering the
 						// need to update
-						if host.Connection != newDef.Connection ||
+						if !reflect.DeepEqual(host.Connection, newDef.Connection) ||
 							!reflect.DeepEqual(host.Labels, newDef.Labels) {
 							update = true
 							hostsImpacted = append(hostsImpacted, host.Name)
@@ -227,7 +227,7 @@ func init() {
 			// Proceed to the change
 
 			bArray, err := json.Marshal(&hostsPoolRequest)
-			request, err = client.NewRequest("POST", "/hosts_pool",
+			request, err = client.NewRequest("POST", "/hosts_pool/"+poolName,
 				bytes.NewBuffer(bArray))
 			if err != nil {
 				httputil.ErrExit(err)
@@ -268,7 +268,7 @@ func init() {
 				"Name", "Connection", "Status", "Message")
 			for _, name := range hostsImpacted {
 
-				request, err := client.NewRequest("GET", "/hosts_pool/"+name, nil)
+				request, err := client.NewRequest("GET", "/hosts_pool/"+poolName+"/"+name, nil)
 				request.Header.Add("Accept", "application/json")
 				if err != nil {
 					httputil.ErrExit(err)
@@ -327,9 +327,14 @@ func toPrintableLabels(labels map[string]string) string {
 // Returns a printable value of a connection, including empty fields
 func toPrintableConnection(connection hostspool.Connection) string {
 
-	return "user: " + connection.User + ",password: " + connection.Password +
+	printable := "user: " + connection.User + ",password: " + connection.Password +
 		",private key:" + connection.PrivateKey + ",host: " +
 		connection.Host + ",port: " + strconv.FormatUint(connection.Port, 10)
+	for i, jumpHost := range connection.JumpHosts {
+		printable += ",jump host " + strconv.Itoa(i+1) + ": " + jumpHost.User + "@" +
+			jumpHost.Host + ":" + strconv.FormatUint(jumpHost.Port, 10)
+	}
+	return printable
 }
 
 // Add rows to a table, for both old and new values
@@ -37,7 +37,7 @@ func init() {
 				httputil.ErrExit(err)
 			}
 			for i := range args {
-				request, err := client.NewRequest("DELETE", "/hosts_pool/"+args[i], nil)
+				request, err := client.NewRequest("DELETE", "/hosts_pool/"+poolName+"/"+args[i], nil)
 				if err != nil {
 					httputil.ErrExit(err)
 				}
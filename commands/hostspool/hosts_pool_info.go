@@ -44,7 +44,7 @@ func init() {
 				httputil.ErrExit(err)
 			}
 
-			request, err := client.NewRequest("GET", "/hosts_pool/"+args[0], nil)
+			request, err := client.NewRequest("GET", "/hosts_pool/"+poolName+"/"+args[0], nil)
 			request.Header.Add("Accept", "application/json")
 			if err != nil {
 				httputil.ErrExit(err)
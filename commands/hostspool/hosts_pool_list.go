@@ -40,7 +40,7 @@ func init() {
 			if err != nil {
 				httputil.ErrExit(err)
 			}
-			request, err := client.NewRequest("GET", "/hosts_pool", nil)
+			request, err := client.NewRequest("GET", "/hosts_pool/"+poolName, nil)
 			if err != nil {
 				httputil.ErrExit(err)
 			}
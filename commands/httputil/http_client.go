@@ -113,17 +113,45 @@ func GetClient(cc config.Client) (*YorcClient, error) {
 		}
 		return &YorcClient{
 			baseURL: "https://" + yorcAPI,
-			Client:  &http.Client{Transport: tr},
+			Client:  &http.Client{Transport: newTokenTransport(tr, cc.Token)},
 		}, nil
 	}
 
 	return &YorcClient{
 		baseURL: "http://" + yorcAPI,
-		Client:  &http.Client{},
+		Client:  &http.Client{Transport: newTokenTransport(http.DefaultTransport, cc.Token)},
 	}, nil
 
 }
 
+// tokenTransport is an http.RoundTripper that authenticates requests using a bearer token
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+func newTokenTransport(base http.RoundTripper, token string) http.RoundTripper {
+	if token == "" {
+		return base
+	}
+	return &tokenTransport{base: base, token: token}
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTrippers should not modify the given request
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(r)
+}
+
 // HandleHTTPStatusCode handles Yorc HTTP status code and displays error if needed
 func HandleHTTPStatusCode(response *http.Response, resourceID string, resourceType string, expectedStatusCodes ...int) {
 	HandleHTTPStatusCodeWithCustomizedErrorMessage(
@@ -155,6 +183,8 @@ func HandleHTTPStatusCodeWithCustomizedErrorMessage(
 		case http.StatusNoContent:
 			// same point as above
 			okExit(fmt.Sprintf("No %s", resourceType))
+		case http.StatusUnauthorized:
+			ErrExit(errors.New("Authentication required: provide a valid token using the --token flag or the YORC_TOKEN environment variable"))
 		default:
 			errs := getRestErrors(response.Body)
 			errMsg := getCustomizedErrorMessage(errs, customizedErrorMessages)
@@ -72,6 +72,7 @@ var ansibleConfiguration = map[string]interface{}{
 	"ansible.archive_artifacts":            config.DefaultArchiveArtifacts,
 	"ansible.cache_facts":                  config.DefaultCacheFacts,
 	"ansible.keep_generated_recipes":       false,
+	"ansible.native_ssh_executor":          false,
 	"ansible.job_monitoring_time_interval": config.DefaultAnsibleJobMonInterval,
 }
 
@@ -297,6 +298,7 @@ func setConfig() {
 	serverCmd.PersistentFlags().Bool("ansible_archive_artifacts", config.DefaultArchiveArtifacts, "Define wether artifacts should be ./archived before being copied on remote nodes (requires tar to be installed on remote nodes).")
 	serverCmd.PersistentFlags().Bool("ansible_cache_facts", config.DefaultCacheFacts, "Define wether Ansible facts (useful variables about remote hosts) should be cached.")
 	serverCmd.PersistentFlags().Bool("ansible_keep_generated_recipes", false, "Define if Yorc should not delete generated Ansible recipes")
+	serverCmd.PersistentFlags().Bool("ansible_native_ssh_executor", false, "Define if Bash and Python operations should be run directly over SSH instead of using Ansible")
 	serverCmd.PersistentFlags().Duration("ansible_job_monitoring_time_interval", config.DefaultAnsibleJobMonInterval, "Default duration for monitoring time interval for jobs handled by Ansible")
 
 	//Flags definition for Terraform
@@ -69,6 +69,9 @@ const DefaultWfStepGracefulTerminationTimeout = 2 * time.Minute
 // DefaultAnsibleJobMonInterval is the default monitoring interval for Jobs handled by Ansible
 const DefaultAnsibleJobMonInterval = 15 * time.Second
 
+// DefaultJWTRolesClaim is the default name of the JWT claim holding the roles of an authenticated user
+const DefaultJWTRolesClaim = "roles"
+
 // Configuration holds config information filled by Cobra and Viper (see commands package for more information)
 type Configuration struct {
 	Ansible                          Ansible               `yaml:"ansible,omitempty" mapstructure:"ansible"`
@@ -92,6 +95,10 @@ type Configuration struct {
 	ServerID                         string                `yaml:"server_id,omitempty" mapstructure:"server_id"`
 	Terraform                        Terraform             `yaml:"terraform,omitempty" mapstructure:"terraform"`
 	DisableSSHAgent                  bool                  `yaml:"disable_ssh_agent,omitempty" mapstructure:"disable_ssh_agent"`
+	Auth                             Auth                  `yaml:"auth,omitempty" mapstructure:"auth"`
+	Autoscaling                      Autoscaling           `yaml:"autoscaling,omitempty" mapstructure:"autoscaling"`
+	Audit                            Audit                 `yaml:"audit,omitempty" mapstructure:"audit"`
+	Monitoring                       Monitoring            `yaml:"monitoring,omitempty" mapstructure:"monitoring"`
 }
 
 // DockerSandbox holds the configuration for a docker sandbox
@@ -127,6 +134,7 @@ type Ansible struct {
 	KeepGeneratedRecipes    bool                         `yaml:"keep_generated_recipes,omitempty" mapstructure:"keep_generated_recipes" json:"keep_generated_recipes,omitempty"`
 	ArchiveArtifacts        bool                         `yaml:"archive_artifacts,omitempty" mapstructure:"archive_artifacts" json:"archive_artifacts,omitempty"`
 	CacheFacts              bool                         `yaml:"cache_facts,omitempty" mapstructure:"cache_facts" json:"cache_facts,omitempty"`
+	NativeSSHExecutor       bool                         `yaml:"native_ssh_executor,omitempty" mapstructure:"native_ssh_executor" json:"native_ssh_executor,omitempty"`
 	HostedOperations        HostedOperations             `yaml:"hosted_operations,omitempty" mapstructure:"hosted_operations" json:"hosted_operations,omitempty"`
 	JobsChecksPeriod        time.Duration                `yaml:"job_monitoring_time_interval,omitempty" mapstructure:"job_monitoring_time_interval" json:"job_monitoring_time_interval,omitempty"`
 	Config                  map[string]map[string]string `yaml:"config,omitempty" mapstructure:"config"`
@@ -168,6 +176,52 @@ type Terraform struct {
 	KeepGeneratedFiles               bool   `yaml:"keep_generated_files,omitempty" mapstructure:"keep_generated_files"`
 }
 
+// Auth holds the configuration of the REST API authentication and authorization
+type Auth struct {
+	Tokens        []AuthToken `yaml:"tokens,omitempty" mapstructure:"tokens"`
+	JWKSFile      string      `yaml:"jwks_file,omitempty" mapstructure:"jwks_file"`
+	JWTIssuer     string      `yaml:"jwt_issuer,omitempty" mapstructure:"jwt_issuer"`
+	JWTAudience   string      `yaml:"jwt_audience,omitempty" mapstructure:"jwt_audience"`
+	JWTRolesClaim string      `yaml:"jwt_roles_claim,omitempty" mapstructure:"jwt_roles_claim"`
+	// WebSocketAllowedOrigins lists the origins allowed to open WebSockets in addition to the Yorc server one
+	WebSocketAllowedOrigins []string `yaml:"websocket_allowed_origins,omitempty" mapstructure:"websocket_allowed_origins"`
+}
+
+// Autoscaling holds the configuration of the metric-driven autoscaling of deployments nodes
+type Autoscaling struct {
+	PrometheusAddress  string        `yaml:"prometheus_address,omitempty" mapstructure:"prometheus_address"`
+	EvaluationInterval time.Duration `yaml:"evaluation_interval,omitempty" mapstructure:"evaluation_interval"`
+	QueryTimeout       time.Duration `yaml:"query_timeout,omitempty" mapstructure:"query_timeout"`
+}
+
+// Audit holds the configuration of the audit trail of REST API actions
+type Audit struct {
+	Retention     time.Duration `yaml:"retention,omitempty" mapstructure:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval,omitempty" mapstructure:"purge_interval"`
+}
+
+// Monitoring holds the configuration of the monitoring of deployed applications
+type Monitoring struct {
+	OrchestratorCommandChecksAllowed bool `yaml:"orchestrator_command_checks_allowed,omitempty" mapstructure:"orchestrator_command_checks_allowed"`
+}
+
+// Enabled returns true if at least one authentication method is configured
+func (a Auth) Enabled() bool {
+	return len(a.Tokens) > 0 || a.JWKSFile != ""
+}
+
+// AuthToken is a static API token granting a role to its bearer
+type AuthToken struct {
+	Name  string `yaml:"name,omitempty" mapstructure:"name"`
+	Token string `yaml:"token" mapstructure:"token"`
+	Role  string `yaml:"role" mapstructure:"role"`
+}
+
+// Format implements fmt.Formatter to provide a custom formatter that does not leak the token value.
+func (t AuthToken) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, "{Name:%s Token:****** Role:%s}", t.Name, t.Role)
+}
+
 // DynamicMap allows to store configuration parameters that are not known in advance.
 // This is particularly useful when configuration parameters may be defined in a plugin such for infrastructures.
 //
@@ -304,4 +358,5 @@ type Client struct {
 	CertFile      string `mapstructure:"cert_file"`
 	CAFile        string `mapstructure:"ca_file"`
 	CAPath        string `mapstructure:"ca_path"`
+	Token         string `mapstructure:"token"`
 }
@@ -323,3 +323,35 @@ func testGetCapabilityProperties(t *testing.T, kv *api.KV) {
 	require.NotNil(t, value)
 	require.Equal(t, "PRIVATE", value.RawString())
 }
+
+func testInstanceEndpointJumpHosts(t *testing.T, kv *api.KV) {
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/capabilities_properties.yaml")
+	require.Nil(t, err)
+
+	jumpHosts, err := GetInstanceEndpointJumpHosts(kv, deploymentID, "Front", "0", "centos")
+	require.Nil(t, err)
+	require.Len(t, jumpHosts, 0)
+
+	expected := []JumpHost{
+		{Host: "bastion1", Port: 22, User: "root", Password: "secret"},
+		{Host: "bastion2", Port: 2222, User: "jump", PrivateKey: "/path/to/key.pem"},
+	}
+	err = SetInstanceCapabilityAttributeComplex(deploymentID, "Front", "0", "endpoint", "credentials", map[string]interface{}{
+		"user":       "centos",
+		"jump_hosts": JumpHostsCredentials(expected),
+	})
+	require.Nil(t, err)
+	jumpHosts, err = GetInstanceEndpointJumpHosts(kv, deploymentID, "Front", "0", "centos")
+	require.Nil(t, err)
+	require.Equal(t, expected, jumpHosts)
+
+	// Users default to the given user and ports to 22
+	err = SetInstanceCapabilityAttributeComplex(deploymentID, "Front", "1", "endpoint", "credentials", map[string]interface{}{
+		"jump_hosts": []interface{}{map[string]interface{}{"host": "bastion3"}},
+	})
+	require.Nil(t, err)
+	jumpHosts, err = GetInstanceEndpointJumpHosts(kv, deploymentID, "Front", "1", "centos")
+	require.Nil(t, err)
+	require.Equal(t, []JumpHost{{Host: "bastion3", Port: 22, User: "centos"}}, jumpHosts)
+}
@@ -0,0 +1,255 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+// GetWorkflowPreconditions returns the preconditions of a workflow
+func GetWorkflowPreconditions(kv *api.KV, deploymentID, workflowName string) ([]tosca.Precondition, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "workflows", url.QueryEscape(workflowName), "preconditions"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, nil
+	}
+	var preconditions []tosca.Precondition
+	err = json.Unmarshal(kvp.Value, &preconditions)
+	return preconditions, errors.Wrapf(err, "failed to read preconditions of workflow %q", workflowName)
+}
+
+// storeConditions stores conditions as json under the given key
+func storeConditions(consulStore consulutil.ConsulStore, key string, conditions interface{}) {
+	// Conditions are only made of strings, slices and maps so marshaling can't fail
+	b, _ := json.Marshal(conditions)
+	consulStore.StoreConsulKey(key, b)
+}
+
+// checkWorkflowsConditions checks that workflows preconditions and steps filters refer to existing nodes
+func checkWorkflowsConditions(topology tosca.Topology) error {
+	for wfName, wf := range topology.TopologyTemplate.Workflows {
+		for _, p := range wf.Preconditions {
+			if p.Target == "" {
+				if tosca.HasAttributesAssertions(p.Condition) {
+					return errors.Errorf("preconditions of workflow %q define assertions on attributes without target", wfName)
+				}
+				continue
+			}
+			if _, ok := topology.TopologyTemplate.NodeTemplates[p.Target]; !ok {
+				return errors.Errorf("preconditions of workflow %q refer to an unknown node %q", wfName, p.Target)
+			}
+		}
+		for stepName, step := range wf.Steps {
+			if step.Target == "" && tosca.HasAttributesAssertions(step.Filter) {
+				return errors.Errorf("filter of step %q of workflow %q defines assertions on attributes without target", stepName, wfName)
+			}
+		}
+	}
+	return nil
+}
+
+// EvaluateConditions checks if the given condition clauses are met for all the given instances of a node
+//
+// Conditions not referring to attributes of the node may be evaluated with an empty node name.
+func EvaluateConditions(kv *api.KV, deploymentID, nodeName string, instances []string, clauses []tosca.ConditionClause) (bool, error) {
+	if len(instances) == 0 {
+		instances = []string{""}
+	}
+	for _, instanceName := range instances {
+		met, err := evaluateConditionClauses(kv, deploymentID, nodeName, instanceName, clauses)
+		if err != nil || !met {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// evaluateConditionClauses checks that all the given clauses are met
+func evaluateConditionClauses(kv *api.KV, deploymentID, nodeName, instanceName string, clauses []tosca.ConditionClause) (bool, error) {
+	for _, c := range clauses {
+		met, err := evaluateConditionClause(kv, deploymentID, nodeName, instanceName, c)
+		if err != nil || !met {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func evaluateConditionClause(kv *api.KV, deploymentID, nodeName, instanceName string, clause tosca.ConditionClause) (bool, error) {
+	for attrName, constraints := range clause.Attributes {
+		if nodeName == "" || instanceName == "" {
+			return false, errors.Errorf("can't check attribute %q without a target node instance", attrName)
+		}
+		value, err := GetInstanceAttributeValue(kv, deploymentID, nodeName, instanceName, attrName)
+		if err != nil {
+			return false, err
+		}
+		var s string
+		if value != nil {
+			s = value.RawString()
+		}
+		met, err := matchConstraints(s, constraints)
+		if err != nil || !met {
+			return false, errors.Wrapf(err, "failed to check attribute %q", attrName)
+		}
+	}
+	if clause.Value != "" {
+		value, err := resolveConditionValue(kv, deploymentID, nodeName, instanceName, clause.Value)
+		if err != nil {
+			return false, err
+		}
+		met, err := matchConstraints(value, clause.Constraints)
+		if err != nil || !met {
+			return false, errors.Wrapf(err, "failed to check value %q", clause.Value)
+		}
+	}
+	if len(clause.And) > 0 {
+		met, err := evaluateConditionClauses(kv, deploymentID, nodeName, instanceName, clause.And)
+		if err != nil || !met {
+			return false, err
+		}
+	}
+	if len(clause.Or) > 0 {
+		var oneMet bool
+		for _, c := range clause.Or {
+			met, err := evaluateConditionClause(kv, deploymentID, nodeName, instanceName, c)
+			if err != nil {
+				return false, err
+			}
+			if met {
+				oneMet = true
+				break
+			}
+		}
+		if !oneMet {
+			return false, nil
+		}
+	}
+	// not is met if none of its clauses is met
+	for _, c := range clause.Not {
+		met, err := evaluateConditionClause(kv, deploymentID, nodeName, instanceName, c)
+		if err != nil || met {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// resolveConditionValue resolves the textual representation of a value assignment used in a condition
+func resolveConditionValue(kv *api.KV, deploymentID, nodeName, instanceName, valueAssignment string) (string, error) {
+	va := &tosca.ValueAssignment{}
+	err := yaml.Unmarshal([]byte(valueAssignment), va)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse condition value %q", valueAssignment)
+	}
+	if va.Type != tosca.ValueAssignmentFunction {
+		return va.GetLiteral(), nil
+	}
+	r := resolver(kv, deploymentID).context(withNodeName(nodeName), withInstanceName(instanceName))
+	value, err := r.resolveFunction(va.GetFunction())
+	if err != nil || value == nil {
+		return "", err
+	}
+	return value.RawString(), nil
+}
+
+// matchConstraints checks if a value meets all the given constraints
+func matchConstraints(value string, constraints []tosca.ConstraintClause) (bool, error) {
+	for _, c := range constraints {
+		met, err := matchConstraint(value, c)
+		if err != nil || !met {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchConstraint(value string, c tosca.ConstraintClause) (bool, error) {
+	if len(c.Values) == 0 {
+		return false, errors.Errorf("missing value for constraint %q", c.Operator)
+	}
+	switch c.Operator {
+	case tosca.ConstraintEqual:
+		return compareConditionValues(value, c.Values[0]) == 0, nil
+	case tosca.ConstraintGreaterThan:
+		return compareConditionValues(value, c.Values[0]) > 0, nil
+	case tosca.ConstraintGreaterOrEqual:
+		return compareConditionValues(value, c.Values[0]) >= 0, nil
+	case tosca.ConstraintLessThan:
+		return compareConditionValues(value, c.Values[0]) < 0, nil
+	case tosca.ConstraintLessOrEqual:
+		return compareConditionValues(value, c.Values[0]) <= 0, nil
+	case tosca.ConstraintInRange:
+		if len(c.Values) != 2 {
+			return false, errors.Errorf("constraint %q expects 2 values", c.Operator)
+		}
+		return compareConditionValues(value, c.Values[0]) >= 0 && compareConditionValues(value, c.Values[1]) <= 0, nil
+	case tosca.ConstraintValidValues:
+		for _, v := range c.Values {
+			if compareConditionValues(value, v) == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case tosca.ConstraintLength, tosca.ConstraintMinLength, tosca.ConstraintMaxLength:
+		l, err := strconv.Atoi(c.Values[0])
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid length for constraint %q", c.Operator)
+		}
+		vl := utf8.RuneCountInString(value)
+		switch c.Operator {
+		case tosca.ConstraintMinLength:
+			return vl >= l, nil
+		case tosca.ConstraintMaxLength:
+			return vl <= l, nil
+		}
+		return vl == l, nil
+	case tosca.ConstraintPattern:
+		// The whole value should match the pattern
+		return regexp.MatchString("^(?:"+c.Values[0]+")$", value)
+	}
+	return false, errors.Errorf("unsupported constraint operator %q", c.Operator)
+}
+
+// compareConditionValues compares values numerically if both of them are numbers and lexically otherwise
+func compareConditionValues(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
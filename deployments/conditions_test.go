@@ -0,0 +1,98 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/testutil"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+func TestMatchConstraint(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		value      string
+		constraint tosca.ConstraintClause
+		want       bool
+	}{
+		{"EqualString", "prod", tosca.ConstraintClause{Operator: tosca.ConstraintEqual, Values: []string{"prod"}}, true},
+		{"EqualNumbers", "1.0", tosca.ConstraintClause{Operator: tosca.ConstraintEqual, Values: []string{"1"}}, true},
+		{"NotEqual", "dev", tosca.ConstraintClause{Operator: tosca.ConstraintEqual, Values: []string{"prod"}}, false},
+		{"GreaterThanNumbers", "10", tosca.ConstraintClause{Operator: tosca.ConstraintGreaterThan, Values: []string{"9"}}, true},
+		{"GreaterOrEqual", "9", tosca.ConstraintClause{Operator: tosca.ConstraintGreaterOrEqual, Values: []string{"9"}}, true},
+		{"LessThan", "9", tosca.ConstraintClause{Operator: tosca.ConstraintLessThan, Values: []string{"9"}}, false},
+		{"LessOrEqualStrings", "abc", tosca.ConstraintClause{Operator: tosca.ConstraintLessOrEqual, Values: []string{"abd"}}, true},
+		{"InRange", "5", tosca.ConstraintClause{Operator: tosca.ConstraintInRange, Values: []string{"1", "5"}}, true},
+		{"OutOfRange", "6", tosca.ConstraintClause{Operator: tosca.ConstraintInRange, Values: []string{"1", "5"}}, false},
+		{"ValidValues", "test", tosca.ConstraintClause{Operator: tosca.ConstraintValidValues, Values: []string{"dev", "test"}}, true},
+		{"InvalidValues", "prod", tosca.ConstraintClause{Operator: tosca.ConstraintValidValues, Values: []string{"dev", "test"}}, false},
+		{"Length", "été", tosca.ConstraintClause{Operator: tosca.ConstraintLength, Values: []string{"3"}}, true},
+		{"MinLength", "ab", tosca.ConstraintClause{Operator: tosca.ConstraintMinLength, Values: []string{"3"}}, false},
+		{"MaxLength", "ab", tosca.ConstraintClause{Operator: tosca.ConstraintMaxLength, Values: []string{"3"}}, true},
+		{"Pattern", "node-1", tosca.ConstraintClause{Operator: tosca.ConstraintPattern, Values: []string{"node-[0-9]+"}}, true},
+		{"PatternMatchesWholeValue", "my-node-1", tosca.ConstraintClause{Operator: tosca.ConstraintPattern, Values: []string{"node-[0-9]+"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchConstraint(tt.value, tt.constraint)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func testConditions(t *testing.T, kv *api.KV) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	err := StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/conditions.yaml")
+	require.NoError(t, err)
+
+	wf, err := ReadWorkflow(kv, deploymentID, "install")
+	require.NoError(t, err)
+	require.Len(t, wf.Preconditions, 1)
+	require.Len(t, wf.Steps["ConditionalNode_start"].Filter, 1)
+	require.Len(t, wf.Steps["Compute_install"].Filter, 0)
+
+	met, err := EvaluateConditions(kv, deploymentID, wf.Preconditions[0].Target, nil, wf.Preconditions[0].Condition)
+	require.NoError(t, err)
+	require.True(t, met)
+
+	filter := wf.Steps["ConditionalNode_start"].Filter
+	err = SetInstanceAttribute(deploymentID, "ConditionalNode", "0", "state", "created")
+	require.NoError(t, err)
+	err = SetInstanceAttribute(deploymentID, "ConditionalNode", "1", "state", "created")
+	require.NoError(t, err)
+	met, err = EvaluateConditions(kv, deploymentID, "ConditionalNode", []string{"0", "1"}, filter)
+	require.NoError(t, err)
+	require.True(t, met)
+
+	// Conditions should be met by all instances
+	err = SetInstanceAttribute(deploymentID, "ConditionalNode", "1", "state", "started")
+	require.NoError(t, err)
+	met, err = EvaluateConditions(kv, deploymentID, "ConditionalNode", []string{"0", "1"}, filter)
+	require.NoError(t, err)
+	require.False(t, met)
+
+	_, err = EvaluateConditions(kv, deploymentID, "", nil, filter)
+	require.Error(t, err, "attributes assertions require a node instance")
+
+	err = StoreDeploymentDefinition(context.Background(), kv, deploymentID+"Invalid", "testdata/conditions_invalid.yaml")
+	require.Error(t, err)
+}
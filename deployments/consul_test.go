@@ -72,6 +72,9 @@ func TestRunConsulDeploymentsPackageTests(t *testing.T) {
 		t.Run("testGetCapabilityProperties", func(t *testing.T) {
 			testGetCapabilityProperties(t, kv)
 		})
+		t.Run("testInstanceEndpointJumpHosts", func(t *testing.T) {
+			testInstanceEndpointJumpHosts(t, kv)
+		})
 		t.Run("testImportTopologyTemplate", func(t *testing.T) {
 			testImportTopologyTemplate(t, kv)
 		})
@@ -105,5 +108,14 @@ func TestRunConsulDeploymentsPackageTests(t *testing.T) {
 		t.Run("testTopologyUpdate", func(t *testing.T) {
 			testTopologyUpdate(t, kv)
 		})
+		t.Run("testTopologyUpdateRollback", func(t *testing.T) {
+			testTopologyUpdateRollback(t, kv)
+		})
+		t.Run("testExecutionPolicies", func(t *testing.T) {
+			testExecutionPolicies(t, kv)
+		})
+		t.Run("testConditions", func(t *testing.T) {
+			testConditions(t, kv)
+		})
 	})
 }
@@ -60,19 +60,9 @@ func StoreDeploymentDefinition(ctx context.Context, kv *api.KV, deploymentID str
 		return handleDeploymentStatus(ctx, kv, deploymentID, err)
 	}
 
-	topology := tosca.Topology{}
-	definition, err := os.Open(defPath)
-	if err != nil {
-		return handleDeploymentStatus(ctx, kv, deploymentID, errors.Wrapf(err, "Failed to open definition file %q", defPath))
-	}
-	defBytes, err := ioutil.ReadAll(definition)
-	if err != nil {
-		return handleDeploymentStatus(ctx, kv, deploymentID, errors.Wrapf(err, "Failed to open definition file %q", defPath))
-	}
-
-	err = yaml.Unmarshal(defBytes, &topology)
+	topology, err := readTopologyFile(defPath)
 	if err != nil {
-		return handleDeploymentStatus(ctx, kv, deploymentID, errors.Wrapf(err, "Failed to unmarshal yaml definition for file %q", defPath))
+		return handleDeploymentStatus(ctx, kv, deploymentID, err)
 	}
 
 	err = storeDeployment(ctx, topology, deploymentID, filepath.Dir(defPath))
@@ -87,6 +77,26 @@ func StoreDeploymentDefinition(ctx context.Context, kv *api.KV, deploymentID str
 	return handleDeploymentStatus(ctx, kv, deploymentID, enhanceNodes(ctx, kv, deploymentID))
 }
 
+// readTopologyFile parses the TOSCA definition file at defPath
+func readTopologyFile(defPath string) (tosca.Topology, error) {
+	topology := tosca.Topology{}
+	definition, err := os.Open(defPath)
+	if err != nil {
+		return topology, errors.Wrapf(err, "Failed to open definition file %q", defPath)
+	}
+	defer definition.Close()
+	defBytes, err := ioutil.ReadAll(definition)
+	if err != nil {
+		return topology, errors.Wrapf(err, "Failed to open definition file %q", defPath)
+	}
+
+	err = yaml.Unmarshal(defBytes, &topology)
+	if err != nil {
+		return topology, errors.Wrapf(err, "Failed to unmarshal yaml definition for file %q", defPath)
+	}
+	return topology, nil
+}
+
 func handleDeploymentStatus(ctx context.Context, kv *api.KV, deploymentID string, err error) error {
 	if err != nil {
 		SetDeploymentStatus(ctx, kv, deploymentID, DEPLOYMENT_FAILED)
@@ -154,6 +164,12 @@ func storeTopology(ctx context.Context, topology tosca.Topology, deploymentID, t
 	if err := checkNestedWorkflows(topology); err != nil {
 		return err
 	}
+	if err := checkWorkflowsExecutionPolicies(topology); err != nil {
+		return err
+	}
+	if err := checkWorkflowsConditions(topology); err != nil {
+		return err
+	}
 
 	if isRootTopologyTemplate {
 		storeWorkflows(ctx, topology, deploymentID)
@@ -556,6 +572,17 @@ func storeInterfaces(consulStore consulutil.ConsulStore, interfaces map[string]t
 				consulStore.StoreConsulKeyAsString(operationPrefix+"/implementation/primary", operationDef.Implementation.Primary)
 				consulStore.StoreConsulKeyAsString(operationPrefix+"/implementation/dependencies", strings.Join(operationDef.Implementation.Dependencies, ","))
 			}
+			metadata := make(map[string]string, len(intMap.Metadata)+len(operationDef.Metadata))
+			for k, v := range intMap.Metadata {
+				metadata[k] = v
+			}
+			for k, v := range operationDef.Metadata {
+				metadata[k] = v
+			}
+			if _, err := parseExecutionPolicyMetadata(metadata); err != nil {
+				return errors.Wrapf(err, "invalid execution policy for operation %s.%s", intTypeName, opName)
+			}
+			storeStringMap(consulStore, operationPrefix+"/metadata", metadata)
 			if operationDef.Implementation.OperationHost != "" {
 				if err := checkOperationHost(operationDef.Implementation.OperationHost, isRelationshipType); err != nil {
 					return err
@@ -782,6 +809,27 @@ func storeWorkflowStep(consulStore consulutil.ConsulStore, deploymentID, workflo
 			consulStore.StoreConsulKeyAsString(activityPrefix+"/inline", strings.ToLower(activity.Inline))
 		}
 	}
+	if step.Timeout != "" {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/timeout", step.Timeout)
+	}
+	if step.MaxRetries != 0 {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/max_retries", strconv.Itoa(step.MaxRetries))
+	}
+	if step.RetryBackoff != "" {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/retry_backoff", step.RetryBackoff)
+	}
+	if len(step.RetryOn) > 0 {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/retry_on", strings.Join(step.RetryOn, ","))
+	}
+	if step.BatchSize != 0 {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/batch_size", strconv.Itoa(step.BatchSize))
+	}
+	if step.PauseBetweenBatches != "" {
+		consulStore.StoreConsulKeyAsString(stepPrefix+"/pause_between_batches", step.PauseBetweenBatches)
+	}
+	if len(step.Filter) > 0 {
+		storeConditions(consulStore, stepPrefix+"/filter", step.Filter)
+	}
 	for _, next := range step.OnSuccess {
 		// store in consul a prefix for the next step to be executed ; this prefix is stepPrefix/next/onSuccess_value
 		consulStore.StoreConsulKeyAsString(fmt.Sprintf("%s/next/%s", stepPrefix, url.QueryEscape(next)), "")
@@ -798,6 +846,9 @@ func storeWorkflowStep(consulStore consulutil.ConsulStore, deploymentID, workflo
 
 // storeWorkflow stores a workflow
 func storeWorkflow(consulStore consulutil.ConsulStore, deploymentID, workflowName string, workflow tosca.Workflow) {
+	if len(workflow.Preconditions) > 0 {
+		storeConditions(consulStore, path.Join(consulutil.DeploymentKVPrefix, deploymentID, "workflows", url.QueryEscape(workflowName), "preconditions"), workflow.Preconditions)
+	}
 	for stepName, step := range workflow.Steps {
 		storeWorkflowStep(consulStore, deploymentID, workflowName, stepName, step)
 	}
@@ -925,12 +976,18 @@ func registerImplementationTypes(ctx context.Context, kv *api.KV, deploymentID s
 
 // enhanceNodes walk through the topology nodes an for each of them if needed it creates the instances and fix alien BlockStorage declaration
 func enhanceNodes(ctx context.Context, kv *api.KV, deploymentID string) error {
-	ctxStore, errGroup, consulStore := consulutil.WithContext(ctx)
-	ctxStore = context.WithValue(ctxStore, consulStoreKey, consulStore)
 	nodes, err := GetNodes(kv, deploymentID)
 	if err != nil {
 		return err
 	}
+	return enhanceNodesList(ctx, kv, deploymentID, nodes)
+}
+
+// enhanceNodesList works as enhanceNodes but only for the given nodes
+func enhanceNodesList(ctx context.Context, kv *api.KV, deploymentID string, nodes []string) error {
+	ctxStore, errGroup, consulStore := consulutil.WithContext(ctx)
+	ctxStore = context.WithValue(ctxStore, consulStoreKey, consulStore)
+	var err error
 	computes := make([]string, 0)
 	for _, nodeName := range nodes {
 		err = fixGetOperationOutputForRelationship(ctx, kv, deploymentID, nodeName)
@@ -1191,9 +1248,14 @@ func fixAlienBlockStorages(ctx context.Context, kv *api.KV, deploymentID, nodeNa
 This function create a given number of floating IP instances
 */
 func createNodeInstances(consulStore consulutil.ConsulStore, kv *api.KV, numberInstances uint32, deploymentID, nodeName string) {
-
 	nodePath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
 
+	// Instances may already exist when a deployment is updated, in this case keep them as is
+	existing, _, err := kv.Keys(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "instances", nodeName)+"/", "/", nil)
+	if err == nil && len(existing) > 0 {
+		consulStore.StoreConsulKeyAsString(path.Join(nodePath, "nbInstances"), strconv.Itoa(len(existing)))
+		return
+	}
 	consulStore.StoreConsulKeyAsString(path.Join(nodePath, "nbInstances"), strconv.FormatUint(uint64(numberInstances), 10))
 
 	for i := uint32(0); i < numberInstances; i++ {
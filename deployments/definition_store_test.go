@@ -985,6 +985,13 @@ func testTopologyTemplateMetadata(t *testing.T, kv *api.KV) {
 		assert.Equal(t, string(kvp.Value), expectedValue, "Wrong value for key %s", key)
 	}
 
+	found, value, err := GetTopologyMetadata(kv, deploymentID, "template_author")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "yorcTester", value)
+	found, _, err = GetTopologyMetadata(kv, deploymentID, "unknown")
+	require.NoError(t, err)
+	assert.False(t, found)
 }
 
 // Testing topology runnable wf autocancel
@@ -96,6 +96,23 @@ func GetDeploymentStatus(kv *api.KV, deploymentID string) (DeploymentStatus, err
 	return DeploymentStatusFromString(string(kvp.Value), true)
 }
 
+// MarkDryRunDeployment marks a deployment as a temporary deployment stored to compute a dry-run execution plan
+//
+// It should be called before storing the deployment definition as no event nor log is published for such deployments.
+func MarkDryRunDeployment(kv *api.KV, deploymentID string) error {
+	_, err := kv.Put(&api.KVPair{Key: path.Join(consulutil.DeploymentKVPrefix, deploymentID, consulutil.DryRunDeploymentMarker), Value: []byte("true")}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// IsDryRunDeployment checks if a deployment is a temporary deployment stored to compute a dry-run execution plan
+func IsDryRunDeployment(kv *api.KV, deploymentID string) (bool, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, consulutil.DryRunDeploymentMarker), nil)
+	if err != nil {
+		return false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return kvp != nil, nil
+}
+
 //GetDeploymentTemplateName only return the name of the template used during the deployment
 func GetDeploymentTemplateName(kv *api.KV, deploymentID string) (string, error) {
 	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "name"), nil)
@@ -108,6 +125,18 @@ func GetDeploymentTemplateName(kv *api.KV, deploymentID string) (string, error)
 	return string(kvp.Value), nil
 }
 
+// GetTopologyMetadata retrieves the related topology template metadata key if exists
+func GetTopologyMetadata(kv *api.KV, deploymentID, key string) (bool, string, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "metadata", key), nil)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "Can't get topology metadata %q", key)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return false, "", nil
+	}
+	return true, string(kvp.Value), nil
+}
+
 // DoesDeploymentExists checks if a given deploymentId refer to an existing deployment
 func DoesDeploymentExists(kv *api.KV, deploymentID string) (bool, error) {
 	if _, err := GetDeploymentStatus(kv, deploymentID); err != nil {
@@ -0,0 +1,231 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+const (
+	// RetryOnTimeout is the class of errors raised when an attempt exceeds its timeout
+	RetryOnTimeout = "timeout"
+	// RetryOnError is the class of errors returned by executors
+	RetryOnError = "error"
+)
+
+// Metadata keys allowing to define an execution policy on TOSCA interfaces and operations
+const (
+	TimeoutMetadata      = "yorc.execution.timeout"
+	MaxRetriesMetadata   = "yorc.execution.max_retries"
+	RetryBackoffMetadata = "yorc.execution.retry_backoff"
+	RetryOnMetadata      = "yorc.execution.retry_on"
+)
+
+// An ExecutionPolicy defines how long the activities of a workflow step may run and how they are retried on failure
+type ExecutionPolicy struct {
+	// Timeout is the maximum duration of an attempt, 0 means no timeout
+	Timeout time.Duration
+	// MaxRetries is the number of retries after a failed attempt
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, it is doubled for each subsequent retry
+	RetryBackoff time.Duration
+	// RetryOn lists the classes of errors that trigger a retry
+	RetryOn []string
+}
+
+// IsZero returns true if this policy doesn't change the default execution behavior
+func (p ExecutionPolicy) IsZero() bool {
+	return p.Timeout == 0 && p.MaxRetries == 0
+}
+
+// ShouldRetry checks if an error of the given class should be retried
+func (p ExecutionPolicy) ShouldRetry(errorClass string) bool {
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	for _, c := range p.RetryOn {
+		if c == errorClass {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay to wait before the given retry (starting at 1)
+func (p ExecutionPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+	// Avoid overflows on large number of retries
+	if retry > 16 {
+		retry = 16
+	}
+	return p.RetryBackoff * time.Duration(1<<uint(retry-1))
+}
+
+// Merge returns a copy of this policy where unset values are taken from the given defaults
+func (p ExecutionPolicy) Merge(defaults ExecutionPolicy) ExecutionPolicy {
+	if p.Timeout == 0 {
+		p.Timeout = defaults.Timeout
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = defaults.MaxRetries
+	}
+	if p.RetryBackoff == 0 {
+		p.RetryBackoff = defaults.RetryBackoff
+	}
+	if len(p.RetryOn) == 0 {
+		p.RetryOn = defaults.RetryOn
+	}
+	return p
+}
+
+// ParseExecutionPolicy checks and converts the given execution policy parameters
+func ParseExecutionPolicy(timeout string, maxRetries int, retryBackoff string, retryOn []string) (ExecutionPolicy, error) {
+	var p ExecutionPolicy
+	var err error
+	if timeout != "" {
+		p.Timeout, err = time.ParseDuration(timeout)
+		if err != nil || p.Timeout < 0 {
+			return p, errors.Errorf("invalid timeout %q, expecting a positive duration", timeout)
+		}
+	}
+	if maxRetries < 0 {
+		return p, errors.Errorf("invalid max_retries %d, expecting a positive integer", maxRetries)
+	}
+	p.MaxRetries = maxRetries
+	if retryBackoff != "" {
+		p.RetryBackoff, err = time.ParseDuration(retryBackoff)
+		if err != nil || p.RetryBackoff < 0 {
+			return p, errors.Errorf("invalid retry_backoff %q, expecting a positive duration", retryBackoff)
+		}
+	}
+	for _, c := range retryOn {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != RetryOnTimeout && c != RetryOnError {
+			return p, errors.Errorf("invalid retry_on error class %q, expecting %q or %q", c, RetryOnTimeout, RetryOnError)
+		}
+		p.RetryOn = append(p.RetryOn, c)
+	}
+	return p, nil
+}
+
+// GetStepExecutionPolicy returns the execution policy defined on a workflow step
+func GetStepExecutionPolicy(step *tosca.Step) (ExecutionPolicy, error) {
+	return ParseExecutionPolicy(step.Timeout, step.MaxRetries, step.RetryBackoff, step.RetryOn)
+}
+
+// parseExecutionPolicyMetadata returns the execution policy defined by operation metadata
+func parseExecutionPolicyMetadata(metadata map[string]string) (ExecutionPolicy, error) {
+	var maxRetries int
+	if mr := metadata[MaxRetriesMetadata]; mr != "" {
+		var err error
+		maxRetries, err = strconv.Atoi(mr)
+		if err != nil {
+			return ExecutionPolicy{}, errors.Errorf("invalid %s %q, expecting a positive integer", MaxRetriesMetadata, mr)
+		}
+	}
+	var retryOn []string
+	if ro := metadata[RetryOnMetadata]; ro != "" {
+		retryOn = strings.Split(ro, ",")
+	}
+	return ParseExecutionPolicy(metadata[TimeoutMetadata], maxRetries, metadata[RetryBackoffMetadata], retryOn)
+}
+
+// GetOperationExecutionPolicy returns the execution policy defined by metadata on the interface or the definition of an operation
+func GetOperationExecutionPolicy(kv *api.KV, deploymentID string, operation prov.Operation) (ExecutionPolicy, error) {
+	operationPath, _ := getOperationAndInterfacePath(deploymentID, operation.ImplementedInNodeTemplate, operation.ImplementedInType, operation.Name)
+	kvps, _, err := kv.List(path.Join(operationPath, "metadata")+"/", nil)
+	if err != nil {
+		return ExecutionPolicy{}, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	metadata := make(map[string]string, len(kvps))
+	for _, kvp := range kvps {
+		metadata[path.Base(kvp.Key)] = string(kvp.Value)
+	}
+	p, err := parseExecutionPolicyMetadata(metadata)
+	return p, errors.Wrapf(err, "invalid execution policy for operation %q", operation.Name)
+}
+
+// A BatchPolicy defines how the instances targeted by a workflow step are split into batches processed one after the other
+type BatchPolicy struct {
+	// BatchSize is the maximum number of instances processed at once, 0 means all of them
+	BatchSize int
+	// PauseBetweenBatches is the delay between the end of a batch and the start of the next one
+	PauseBetweenBatches time.Duration
+}
+
+// ParseBatchPolicy checks and converts the given batch policy parameters
+func ParseBatchPolicy(batchSize int, pauseBetweenBatches string) (BatchPolicy, error) {
+	var p BatchPolicy
+	if batchSize < 0 {
+		return p, errors.Errorf("invalid batch_size %d, expecting a positive integer", batchSize)
+	}
+	p.BatchSize = batchSize
+	if pauseBetweenBatches != "" {
+		var err error
+		p.PauseBetweenBatches, err = time.ParseDuration(pauseBetweenBatches)
+		if err != nil || p.PauseBetweenBatches < 0 {
+			return p, errors.Errorf("invalid pause_between_batches %q, expecting a positive duration", pauseBetweenBatches)
+		}
+	}
+	return p, nil
+}
+
+// GetStepBatchPolicy returns the batch policy defined on a workflow step
+func GetStepBatchPolicy(step *tosca.Step) (BatchPolicy, error) {
+	return ParseBatchPolicy(step.BatchSize, step.PauseBetweenBatches)
+}
+
+// Batches splits the given instances into batches of at most BatchSize instances
+func (p BatchPolicy) Batches(instances []string) [][]string {
+	if p.BatchSize == 0 || len(instances) <= p.BatchSize {
+		return [][]string{instances}
+	}
+	batches := make([][]string, 0, (len(instances)+p.BatchSize-1)/p.BatchSize)
+	for i := 0; i < len(instances); i += p.BatchSize {
+		end := i + p.BatchSize
+		if end > len(instances) {
+			end = len(instances)
+		}
+		batches = append(batches, instances[i:end])
+	}
+	return batches
+}
+
+// checkWorkflowsExecutionPolicies checks that execution policies of workflows steps are valid
+func checkWorkflowsExecutionPolicies(topology tosca.Topology) error {
+	for wfName, wf := range topology.TopologyTemplate.Workflows {
+		for stepName, step := range wf.Steps {
+			if _, err := GetStepExecutionPolicy(step); err != nil {
+				return errors.Wrapf(err, "invalid execution policy for step %q of workflow %q", stepName, wfName)
+			}
+			if _, err := GetStepBatchPolicy(step); err != nil {
+				return errors.Wrapf(err, "invalid batch policy for step %q of workflow %q", stepName, wfName)
+			}
+		}
+	}
+	return nil
+}
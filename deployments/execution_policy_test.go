@@ -0,0 +1,126 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/testutil"
+)
+
+func TestParseExecutionPolicy(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		timeout      string
+		maxRetries   int
+		retryBackoff string
+		retryOn      []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    ExecutionPolicy
+		wantErr bool
+	}{
+		{"Empty", args{"", 0, "", nil}, ExecutionPolicy{}, false},
+		{"Full", args{"5m", 3, "10s", []string{" Timeout", "error"}}, ExecutionPolicy{Timeout: 5 * time.Minute, MaxRetries: 3, RetryBackoff: 10 * time.Second, RetryOn: []string{RetryOnTimeout, RetryOnError}}, false},
+		{"InvalidTimeout", args{"5 minutes", 0, "", nil}, ExecutionPolicy{}, true},
+		{"NegativeTimeout", args{"-5m", 0, "", nil}, ExecutionPolicy{}, true},
+		{"NegativeRetries", args{"", -1, "", nil}, ExecutionPolicy{}, true},
+		{"InvalidBackoff", args{"", 1, "soon", nil}, ExecutionPolicy{}, true},
+		{"InvalidErrorClass", args{"", 1, "", []string{"cancel"}}, ExecutionPolicy{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExecutionPolicy(tt.args.timeout, tt.args.maxRetries, tt.args.retryBackoff, tt.args.retryOn)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecutionPolicyRetries(t *testing.T) {
+	t.Parallel()
+	p := ExecutionPolicy{MaxRetries: 3, RetryBackoff: time.Second}
+	require.False(t, p.IsZero())
+	require.True(t, p.ShouldRetry(RetryOnError))
+	require.True(t, p.ShouldRetry(RetryOnTimeout))
+	require.Equal(t, time.Second, p.Backoff(1))
+	require.Equal(t, 2*time.Second, p.Backoff(2))
+	require.Equal(t, 4*time.Second, p.Backoff(3))
+
+	p.RetryOn = []string{RetryOnTimeout}
+	require.False(t, p.ShouldRetry(RetryOnError))
+	require.True(t, p.ShouldRetry(RetryOnTimeout))
+
+	merged := ExecutionPolicy{Timeout: time.Minute}.Merge(p)
+	require.Equal(t, ExecutionPolicy{Timeout: time.Minute, MaxRetries: 3, RetryBackoff: time.Second, RetryOn: []string{RetryOnTimeout}}, merged)
+	require.True(t, ExecutionPolicy{RetryBackoff: time.Second}.IsZero())
+}
+
+func TestBatchPolicy(t *testing.T) {
+	t.Parallel()
+	p, err := ParseBatchPolicy(2, "30s")
+	require.NoError(t, err)
+	require.Equal(t, BatchPolicy{BatchSize: 2, PauseBetweenBatches: 30 * time.Second}, p)
+	require.Equal(t, [][]string{{"0", "1"}, {"2", "3"}, {"4"}}, p.Batches([]string{"0", "1", "2", "3", "4"}))
+	require.Equal(t, [][]string{{"0"}}, p.Batches([]string{"0"}))
+	require.Equal(t, [][]string{{"0", "1", "2"}}, BatchPolicy{}.Batches([]string{"0", "1", "2"}))
+
+	_, err = ParseBatchPolicy(-1, "")
+	require.Error(t, err)
+	_, err = ParseBatchPolicy(1, "later")
+	require.Error(t, err)
+}
+
+func testExecutionPolicies(t *testing.T, kv *api.KV) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	err := StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/execution_policy.yaml")
+	require.NoError(t, err)
+
+	wf, err := ReadWorkflow(kv, deploymentID, "install")
+	require.NoError(t, err)
+	p, err := GetStepExecutionPolicy(wf.Steps["Compute_install"])
+	require.NoError(t, err)
+	require.Equal(t, ExecutionPolicy{Timeout: 30 * time.Minute, MaxRetries: 3, RetryBackoff: time.Minute, RetryOn: []string{RetryOnError, RetryOnTimeout}}, p)
+	p, err = GetStepExecutionPolicy(wf.Steps["PolicyNode_create"])
+	require.NoError(t, err)
+	require.True(t, p.IsZero())
+	bp, err := GetStepBatchPolicy(wf.Steps["PolicyNode_create"])
+	require.NoError(t, err)
+	require.Equal(t, BatchPolicy{BatchSize: 2, PauseBetweenBatches: 10 * time.Second}, bp)
+
+	// Interface metadata apply to all operations
+	p, err = GetOperationExecutionPolicy(kv, deploymentID, prov.Operation{Name: "standard.create", ImplementedInType: "ystia.yorc.tests.nodes.PolicyNode"})
+	require.NoError(t, err)
+	require.Equal(t, ExecutionPolicy{Timeout: 10 * time.Minute, MaxRetries: 2}, p)
+	// and operation metadata override them
+	p, err = GetOperationExecutionPolicy(kv, deploymentID, prov.Operation{Name: "standard.start", ImplementedInType: "ystia.yorc.tests.nodes.PolicyNode"})
+	require.NoError(t, err)
+	require.Equal(t, ExecutionPolicy{Timeout: time.Minute, MaxRetries: 2, RetryBackoff: 5 * time.Second, RetryOn: []string{RetryOnTimeout}}, p)
+
+	err = StoreDeploymentDefinition(context.Background(), kv, deploymentID+"Invalid", "testdata/execution_policy_invalid.yaml")
+	require.Error(t, err)
+}
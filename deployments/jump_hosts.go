@@ -0,0 +1,107 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+)
+
+// JumpHost holds the connection parameters of a bastion host defined in the
+// jump_hosts of the credentials of an endpoint capability
+type JumpHost struct {
+	Host       string
+	Port       int
+	User       string
+	Password   string
+	PrivateKey string
+}
+
+// GetInstanceEndpointJumpHosts returns the ordered chain of jump hosts defined in the credentials
+// of the endpoint capability of a node instance
+//
+// Templates in values are resolved using the Yorc configuration. Jump hosts without user
+// use the given defaultUser and jump hosts without port use the port 22.
+func GetInstanceEndpointJumpHosts(kv *api.KV, deploymentID, nodeName, instanceName, defaultUser string) ([]JumpHost, error) {
+	var jumpHosts []JumpHost
+	for i := 0; ; i++ {
+		index := strconv.Itoa(i)
+		getValue := func(name string, keys ...string) (string, error) {
+			value, err := GetInstanceCapabilityAttributeValue(kv, deploymentID, nodeName, instanceName, "endpoint", "credentials", append([]string{"jump_hosts", index}, keys...)...)
+			if err != nil || value == nil || value.RawString() == "" {
+				return "", err
+			}
+			return config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("jump_host."+name, value.RawString()).(string), nil
+		}
+		host, err := getValue("host", "host")
+		if err != nil || host == "" {
+			return jumpHosts, err
+		}
+		jh := JumpHost{Host: host, User: defaultUser, Port: 22}
+		user, err := getValue("user", "user")
+		if err != nil {
+			return nil, err
+		}
+		if user != "" {
+			jh.User = user
+		}
+		port, err := getValue("port", "port")
+		if err != nil {
+			return nil, err
+		}
+		if port != "" {
+			jh.Port, err = strconv.Atoi(port)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to convert jump host port value:%q to int", port)
+			}
+		}
+		jh.Password, err = getValue("password", "token")
+		if err != nil {
+			return nil, err
+		}
+		jh.PrivateKey, err = getValue("privateKey", "keys", "0")
+		if err != nil {
+			return nil, err
+		}
+		jumpHosts = append(jumpHosts, jh)
+	}
+}
+
+// JumpHostsCredentials returns the jump_hosts value of the credentials of an endpoint capability
+// defining the given jump hosts, as read by GetInstanceEndpointJumpHosts
+func JumpHostsCredentials(jumpHosts []JumpHost) []map[string]interface{} {
+	if len(jumpHosts) == 0 {
+		return nil
+	}
+	creds := make([]map[string]interface{}, len(jumpHosts))
+	for i, jh := range jumpHosts {
+		creds[i] = map[string]interface{}{
+			"host": jh.Host,
+			"user": jh.User,
+			"port": strconv.Itoa(jh.Port),
+		}
+		if jh.Password != "" {
+			creds[i]["token"] = jh.Password
+		}
+		if jh.PrivateKey != "" {
+			creds[i]["keys"] = []string{jh.PrivateKey}
+		}
+	}
+	return creds
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"path"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+// LockDeployment acquires the lock of a deployment.
+//
+// This lock should be held when checking the tasks running on a deployment before changing it,
+// in order to prevent another task to be registered meanwhile. It should be released by calling Unlock.
+func LockDeployment(cc *api.Client, deploymentID string) (*consulutil.AutoDeleteLock, error) {
+	lockPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, ".lock")
+	for {
+		lock, err := cc.LockKey(lockPath)
+		if err != nil {
+			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+		lockCh, err := lock.Lock(nil)
+		if err != nil {
+			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+		if lockCh != nil {
+			return &consulutil.AutoDeleteLock{Lock: lock}, nil
+		}
+	}
+}
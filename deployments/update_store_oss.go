@@ -0,0 +1,463 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !premium
+
+package deployments
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/collections"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+// UpdateDeploymentDefinition parses the TOSCA definition at defPath and stores it as a new version of an existing deployment.
+//
+// Instances of nodes still present in the new topology are kept as is, instances are created for added nodes.
+// Removed nodes are kept until they are uninstalled by the returned update workflow and then deleted by CleanupRemovedNodes.
+// If the new definition can't be stored, the previous definition and deployment status are restored.
+// If the new definition doesn't change anything, the returned update has the current version of the deployment.
+//
+// Callers should hold the deployment lock (see LockDeployment) to prevent tasks to be registered meanwhile.
+func UpdateDeploymentDefinition(ctx context.Context, kv *api.KV, deploymentID string, defPath string) (*DeploymentUpdate, error) {
+	previousStatus, err := GetDeploymentStatus(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := GetDeploymentVersion(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	if err = SetDeploymentStatus(ctx, kv, deploymentID, UPDATE_IN_PROGRESS); err != nil {
+		return nil, err
+	}
+	update, err := updateDeploymentDefinition(ctx, kv, deploymentID, defPath, version, previousStatus)
+	if err != nil {
+		return nil, err
+	}
+	if update.Version == version {
+		// Nothing changed
+		return update, SetDeploymentStatus(ctx, kv, deploymentID, previousStatus)
+	}
+	if len(update.UpdatedNodes) > 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf(
+			"Definition of nodes %s changed in version %d, their existing instances are kept as is and should be reconfigured by running a workflow",
+			strings.Join(update.UpdatedNodes, ", "), update.Version)
+	}
+	if update.Workflow == "" {
+		// Nothing to install or uninstall, the update is done
+		if err = CleanupRemovedNodes(kv, deploymentID); err != nil {
+			return update, err
+		}
+		return update, SetDeploymentStatus(ctx, kv, deploymentID, UPDATED)
+	}
+	return update, nil
+}
+
+func updateDeploymentDefinition(ctx context.Context, kv *api.KV, deploymentID string, defPath string, version int, previousStatus DeploymentStatus) (update *DeploymentUpdate, err error) {
+	// Keep the current definition to restore it if the new one can't be stored
+	var snapshot api.KVPairs
+	var addedNodes []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		status := previousStatus
+		if snapshot != nil {
+			if errRestore := restoreDefinition(ctx, kv, deploymentID, addedNodes, snapshot); errRestore != nil {
+				log.Printf("Failed to restore definition of deployment %q after a failed update: %+v", deploymentID, errRestore)
+				status = UPDATE_FAILURE
+			}
+		}
+		if errStatus := SetDeploymentStatus(ctx, kv, deploymentID, status); errStatus != nil {
+			log.Printf("Failed to restore status of deployment %q after a failed update: %+v", deploymentID, errStatus)
+		}
+	}()
+
+	topology, err := readTopologyFile(defPath)
+	if err != nil {
+		return nil, invalidDefinitionError{err}
+	}
+	update = &DeploymentUpdate{Version: version + 1}
+
+	oldNodes, err := GetNodes(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	oldWorkflows, err := GetWorkflows(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	oldUninstall, err := ReadWorkflow(kv, deploymentID, "uninstall")
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0, len(topology.TopologyTemplate.NodeTemplates))
+	for nodeName := range topology.TopologyTemplate.NodeTemplates {
+		nodes = append(nodes, nodeName)
+	}
+	sort.Strings(nodes)
+	oldDefinitions := make(map[string]map[string]string)
+	for _, nodeName := range nodes {
+		if !collections.ContainsString(oldNodes, nodeName) {
+			update.AddedNodes = append(update.AddedNodes, nodeName)
+			continue
+		}
+		oldDefinitions[nodeName], err = getNodeDefinitionValues(kv, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, nodeName := range oldNodes {
+		if !collections.ContainsString(nodes, nodeName) {
+			update.RemovedNodes = append(update.RemovedNodes, nodeName)
+		}
+	}
+	for wfName := range topology.TopologyTemplate.Workflows {
+		if !collections.ContainsString(oldWorkflows, wfName) {
+			update.NewWorkflows = append(update.NewWorkflows, wfName)
+		}
+	}
+	sort.Strings(update.RemovedNodes)
+	sort.Strings(update.NewWorkflows)
+
+	snapshot, err = snapshotDefinition(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	addedNodes = update.AddedNodes
+
+	err = clearDefinitionForUpdate(kv, deploymentID, nodes)
+	if err != nil {
+		return nil, err
+	}
+	err = storeDeployment(ctx, topology, deploymentID, filepath.Dir(defPath))
+	if err != nil {
+		return nil, invalidDefinitionError{errors.Wrapf(err, "Failed to store TOSCA Definition for deployment with id %q, (file path %q)", deploymentID, defPath)}
+	}
+	err = registerImplementationTypes(ctx, kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	err = enhanceNodesList(ctx, kv, deploymentID, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nodeName := range nodes {
+		oldDef, ok := oldDefinitions[nodeName]
+		if !ok {
+			continue
+		}
+		var newDef map[string]string
+		newDef, err = getNodeDefinitionValues(kv, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(oldDef, newDef) {
+			update.UpdatedNodes = append(update.UpdatedNodes, nodeName)
+		}
+	}
+
+	newDefinition, err := snapshotDefinition(kv, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(update.RemovedNodes) == 0 && isSameDefinition(snapshot, newDefinition) {
+		return &DeploymentUpdate{Version: version}, nil
+	}
+	err = storePreviousDefinition(ctx, deploymentID, version, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	newInstall, err := ReadWorkflow(kv, deploymentID, "install")
+	if err != nil {
+		return nil, err
+	}
+	wf := buildUpdateWorkflow(oldUninstall, newInstall, update.RemovedNodes, update.AddedNodes)
+	if len(wf.Steps) > 0 {
+		_, errGroup, consulStore := consulutil.WithContext(ctx)
+		storeWorkflow(consulStore, deploymentID, UpdateWorkflowName, wf)
+		if err = errGroup.Wait(); err != nil {
+			return nil, err
+		}
+		update.Workflow = UpdateWorkflowName
+	}
+	err = storeDeploymentUpdate(kv, deploymentID, update)
+	return update, err
+}
+
+func isInstanceKey(deploymentID, key string) bool {
+	topologyPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology")
+	return strings.HasPrefix(key, path.Join(topologyPath, "instances")+"/") ||
+		strings.HasPrefix(key, path.Join(topologyPath, "relationship_instances")+"/")
+}
+
+// snapshotDefinition returns the keys of the definition of a deployment (topology and workflows) as well as the
+// attribute notifications of its instances. Other runtime data of instances are not part of the snapshot.
+func snapshotDefinition(kv *api.KV, deploymentID string) (api.KVPairs, error) {
+	depPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID)
+	snapshot := make(api.KVPairs, 0)
+	for _, p := range []string{"topology", "workflows"} {
+		kvps, _, err := kv.List(path.Join(depPath, p)+"/", nil)
+		if err != nil {
+			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+		for _, kvp := range kvps {
+			if isInstanceKey(deploymentID, kvp.Key) && !strings.Contains(kvp.Key, "/attribute_notifications/") {
+				continue
+			}
+			snapshot = append(snapshot, kvp)
+		}
+	}
+	return snapshot, nil
+}
+
+// isSameDefinition checks if two definition snapshots have the same keys and values
+func isSameDefinition(snapshot, other api.KVPairs) bool {
+	if len(snapshot) != len(other) {
+		return false
+	}
+	values := make(map[string][]byte, len(snapshot))
+	for _, kvp := range snapshot {
+		values[kvp.Key] = kvp.Value
+	}
+	for _, kvp := range other {
+		value, ok := values[kvp.Key]
+		if !ok || !bytes.Equal(value, kvp.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// storePreviousDefinition keeps the definition of the given version of a deployment under versions/<version>/definition
+func storePreviousDefinition(ctx context.Context, deploymentID string, version int, snapshot api.KVPairs) error {
+	depPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID)
+	versionPath := path.Join(depPath, "versions", strconv.Itoa(version), "definition")
+	_, errGroup, consulStore := consulutil.WithContext(ctx)
+	for _, kvp := range snapshot {
+		if isInstanceKey(deploymentID, kvp.Key) {
+			continue
+		}
+		consulStore.StoreConsulKey(path.Join(versionPath, strings.TrimPrefix(kvp.Key, depPath)), kvp.Value)
+	}
+	return errGroup.Wait()
+}
+
+// restoreDefinition puts back a deployment definition snapshot after a failed update and removes instances created for added nodes
+func restoreDefinition(ctx context.Context, kv *api.KV, deploymentID string, addedNodes []string, snapshot api.KVPairs) error {
+	topologyPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology")
+	for _, nodeName := range addedNodes {
+		for _, p := range []string{"instances", "relationship_instances"} {
+			_, err := kv.DeleteTree(path.Join(topologyPath, p, nodeName)+"/", nil)
+			if err != nil {
+				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			}
+		}
+	}
+	// Remove definition keys stored by the failed update
+	current, err := snapshotDefinition(kv, deploymentID)
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]struct{}, len(snapshot))
+	for _, kvp := range snapshot {
+		previous[kvp.Key] = struct{}{}
+	}
+	for _, kvp := range current {
+		if _, ok := previous[kvp.Key]; !ok {
+			_, err = kv.Delete(kvp.Key, nil)
+			if err != nil {
+				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			}
+		}
+	}
+	_, errGroup, consulStore := consulutil.WithContext(ctx)
+	for _, kvp := range snapshot {
+		consulStore.StoreConsulKey(kvp.Key, kvp.Value)
+	}
+	return errGroup.Wait()
+}
+
+// getNodeDefinitionValues returns the stored type and properties of a node, used to detect updated nodes
+func getNodeDefinitionValues(kv *api.KV, deploymentID, nodeName string) (map[string]string, error) {
+	nodePath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName) + "/"
+	kvps, _, err := kv.List(nodePath, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	values := make(map[string]string)
+	for _, kvp := range kvps {
+		key := strings.TrimPrefix(kvp.Key, nodePath)
+		parts := strings.Split(key, "/")
+		if key == "type" || parts[0] == "properties" || (parts[0] == "capabilities" && len(parts) > 2 && parts[2] == "properties") {
+			values[key] = string(kvp.Value)
+		}
+	}
+	return values, nil
+}
+
+// clearDefinitionForUpdate removes the parts of a deployment definition that will be stored again.
+//
+// Types are kept as removed nodes still refer to them, runtime data of instances are also kept except attribute notifications
+// that will be computed again.
+func clearDefinitionForUpdate(kv *api.KV, deploymentID string, nodes []string) error {
+	topologyPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology")
+	prefixes := []string{
+		path.Join(consulutil.DeploymentKVPrefix, deploymentID, "workflows"),
+		path.Join(topologyPath, "metadata"),
+		path.Join(topologyPath, "inputs"),
+		path.Join(topologyPath, "outputs"),
+		path.Join(topologyPath, "policies"),
+		path.Join(topologyPath, "repositories"),
+		path.Join(topologyPath, "substitution_mappings"),
+		path.Join(topologyPath, implementationArtifactsExtensionsPath),
+	}
+	for _, nodeName := range nodes {
+		prefixes = append(prefixes, path.Join(topologyPath, "nodes", nodeName))
+	}
+	for _, p := range prefixes {
+		_, err := kv.DeleteTree(p+"/", nil)
+		if err != nil {
+			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+	}
+
+	instancesKeys, _, err := kv.Keys(path.Join(topologyPath, "instances")+"/", "", nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, key := range instancesKeys {
+		if strings.Contains(key, "/attribute_notifications/") {
+			_, err = kv.Delete(key, nil)
+			if err != nil {
+				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			}
+		}
+	}
+	return nil
+}
+
+// buildUpdateWorkflow generates a workflow running the previous uninstall workflow steps of removed nodes
+// then the new install workflow steps of added nodes
+func buildUpdateWorkflow(oldUninstall, newInstall tosca.Workflow, removedNodes, addedNodes []string) tosca.Workflow {
+	uninstallSteps := filterWorkflowSteps(oldUninstall, removedNodes)
+	installSteps := filterWorkflowSteps(newInstall, addedNodes)
+
+	// Install steps start when all uninstall steps are done
+	installInitialSteps := make([]string, 0)
+	for stepName := range installSteps {
+		isInitial := true
+		for _, s := range installSteps {
+			if collections.ContainsString(s.OnSuccess, stepName) {
+				isInitial = false
+				break
+			}
+		}
+		if isInitial {
+			installInitialSteps = append(installInitialSteps, "install_"+stepName)
+		}
+	}
+	sort.Strings(installInitialSteps)
+
+	wf := tosca.Workflow{Steps: make(map[string]*tosca.Step, len(uninstallSteps)+len(installSteps))}
+	for stepName, s := range uninstallSteps {
+		s.OnSuccess = prefixStepNames("uninstall_", s.OnSuccess)
+		if len(s.OnSuccess) == 0 {
+			s.OnSuccess = installInitialSteps
+		}
+		wf.Steps["uninstall_"+stepName] = s
+	}
+	for stepName, s := range installSteps {
+		s.OnSuccess = prefixStepNames("install_", s.OnSuccess)
+		wf.Steps["install_"+stepName] = s
+	}
+	return wf
+}
+
+func prefixStepNames(prefix string, steps []string) []string {
+	res := make([]string, len(steps))
+	for i := range steps {
+		res[i] = prefix + steps[i]
+	}
+	return res
+}
+
+// filterWorkflowSteps returns copies of the workflow steps targeting the given nodes.
+//
+// Ordering between returned steps is preserved even if it was defined through filtered out steps.
+func filterWorkflowSteps(wf tosca.Workflow, nodes []string) map[string]*tosca.Step {
+	keep := func(s *tosca.Step) bool {
+		return s.Target != "" && collections.ContainsString(nodes, s.Target)
+	}
+	steps := make(map[string]*tosca.Step)
+	for stepName, s := range wf.Steps {
+		if !keep(s) {
+			continue
+		}
+		steps[stepName] = &tosca.Step{
+			Target:              s.Target,
+			TargetRelationShip:  s.TargetRelationShip,
+			OperationHost:       s.OperationHost,
+			Filter:              s.Filter,
+			Activities:          s.Activities,
+			OnSuccess:           nextKeptSteps(wf, s.OnSuccess, keep, make(map[string]bool)),
+			Timeout:             s.Timeout,
+			MaxRetries:          s.MaxRetries,
+			RetryBackoff:        s.RetryBackoff,
+			RetryOn:             s.RetryOn,
+			BatchSize:           s.BatchSize,
+			PauseBetweenBatches: s.PauseBetweenBatches,
+		}
+	}
+	return steps
+}
+
+func nextKeptSteps(wf tosca.Workflow, next []string, keep func(*tosca.Step) bool, visited map[string]bool) []string {
+	res := make([]string, 0)
+	for _, stepName := range next {
+		if visited[stepName] {
+			continue
+		}
+		visited[stepName] = true
+		s, ok := wf.Steps[stepName]
+		if !ok {
+			continue
+		}
+		if keep(s) {
+			res = append(res, stepName)
+			continue
+		}
+		res = append(res, nextKeptSteps(wf, s.OnSuccess, keep, visited)...)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -17,13 +17,145 @@
 package deployments
 
 import (
+	"context"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/tosca"
 )
 
-// Testing topology template metadata
+// Testing topology update
 func testTopologyUpdate(t *testing.T, kv *api.KV) {
+	t.Parallel()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	ctx := context.Background()
+	err := StoreDeploymentDefinition(ctx, kv, deploymentID, "testdata/update_topology_v1.yaml")
+	require.NoError(t, err, "Failed to store test topology deployment definition")
+	require.NoError(t, SetDeploymentStatus(ctx, kv, deploymentID, DEPLOYED))
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/Compute/0/attributes/state"), tosca.NodeStateStarted.String())
+	require.NoError(t, err)
+
+	update, err := UpdateDeploymentDefinition(ctx, kv, deploymentID, "testdata/update_topology_v2.yaml")
+	require.NoError(t, err, "Failed to update test topology deployment definition")
+	assert.Equal(t, 2, update.Version)
+	assert.Equal(t, []string{"NewSoft"}, update.AddedNodes)
+	assert.Equal(t, []string{"OldSoft"}, update.RemovedNodes)
+	assert.Equal(t, []string{"Soft"}, update.UpdatedNodes)
+	assert.Equal(t, []string{"restart"}, update.NewWorkflows)
+	assert.Equal(t, UpdateWorkflowName, update.Workflow)
+
+	version, err := GetDeploymentVersion(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+	status, err := GetDeploymentStatus(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, UPDATE_IN_PROGRESS, status)
+
+	// Existing instances are kept, new ones are created
+	state, err := GetInstanceState(kv, deploymentID, "Compute", "0")
+	require.NoError(t, err)
+	assert.Equal(t, tosca.NodeStateStarted, state)
+	state, err = GetInstanceState(kv, deploymentID, "NewSoft", "0")
+	require.NoError(t, err)
+	assert.Equal(t, tosca.NodeStateInitial, state)
+	version2, err := GetNodePropertyValue(kv, deploymentID, "Soft", "component_version")
+	require.NoError(t, err)
+	require.NotNil(t, version2)
+	assert.Equal(t, "2.0.0", version2.RawString())
+
+	wf, err := ReadWorkflow(kv, deploymentID, UpdateWorkflowName)
+	require.NoError(t, err)
+	require.Len(t, wf.Steps, 4)
+	require.Contains(t, wf.Steps, "uninstall_OldSoft_stop")
+	assert.Equal(t, []string{"uninstall_OldSoft_stopped"}, wf.Steps["uninstall_OldSoft_stop"].OnSuccess)
+	require.Contains(t, wf.Steps, "uninstall_OldSoft_stopped")
+	assert.Equal(t, []string{"install_NewSoft_initial"}, wf.Steps["uninstall_OldSoft_stopped"].OnSuccess)
+	require.Contains(t, wf.Steps, "install_NewSoft_initial")
+	assert.Equal(t, []string{"install_NewSoft_create"}, wf.Steps["install_NewSoft_initial"].OnSuccess)
+	require.Contains(t, wf.Steps, "install_NewSoft_create")
+	assert.Len(t, wf.Steps["install_NewSoft_create"].OnSuccess, 0)
+
+	// The previous definition is kept
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "versions/1/definition/topology/nodes/OldSoft/type"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, kvp)
+	assert.Equal(t, "tosca.nodes.SoftwareComponent", string(kvp.Value))
+
+	// Removed nodes are kept until the update workflow uninstalled them
+	exist, err := DoesNodeExist(kv, deploymentID, "OldSoft")
+	require.NoError(t, err)
+	assert.True(t, exist)
+	require.NoError(t, CleanupRemovedNodes(kv, deploymentID))
+	nodes, err := GetNodes(kv, deploymentID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Compute", "Soft", "NewSoft"}, nodes)
+	workflows, err := GetWorkflows(kv, deploymentID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"install", "uninstall", "restart"}, workflows)
+
+	// Updating again with the same definition changes nothing
+	require.NoError(t, SetDeploymentStatus(ctx, kv, deploymentID, UPDATED))
+	update, err = UpdateDeploymentDefinition(ctx, kv, deploymentID, "testdata/update_topology_v2.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, &DeploymentUpdate{Version: 2}, update)
+	version, err = GetDeploymentVersion(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+	status, err = GetDeploymentStatus(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, UPDATED, status)
+	kvp, _, err = kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "versions/3"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, kvp)
+}
+
+// Testing that a failed topology update restores the previous definition
+func testTopologyUpdateRollback(t *testing.T, kv *api.KV) {
+	t.Parallel()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	ctx := context.Background()
+	err := StoreDeploymentDefinition(ctx, kv, deploymentID, "testdata/update_topology_v1.yaml")
+	require.NoError(t, err, "Failed to store test topology deployment definition")
+	require.NoError(t, SetDeploymentStatus(ctx, kv, deploymentID, DEPLOYED))
+
+	_, err = UpdateDeploymentDefinition(ctx, kv, deploymentID, "testdata/update_topology_invalid.yaml")
+	require.Error(t, err)
+	assert.True(t, IsInvalidDefinitionError(err), "unexpected error type %T: %v", err, err)
+
+	version, err := GetDeploymentVersion(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	status, err := GetDeploymentStatus(kv, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, DEPLOYED, status)
+	nodes, err := GetNodes(kv, deploymentID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Compute", "Soft", "OldSoft"}, nodes)
+	version1, err := GetNodePropertyValue(kv, deploymentID, "Soft", "component_version")
+	require.NoError(t, err)
+	require.NotNil(t, version1)
+	assert.Equal(t, "1.0.0", version1.RawString())
+	workflows, err := GetWorkflows(kv, deploymentID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"install", "uninstall"}, workflows)
+}
 
-	// Topology update is a premium feature
+func TestBuildUpdateWorkflowWithoutRemovedNodes(t *testing.T) {
+	t.Parallel()
+	install := tosca.Workflow{Steps: map[string]*tosca.Step{
+		"A_install": &tosca.Step{Target: "A", OnSuccess: []string{"B_install"}},
+		"B_install": &tosca.Step{Target: "B", OnSuccess: []string{"C_install"}},
+		"C_install": &tosca.Step{Target: "C"},
+		"inline":    &tosca.Step{Activities: []tosca.Activity{{Inline: "other"}}, OnSuccess: []string{"C_install"}},
+	}}
+	wf := buildUpdateWorkflow(tosca.Workflow{}, install, nil, []string{"A", "C"})
+	require.Len(t, wf.Steps, 2)
+	assert.Equal(t, []string{"install_C_install"}, wf.Steps["install_A_install"].OnSuccess)
+	assert.Len(t, wf.Steps["install_C_install"].OnSuccess, 0)
 }
@@ -0,0 +1,125 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"encoding/json"
+	"path"
+	"strconv"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+// UpdateWorkflowName is the name of the workflow generated to install and uninstall nodes
+// added or removed by a deployment update
+const UpdateWorkflowName = "yorc_update"
+
+type invalidDefinitionError struct {
+	err error
+}
+
+func (e invalidDefinitionError) Error() string {
+	return e.err.Error()
+}
+
+// IsInvalidDefinitionError checks if the given error is due to an invalid new deployment definition
+func IsInvalidDefinitionError(err error) bool {
+	cause := errors.Cause(err)
+	_, ok := cause.(invalidDefinitionError)
+	return ok
+}
+
+// A DeploymentUpdate describes the changes introduced by a new version of a deployment topology
+type DeploymentUpdate struct {
+	Version      int      `json:"version"`
+	AddedNodes   []string `json:"added_nodes,omitempty"`
+	RemovedNodes []string `json:"removed_nodes,omitempty"`
+	UpdatedNodes []string `json:"updated_nodes,omitempty"`
+	NewWorkflows []string `json:"new_workflows,omitempty"`
+	// Workflow is the name of the workflow applying this update,
+	// it is empty if there is no node to install or uninstall
+	Workflow string `json:"workflow,omitempty"`
+}
+
+// GetDeploymentVersion returns the current version of a deployment topology
+//
+// Deployments that were never updated are at version 1.
+func GetDeploymentVersion(kv *api.KV, deploymentID string) (int, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "version"), nil)
+	if err != nil {
+		return 0, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(string(kvp.Value))
+	return version, errors.Wrapf(err, "invalid version for deployment %q", deploymentID)
+}
+
+// GetDeploymentUpdate returns the changes introduced by the given version of a deployment
+//
+// A nil DeploymentUpdate is returned if this version is not the result of an update.
+func GetDeploymentUpdate(kv *api.KV, deploymentID string, version int) (*DeploymentUpdate, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "versions", strconv.Itoa(version)), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, nil
+	}
+	update := new(DeploymentUpdate)
+	err = json.Unmarshal(kvp.Value, update)
+	return update, errors.Wrapf(err, "failed to read version %d of deployment %q", version, deploymentID)
+}
+
+func storeDeploymentUpdate(kv *api.KV, deploymentID string, update *DeploymentUpdate) error {
+	b, err := json.Marshal(update)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store version %d of deployment %q", update.Version, deploymentID)
+	}
+	depPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID)
+	err = consulutil.StoreConsulKey(path.Join(depPath, "versions", strconv.Itoa(update.Version)), b)
+	if err != nil {
+		return err
+	}
+	return consulutil.StoreConsulKeyAsString(path.Join(depPath, "version"), strconv.Itoa(update.Version))
+}
+
+// CleanupRemovedNodes deletes definitions and instances of nodes removed by the last update of a deployment
+// as well as the workflow generated to apply this update.
+func CleanupRemovedNodes(kv *api.KV, deploymentID string) error {
+	version, err := GetDeploymentVersion(kv, deploymentID)
+	if err != nil {
+		return err
+	}
+	update, err := GetDeploymentUpdate(kv, deploymentID, version)
+	if err != nil || update == nil {
+		return err
+	}
+	topologyPath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology")
+	for _, nodeName := range update.RemovedNodes {
+		for _, p := range []string{"nodes", "instances", "relationship_instances"} {
+			_, err = kv.DeleteTree(path.Join(topologyPath, p, nodeName)+"/", nil)
+			if err != nil {
+				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			}
+		}
+	}
+	_, err = kv.DeleteTree(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "workflows", UpdateWorkflowName)+"/", nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
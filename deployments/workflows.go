@@ -15,6 +15,7 @@
 package deployments
 
 import (
+	"encoding/json"
 	"net/url"
 	"path"
 	"strconv"
@@ -61,7 +62,8 @@ func ReadWorkflow(kv *api.KV, deploymentID, workflowName string) (tosca.Workflow
 		}
 		wf.Steps[stepName] = step
 	}
-	return wf, nil
+	wf.Preconditions, err = GetWorkflowPreconditions(kv, deploymentID, workflowName)
+	return wf, err
 }
 
 func readWfStep(kv *api.KV, stepKey string, stepName string, wfName string) (*tosca.Step, error) {
@@ -83,6 +85,41 @@ func readWfStep(kv *api.KV, stepKey string, stepName string, wfName string) (*to
 	if kvp != nil && len(kvp.Value) != 0 {
 		step.TargetRelationShip = string(kvp.Value)
 	}
+	// Get the step's execution policy (not mandatory)
+	kvps, _, err := kv.List(stepKey, nil)
+	if err != nil {
+		return step, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, kvp := range kvps {
+		if len(kvp.Value) == 0 {
+			continue
+		}
+		switch strings.TrimPrefix(kvp.Key, stepKey) {
+		case "timeout":
+			step.Timeout = string(kvp.Value)
+		case "max_retries":
+			step.MaxRetries, err = strconv.Atoi(string(kvp.Value))
+			if err != nil {
+				return step, errors.Wrapf(err, "invalid max_retries for step %q", stepName)
+			}
+		case "retry_backoff":
+			step.RetryBackoff = string(kvp.Value)
+		case "retry_on":
+			step.RetryOn = strings.Split(string(kvp.Value), ",")
+		case "batch_size":
+			step.BatchSize, err = strconv.Atoi(string(kvp.Value))
+			if err != nil {
+				return step, errors.Wrapf(err, "invalid batch_size for step %q", stepName)
+			}
+		case "pause_between_batches":
+			step.PauseBetweenBatches = string(kvp.Value)
+		case "filter":
+			err = json.Unmarshal(kvp.Value, &step.Filter)
+			if err != nil {
+				return step, errors.Wrapf(err, "invalid filter for step %q", stepName)
+			}
+		}
+	}
 	// Get the step's activities
 	activitiesKeys, _, err := kv.List(stepKey+"/activities", nil)
 	if err != nil {
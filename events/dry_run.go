@@ -0,0 +1,50 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"path"
+	"sync"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+// regularDeployments caches the ids of deployments known not to be dry-run ones
+//
+// A deployment never changes of kind, while dry-run deployments are marked before publishing anything.
+// Only regular deployments are cached as dry-run ones are short-lived.
+var regularDeployments sync.Map
+
+// isDryRunDeployment checks if a deployment is marked as a temporary deployment used to compute a dry-run execution plan
+func isDryRunDeployment(deploymentID string) bool {
+	if _, ok := regularDeployments.Load(deploymentID); ok {
+		return false
+	}
+	kv := consulutil.GetKV()
+	if kv == nil {
+		return false
+	}
+	kvp, _, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, consulutil.DryRunDeploymentMarker), nil)
+	if err != nil {
+		log.Printf("Failed to check if deployment %q is a dry-run one: %v", deploymentID, err)
+		return false
+	}
+	if kvp != nil {
+		return true
+	}
+	regularDeployments.Store(deploymentID, struct{}{})
+	return false
+}
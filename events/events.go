@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -270,6 +271,23 @@ func PublishAndLogWorkflowStatusChange(ctx context.Context, kv *api.KV, deployme
 	return id, nil
 }
 
+// PublishCheckStatusChange publishes a status change of the monitoring check of a given instance of a given node
+//
+// Contrary to other status changes, it is not logged into the log API as monitoring checks already log their
+// notifications. PublishCheckStatusChange returns the published event id
+func PublishCheckStatusChange(ctx context.Context, deploymentID, nodeName, instance, status, message string) (string, error) {
+	ctx = AddLogOptionalFields(ctx, LogOptionalFields{NodeID: nodeName, InstanceID: instance})
+	info := buildInfoFromContext(ctx)
+	info[ENodeID] = nodeName
+	info[EInstanceID] = instance
+	info[EMessage] = message
+	e, err := newStatusChange(StatusChangeTypeCheck, info, deploymentID, strings.ToLower(status))
+	if err != nil {
+		return "", err
+	}
+	return e.register()
+}
+
 // StatusEvents return a list of events (StatusUpdate instances) for all, or a given deployment
 func StatusEvents(kv *api.KV, deploymentID string, waitIndex uint64, timeout time.Duration) ([]json.RawMessage, uint64, error) {
 	events := make([]json.RawMessage, 0)
@@ -325,6 +343,44 @@ func LogsEvents(kv *api.KV, deploymentID string, waitIndex uint64, timeout time.
 	return logs, qm.LastIndex, nil
 }
 
+// IndexedEntry is an event or a log entry along with the Consul index at which it was stored
+type IndexedEntry struct {
+	Index uint64          `json:"index"`
+	Value json.RawMessage `json:"value"`
+}
+
+// StatusEventsWithIndexes works as StatusEvents but returns each event along with its own Consul index
+//
+// Events are sorted by index, this allows consumers to resume a stream from the index of the last event they processed.
+func StatusEventsWithIndexes(kv *api.KV, deploymentID string, waitIndex uint64, timeout time.Duration) ([]IndexedEntry, uint64, error) {
+	return indexedEntries(kv, path.Join(consulutil.EventsPrefix, deploymentID), waitIndex, timeout)
+}
+
+// LogsEventsWithIndexes works as LogsEvents but returns each log along with its own Consul index
+//
+// Logs are sorted by index, this allows consumers to resume a stream from the index of the last log they processed.
+func LogsEventsWithIndexes(kv *api.KV, deploymentID string, waitIndex uint64, timeout time.Duration) ([]IndexedEntry, uint64, error) {
+	return indexedEntries(kv, path.Join(consulutil.LogsPrefix, deploymentID), waitIndex, timeout)
+}
+
+func indexedEntries(kv *api.KV, prefix string, waitIndex uint64, timeout time.Duration) ([]IndexedEntry, uint64, error) {
+	entries := make([]IndexedEntry, 0)
+	kvps, qm, err := kv.List(prefix, &api.QueryOptions{WaitIndex: waitIndex, WaitTime: timeout})
+	if err != nil || qm == nil {
+		return entries, 0, err
+	}
+	for _, kvp := range kvps {
+		if kvp.ModifyIndex <= waitIndex {
+			continue
+		}
+		entries = append(entries, IndexedEntry{Index: kvp.ModifyIndex, Value: kvp.Value})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Index < entries[j].Index
+	})
+	return entries, qm.LastIndex, nil
+}
+
 // GetStatusEventsIndex returns the latest index of InstanceStatus events for a given deployment
 func GetStatusEventsIndex(kv *api.KV, deploymentID string) (uint64, error) {
 	_, qm, err := kv.Get(path.Join(consulutil.EventsPrefix, deploymentID), nil)
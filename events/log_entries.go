@@ -167,9 +167,11 @@ func (e LogEntry) Register(content []byte) {
 
 	// Get the value to store and the flat log entry representation to log entry
 	val, flat := e.generateValue()
-	err := consulutil.StoreConsulKey(e.generateKey(), val)
-	if err != nil {
-		log.Printf("Failed to register log in consul for entry:%+v due to error:%+v", e, err)
+	if !isDryRunDeployment(e.deploymentID) {
+		err := consulutil.StoreConsulKey(e.generateKey(), val)
+		if err != nil {
+			log.Printf("Failed to register log in consul for entry:%+v due to error:%+v", e, err)
+		}
 	}
 
 	// log the entry in stdout/stderr in DEBUG mode
@@ -36,7 +36,8 @@ import (
 // Workflow,
 // WorkflowStep
 // AlienTask,
-// AttributeValue
+// AttributeValue,
+// Check
 // )
 type StatusChangeType int
 
@@ -77,6 +78,8 @@ const (
 	EAttributeName
 	// EAttributeValue is event information related to attribute value
 	EAttributeValue
+	// EMessage is event information related to an additional message
+	EMessage
 )
 
 func (i InfoType) String() string {
@@ -115,6 +118,8 @@ func (i InfoType) String() string {
 		return "attribute"
 	case EAttributeValue:
 		return "value"
+	case EMessage:
+		return "message"
 	}
 	return ""
 }
@@ -145,6 +150,9 @@ type WorkflowStepInfo struct {
 // The content is JSON format
 func (e *statusChange) register() (string, error) {
 	e.timestamp = time.Now().Format(time.RFC3339Nano)
+	if isDryRunDeployment(e.deploymentID) {
+		return e.timestamp, nil
+	}
 	eventsPrefix := path.Join(consulutil.EventsPrefix, e.deploymentID)
 
 	// For presentation purpose, each field is in flat json object
@@ -195,6 +203,7 @@ func (e *statusChange) check() error {
 	mandatoryMap := map[StatusChangeType][]InfoType{
 		StatusChangeTypeInstance:       {ENodeID, EInstanceID},
 		StatusChangeTypeAttributeValue: {ENodeID, EAttributeName, EAttributeValue},
+		StatusChangeTypeCheck:          {ENodeID, EInstanceID},
 		StatusChangeTypeCustomCommand:  {ETaskID},
 		StatusChangeTypeScaling:        {ETaskID},
 		StatusChangeTypeWorkflow:       {ETaskID},
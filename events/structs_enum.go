@@ -39,9 +39,11 @@ const (
 	StatusChangeTypeAlienTask
 	// StatusChangeTypeAttributeValue is a StatusChangeType of type AttributeValue
 	StatusChangeTypeAttributeValue
+	// StatusChangeTypeCheck is a StatusChangeType of type Check
+	StatusChangeTypeCheck
 )
 
-const _StatusChangeTypeName = "InstanceDeploymentCustomCommandScalingWorkflowWorkflowStepAlienTaskAttributeValue"
+const _StatusChangeTypeName = "InstanceDeploymentCustomCommandScalingWorkflowWorkflowStepAlienTaskAttributeValueCheck"
 
 var _StatusChangeTypeMap = map[StatusChangeType]string{
 	0: _StatusChangeTypeName[0:8],
@@ -52,6 +54,7 @@ var _StatusChangeTypeMap = map[StatusChangeType]string{
 	5: _StatusChangeTypeName[46:58],
 	6: _StatusChangeTypeName[58:67],
 	7: _StatusChangeTypeName[67:81],
+	8: _StatusChangeTypeName[81:86],
 }
 
 func (i StatusChangeType) String() string {
@@ -78,6 +81,8 @@ var _StatusChangeTypeValue = map[string]StatusChangeType{
 	strings.ToLower(_StatusChangeTypeName[58:67]): 6,
 	_StatusChangeTypeName[67:81]:                  7,
 	strings.ToLower(_StatusChangeTypeName[67:81]): 7,
+	_StatusChangeTypeName[81:86]:                  8,
+	strings.ToLower(_StatusChangeTypeName[81:86]): 8,
 }
 
 // ParseStatusChangeType attempts to convert a string to a StatusChangeType
@@ -33,8 +33,8 @@ require (
 	github.com/denisenkom/go-mssqldb v0.0.0-20190204142019-df6d76eb9289 // indirect
 	github.com/docker/distribution v0.0.0-20180327202408-83389a148052d74ac602f5f1d62f86ff2f3c4aa5 // indirect
 	github.com/docker/docker v0.0.0-20170504205632-89658bed64c2
-	github.com/docker/go-connections v0.3.0 // indirect
-	github.com/docker/go-units v0.3.3 // indirect
+	github.com/docker/go-connections v0.3.0
+	github.com/docker/go-units v0.3.3
 	github.com/docker/libtrust v0.0.0-20160708172513-aabc10ec26b754e797f9028f4589c5b7bd90dc20 // indirect
 	github.com/duosecurity/duo_api_golang v0.0.0-20190107154727-539434bf0d45 // indirect
 	github.com/dustin/go-humanize v0.0.0-20160623014021-fef948f2d241
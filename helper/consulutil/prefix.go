@@ -49,3 +49,16 @@ const YorcServicePrefix = yorcPrefix + "/service"
 
 // SchedulingKVPrefix is the prefix in Consul KV store for scheduling
 const SchedulingKVPrefix string = yorcPrefix + "/scheduling"
+
+// NotificationsKVPrefix is the prefix in Consul KV store for notifications (webhooks subscriptions)
+const NotificationsKVPrefix string = yorcPrefix + "/notifications"
+
+// AuditKVPrefix is the prefix in Consul KV store for the audit trail of REST API actions
+const AuditKVPrefix string = yorcPrefix + "/audit"
+
+// AutoscalingKVPrefix is the prefix in Consul KV store for autoscaling runtime data
+const AutoscalingKVPrefix string = yorcPrefix + "/autoscaling"
+
+// DryRunDeploymentMarker is the key marking, under the prefix of a deployment, the temporary deployments stored to compute
+// dry-run execution plans. No event nor log is published for those deployments.
+const DryRunDeploymentMarker = ".dry_run"
@@ -39,13 +39,13 @@ type pool struct {
 // connection. If dialing fails, Open returns the error from Dial.
 func (p *pool) openSession(client *SSHClient) (*sshSession, error) {
 	addr := fmt.Sprintf("%s:%d", client.Host, client.Port)
-	k := getUserKey(addr, client.Config)
+	k := getUserKey(addr, client.Config, client.JumpHosts)
 	for {
 		// The algorithm here is to get a connection by reusing existing if any
 		// Then if we open a session. Sometimes open fails due to too many sessions open
 		// in this case we remove the connection from cache and teardown it (wait for other sessions
 		// to be closed and finally close the underlying connection) on next try a new connection is created.
-		c := p.getConn(k, addr, client.Config)
+		c := p.getConn(k, addr, client.Config, client.JumpHosts)
 		if c.err != nil {
 			// Can't open connection stop here
 			p.removeConn(k, c)
@@ -85,6 +85,7 @@ type conn struct {
 	name              string
 	netC              net.Conn
 	c                 *ssh.Client
+	jumpClients       []*ssh.Client
 	ok                chan bool
 	err               error
 	lockSessionsCount sync.Mutex
@@ -94,6 +95,10 @@ type conn struct {
 // closes the ssh client
 func (c *conn) close() {
 	c.c.Close()
+	// Close jump hosts connections starting from the nearest to the target
+	for i := len(c.jumpClients) - 1; i >= 0; i-- {
+		c.jumpClients[i].Close()
+	}
 	metrics.IncrCounter(metricsutil.CleanupMetricKey([]string{"ssh-connections-pool", "closes", c.name}), 1)
 }
 
@@ -131,7 +136,7 @@ func (c *conn) newSession() (*sshSession, error) {
 
 // getConn gets an ssh connection from the pool for key.
 // If none is available, it dials anew.
-func (p *pool) getConn(k, addr string, config *ssh.ClientConfig) *conn {
+func (p *pool) getConn(k, addr string, config *ssh.ClientConfig, jumpHosts []JumpHost) *conn {
 	p.mu.Lock()
 	if p.tab == nil {
 		p.tab = make(map[string]*conn)
@@ -145,7 +150,11 @@ func (p *pool) getConn(k, addr string, config *ssh.ClientConfig) *conn {
 	c = &conn{ok: make(chan bool)}
 	p.tab[k] = c
 	p.mu.Unlock()
-	c.netC, c.c, c.err = p.dial("tcp", addr, config)
+	if len(jumpHosts) > 0 {
+		c.netC, c.c, c.jumpClients, c.err = p.dialThroughJumpHosts("tcp", addr, config, jumpHosts)
+	} else {
+		c.netC, c.c, c.err = p.dial("tcp", addr, config)
+	}
 	// Add context to the error if any
 	c.err = errors.Wrapf(c.err, "failed to open connection on %s", addr)
 	if c.err == nil {
@@ -187,6 +196,59 @@ func (p *pool) dial(network, addr string, config *ssh.ClientConfig) (net.Conn, *
 	return netC, sshC, nil
 }
 
-func getUserKey(addr string, config *ssh.ClientConfig) string {
-	return strconv.Quote(addr) + "-" + strconv.Quote(config.User)
+// dialThroughJumpHosts connects to the first jump host and then reaches each
+// subsequent hop through the previous one until addr is reached.
+// On success, it returns the clients connected to jump hosts as they should be closed
+// once the connection to addr is closed.
+func (p *pool) dialThroughJumpHosts(network, addr string, config *ssh.ClientConfig, jumpHosts []JumpHost) (net.Conn, *ssh.Client, []*ssh.Client, error) {
+	jumpClients := make([]*ssh.Client, 0, len(jumpHosts))
+	closeJumpClients := func() {
+		for i := len(jumpClients) - 1; i >= 0; i-- {
+			jumpClients[i].Close()
+		}
+	}
+	for i, jh := range jumpHosts {
+		jhAddr := fmt.Sprintf("%s:%d", jh.Host, jh.Port)
+		var sshC *ssh.Client
+		var err error
+		if i == 0 {
+			_, sshC, err = p.dial(network, jhAddr, jh.Config)
+		} else {
+			_, sshC, err = dialThrough(jumpClients[i-1], network, jhAddr, jh.Config)
+		}
+		if err != nil {
+			closeJumpClients()
+			return nil, nil, nil, errors.Wrapf(err, "failed to connect to jump host %s", jhAddr)
+		}
+		jumpClients = append(jumpClients, sshC)
+	}
+	netC, sshC, err := dialThrough(jumpClients[len(jumpClients)-1], network, addr, config)
+	if err != nil {
+		closeJumpClients()
+		return nil, nil, nil, err
+	}
+	return netC, sshC, jumpClients, nil
+}
+
+// dialThrough opens an SSH connection to addr using a tunnel through the given SSH client
+func dialThrough(through *ssh.Client, network, addr string, config *ssh.ClientConfig) (net.Conn, *ssh.Client, error) {
+	netC, err := through.Dial(network, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, chans, reqs, err := ssh.NewClientConn(netC, addr, config)
+	if err != nil {
+		netC.Close()
+		return nil, nil, err
+	}
+	return netC, ssh.NewClient(conn, chans, reqs), nil
+}
+
+func getUserKey(addr string, config *ssh.ClientConfig, jumpHosts []JumpHost) string {
+	k := strconv.Quote(addr) + "-" + strconv.Quote(config.User)
+	// Connections going through different jump hosts should not be shared
+	for _, jh := range jumpHosts {
+		k += "-" + strconv.Quote(fmt.Sprintf("%s:%d", jh.Host, jh.Port)) + "-" + strconv.Quote(jh.Config.User)
+	}
+	return k
 }
@@ -80,6 +80,17 @@ type SSHClient struct {
 	Config *ssh.ClientConfig
 	Host   string
 	Port   int
+	// JumpHosts is an optional ordered chain of bastion hosts used to reach Host.
+	// The first one is directly reached, each subsequent one (and finally Host)
+	// is reached through the previous one.
+	JumpHosts []JumpHost
+}
+
+// JumpHost defines an SSH bastion host used to reach a target host
+type JumpHost struct {
+	Config *ssh.ClientConfig
+	Host   string
+	Port   int
 }
 
 // SSHAgent is an SSH agent
@@ -204,17 +215,28 @@ func (client *SSHClient) CopyFile(source io.Reader, remotePath, permissions stri
 	scpHostPort := fmt.Sprintf("%s:%d", client.Host, client.Port)
 	scpClient := scp.NewClient(scpHostPort, client.Config)
 
-	// Connect to the remote server
-	err := scpClient.Connect()
-	if err != nil {
-		return errors.Wrapf(err, "Couldn't establish a connection to the remote host:%q", scpHostPort)
+	if len(client.JumpHosts) > 0 {
+		// The SCP client is not able to go through jump hosts by itself
+		// so use a session of the connections pool instead
+		session, err := client.newSession()
+		if err != nil {
+			return errors.Wrapf(err, "Couldn't establish a connection to the remote host:%q", scpHostPort)
+		}
+		defer session.Close()
+		scpClient.Session = session.Session
+	} else {
+		// Connect to the remote server
+		err := scpClient.Connect()
+		if err != nil {
+			return errors.Wrapf(err, "Couldn't establish a connection to the remote host:%q", scpHostPort)
+		}
+		defer scpClient.Session.Close()
 	}
-	defer scpClient.Session.Close()
 
 	// Create the remote directory
 	remoteDir := path.Dir(remotePath)
 	mkdirCmd := fmt.Sprintf("mkdir -p %s", remoteDir)
-	_, err = client.RunCommand(mkdirCmd)
+	_, err := client.RunCommand(mkdirCmd)
 	if err != nil {
 		return errors.Wrapf(err, "Couldn't create the remote directory:%q", remoteDir)
 	}
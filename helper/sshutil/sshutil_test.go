@@ -75,3 +75,20 @@ func TestReadPrivateKey(t *testing.T) {
 	_, err := ReadPrivateKey("./testdata/test.pem")
 	require.NotNil(t, err)
 }
+
+func TestGetUserKeyWithJumpHosts(t *testing.T) {
+	config := &ssh.ClientConfig{User: "user1"}
+	jumpHosts := []JumpHost{
+		{Config: &ssh.ClientConfig{User: "jump"}, Host: "bastion1", Port: 22},
+	}
+	k := getUserKey("host1:22", config, nil)
+	require.Equal(t, `"host1:22"-"user1"`, k)
+
+	kJump := getUserKey("host1:22", config, jumpHosts)
+	require.Equal(t, `"host1:22"-"user1"-"bastion1:22"-"jump"`, kJump)
+
+	otherJumpHosts := []JumpHost{
+		{Config: &ssh.ClientConfig{User: "jump"}, Host: "bastion2", Port: 22},
+	}
+	require.NotEqual(t, kJump, getUserKey("host1:22", config, otherJumpHosts), "connections through different jump hosts should not share the same key")
+}
@@ -0,0 +1,183 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+// A Subscription is a webhook subscription to deployments events
+type Subscription struct {
+	ID string `json:"id"`
+	// URL is the URL events are posted to
+	URL string `json:"url"`
+	// EventTypes filters events by type (like deployment or workflow), all types are delivered if empty
+	EventTypes []string `json:"event_types,omitempty"`
+	// Deployments filters events by deployment ID, events of all deployments are delivered if empty
+	Deployments []string `json:"deployments,omitempty"`
+	// Statuses filters events by status (like failed or error), all statuses are delivered if empty
+	Statuses []string `json:"statuses,omitempty"`
+	// Secret is used to sign delivered payloads, see Signature
+	Secret string `json:"secret,omitempty"`
+}
+
+// A DeadLetter is the record of an event that couldn't be delivered to a subscription
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	URL            string          `json:"url"`
+	Event          json.RawMessage `json:"event"`
+	Attempts       int             `json:"attempts"`
+	Error          string          `json:"error"`
+	Timestamp      time.Time       `json:"timestamp"`
+}
+
+// Validate checks that a subscription has a valid URL and valid event types
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid webhook url %q", s.URL)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid webhook url %q, expecting an absolute http or https url", s.URL)
+	}
+	for _, t := range s.EventTypes {
+		if _, err := events.ParseStatusChangeType(t); err != nil {
+			return errors.Wrapf(err, "invalid event type %q", t)
+		}
+	}
+	return nil
+}
+
+// matches checks if an event passes the filters of a subscription
+func (s Subscription) matches(event map[string]interface{}) bool {
+	return matchFilter(s.EventTypes, event[events.EType.String()]) &&
+		matchFilter(s.Deployments, event[events.EDeploymentID.String()]) &&
+		matchFilter(s.Statuses, event[events.EStatus.String()])
+}
+
+func matchFilter(filter []string, value interface{}) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	v := fmt.Sprint(value)
+	for _, f := range filter {
+		if strings.EqualFold(f, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSubscription validates and stores a new subscription, its generated ID is returned
+func CreateSubscription(kv *api.KV, s Subscription) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	s.ID = uuid.NewV4().String()
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal webhook subscription")
+	}
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.NotificationsKVPrefix, "subscriptions", s.ID), Value: b}, nil)
+	if err != nil {
+		return "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return s.ID, nil
+}
+
+// GetSubscription returns a subscription or nil if it doesn't exist
+func GetSubscription(kv *api.KV, id string) (*Subscription, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.NotificationsKVPrefix, "subscriptions", id), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, nil
+	}
+	s := new(Subscription)
+	err = json.Unmarshal(kvp.Value, s)
+	return s, errors.Wrapf(err, "failed to read webhook subscription %q", id)
+}
+
+// ListSubscriptions returns all the registered subscriptions
+func ListSubscriptions(kv *api.KV) ([]Subscription, error) {
+	kvps, _, err := kv.List(path.Join(consulutil.NotificationsKVPrefix, "subscriptions")+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	subscriptions := make([]Subscription, 0, len(kvps))
+	for _, kvp := range kvps {
+		var s Subscription
+		if err = json.Unmarshal(kvp.Value, &s); err != nil {
+			return nil, errors.Wrapf(err, "failed to read webhook subscription %q", path.Base(kvp.Key))
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes a subscription and its dead letters
+func DeleteSubscription(kv *api.KV, id string) error {
+	_, err := kv.Delete(path.Join(consulutil.NotificationsKVPrefix, "subscriptions", id), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return DeleteDeadLetters(kv, id)
+}
+
+func storeDeadLetter(kv *api.KV, dl DeadLetter) error {
+	b, err := json.Marshal(dl)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal dead letter")
+	}
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.NotificationsKVPrefix, "dead_letters", dl.SubscriptionID, dl.ID), Value: b}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// ListDeadLetters returns the events that couldn't be delivered to a given subscription
+func ListDeadLetters(kv *api.KV, subscriptionID string) ([]DeadLetter, error) {
+	kvps, _, err := kv.List(path.Join(consulutil.NotificationsKVPrefix, "dead_letters", subscriptionID)+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	deadLetters := make([]DeadLetter, 0, len(kvps))
+	for _, kvp := range kvps {
+		var dl DeadLetter
+		if err = json.Unmarshal(kvp.Value, &dl); err != nil {
+			return nil, errors.Wrapf(err, "failed to read dead letter %q", path.Base(kvp.Key))
+		}
+		deadLetters = append(deadLetters, dl)
+	}
+	return deadLetters, nil
+}
+
+// DeleteDeadLetters deletes the dead letters of a given subscription
+func DeleteDeadLetters(kv *api.KV, subscriptionID string) error {
+	_, err := kv.DeleteTree(path.Join(consulutil.NotificationsKVPrefix, "dead_letters", subscriptionID)+"/", nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		subscription Subscription
+		wantErr      bool
+	}{
+		{"Valid", Subscription{URL: "https://chat.example.com/hooks/yorc", EventTypes: []string{"deployment", "Workflow"}}, false},
+		{"NoFilters", Subscription{URL: "http://localhost:8080/"}, false},
+		{"MissingURL", Subscription{}, true},
+		{"RelativeURL", Subscription{URL: "/hooks/yorc"}, true},
+		{"UnsupportedScheme", Subscription{URL: "ftp://example.com/hooks"}, true},
+		{"UnknownEventType", Subscription{URL: "http://localhost:8080/", EventTypes: []string{"task"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subscription.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	t.Parallel()
+	event := map[string]interface{}{"type": "Workflow", "deploymentId": "dep1", "status": "failed", "workflowId": "backup"}
+	tests := []struct {
+		name         string
+		subscription Subscription
+		want         bool
+	}{
+		{"NoFilters", Subscription{}, true},
+		{"TypeMatch", Subscription{EventTypes: []string{"deployment", "workflow"}}, true},
+		{"TypeMismatch", Subscription{EventTypes: []string{"deployment"}}, false},
+		{"DeploymentMatch", Subscription{Deployments: []string{"dep1"}}, true},
+		{"DeploymentMismatch", Subscription{Deployments: []string{"dep2"}}, false},
+		{"StatusMatch", Subscription{EventTypes: []string{"workflow"}, Statuses: []string{"failed", "error"}}, true},
+		{"StatusMismatch", Subscription{EventTypes: []string{"workflow"}, Statuses: []string{"done"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.subscription.matches(event))
+		})
+	}
+}
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+	// Computed with: echo -n '{"status":"failed"}' | openssl dgst -sha256 -hmac mysecret
+	require.Equal(t, "sha256=9b995a694434a848588a38ab0f50bb5774c5f56bedf1ec4581064648bcd4f91a", Signature("mysecret", []byte(`{"status":"failed"}`)))
+}
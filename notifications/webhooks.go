@@ -0,0 +1,284 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notifications is responsible for delivering deployments events to registered webhooks
+//
+// Only the leader Yorc server delivers events to avoid duplicated notifications.
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const (
+	// SignatureHeader is the HTTP header containing the signature of a delivered payload for subscriptions defining a secret
+	SignatureHeader = "X-Yorc-Signature"
+	// EventTypeHeader is the HTTP header containing the type of the delivered event
+	EventTypeHeader = "X-Yorc-Event"
+	// DeliveryHeader is the HTTP header containing a unique identifier of a delivery, it is the same for all the attempts
+	DeliveryHeader = "X-Yorc-Delivery"
+)
+
+// deliveryAttempts is the maximum number of attempts to deliver an event before recording it as a dead letter
+var deliveryAttempts = 5
+
+// deliveryBackoff is the delay before the first retry of a delivery, it is doubled on each retry
+var deliveryBackoff = 5 * time.Second
+
+var defaultManager *manager
+
+type manager struct {
+	cc             *api.Client
+	httpClient     *http.Client
+	serviceKey     string
+	chShutdown     chan struct{}
+	chStopDelivery chan struct{}
+	isActive       bool
+	isActiveLock   sync.Mutex
+}
+
+func newManager(cc *api.Client) *manager {
+	return &manager{
+		cc:         cc,
+		httpClient: &http.Client{Transport: cleanhttp.DefaultTransport(), Timeout: 30 * time.Second},
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/notifications/leader"),
+		chShutdown: make(chan struct{}),
+	}
+}
+
+// Start allows to instantiate a default notifications manager delivering events to webhooks when this server is the leader
+func Start(cfg config.Configuration, cc *api.Client) {
+	defaultManager = newManager(cc)
+	// Watch leader election for notifications
+	go consulutil.WatchLeaderElection(defaultManager.cc, defaultManager.serviceKey, defaultManager.chShutdown, defaultManager.startDelivery, defaultManager.stopDelivery)
+}
+
+// Stop allows to stop delivering events
+func Stop() {
+	defaultManager.stopDelivery()
+
+	// Stop watch leader election
+	close(defaultManager.chShutdown)
+}
+
+// Signature returns the signature of a payload sent to a subscription having the given secret
+//
+// It is the hex encoded HMAC-SHA256 of the payload prefixed by "sha256=".
+func Signature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func handleError(err error) {
+	err = errors.Wrap(err, "[WARN] Error during events notifications")
+	log.Print(err)
+	log.Debugf("%+v", err)
+}
+
+func (mgr *manager) startDelivery() {
+	mgr.isActiveLock.Lock()
+	defer mgr.isActiveLock.Unlock()
+	if mgr.isActive {
+		log.Println("Notifications service is already running.")
+		return
+	}
+	log.Debugf("Notifications service is now running.")
+	mgr.isActive = true
+	mgr.chStopDelivery = make(chan struct{})
+	go mgr.watchEvents(mgr.chStopDelivery)
+}
+
+func (mgr *manager) stopDelivery() {
+	mgr.isActiveLock.Lock()
+	defer mgr.isActiveLock.Unlock()
+	if mgr.isActive {
+		log.Debugf("Notifications service is about to be stopped")
+		close(mgr.chStopDelivery)
+		mgr.isActive = false
+	}
+}
+
+// watchEvents delivers events to matching subscriptions as they are published
+//
+// The index of the latest dispatched events is stored in Consul, this allows a new leader to resume delivery where
+// the previous one stopped.
+func (mgr *manager) watchEvents(chStop chan struct{}) {
+	kv := mgr.cc.KV()
+	indexKey := path.Join(consulutil.NotificationsKVPrefix, "last_index")
+	waitIndex, err := mgr.lastDispatchedIndex(indexKey)
+	for err != nil {
+		handleError(err)
+		select {
+		case <-time.After(time.Second):
+		case <-chStop:
+			return
+		}
+		waitIndex, err = mgr.lastDispatchedIndex(indexKey)
+	}
+	for {
+		select {
+		case <-chStop:
+			log.Debugf("Ending notifications has been requested: stop it now.")
+			return
+		case <-mgr.chShutdown:
+			log.Debugf("Shutdown has been sent: stop notifications now.")
+			return
+		default:
+		}
+
+		entries, lastIndex, err := events.StatusEventsWithIndexes(kv, "", waitIndex, 5*time.Minute)
+		if err != nil {
+			handleError(err)
+			select {
+			case <-time.After(time.Second):
+			case <-chStop:
+			}
+			continue
+		}
+		if lastIndex == waitIndex {
+			// long pool ended due to a timeout
+			continue
+		}
+		if len(entries) > 0 {
+			subscriptions, err := ListSubscriptions(kv)
+			if err != nil {
+				handleError(err)
+				continue
+			}
+			for _, entry := range entries {
+				mgr.dispatch(subscriptions, entry.Value)
+			}
+		}
+		waitIndex = lastIndex
+		_, err = kv.Put(&api.KVPair{Key: indexKey, Value: []byte(strconv.FormatUint(waitIndex, 10))}, nil)
+		if err != nil {
+			handleError(err)
+		}
+	}
+}
+
+// lastDispatchedIndex returns the index from which events should be delivered
+//
+// If no events were already dispatched, it is the current index of events as past events are not delivered.
+func (mgr *manager) lastDispatchedIndex(indexKey string) (uint64, error) {
+	kv := mgr.cc.KV()
+	kvp, _, err := kv.Get(indexKey, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		index, err := strconv.ParseUint(string(kvp.Value), 10, 64)
+		return index, errors.Wrapf(err, "invalid notifications index %q", string(kvp.Value))
+	}
+	return events.GetStatusEventsIndex(kv, "")
+}
+
+// dispatch asynchronously delivers an event to the subscriptions it matches
+func (mgr *manager) dispatch(subscriptions []Subscription, payload []byte) {
+	var event map[string]interface{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		handleError(errors.Wrap(err, "failed to read event"))
+		return
+	}
+	eventType := fmt.Sprint(event[events.EType.String()])
+	for _, s := range subscriptions {
+		if s.matches(event) {
+			go mgr.deliver(s, eventType, payload)
+		}
+	}
+}
+
+// deliver posts an event to a subscription retrying on failures, the event is recorded as a dead letter if all attempts fail
+func (mgr *manager) deliver(s Subscription, eventType string, payload []byte) {
+	deliveryID := uuid.NewV4().String()
+	backoff := deliveryBackoff
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = mgr.post(s, deliveryID, eventType, payload)
+		if err == nil {
+			return
+		}
+		log.Debugf("Attempt %d to deliver event to webhook %q failed: %v", attempt, s.URL, err)
+		if attempt >= deliveryAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-mgr.chShutdown:
+			return
+		}
+	}
+	log.Printf("[WARN] Failed to deliver event to webhook %q after %d attempts: %v", s.URL, attempt, err)
+	err = storeDeadLetter(mgr.cc.KV(), DeadLetter{
+		ID:             deliveryID,
+		SubscriptionID: s.ID,
+		URL:            s.URL,
+		Event:          payload,
+		Attempts:       attempt,
+		Error:          err.Error(),
+		Timestamp:      time.Now(),
+	})
+	if err != nil {
+		handleError(err)
+	}
+}
+
+func (mgr *manager) post(s Subscription, deliveryID, eventType string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(EventTypeHeader, eventType)
+	if s.Secret != "" {
+		req.Header.Set(SignatureHeader, Signature(s.Secret, payload))
+	}
+	resp, err := mgr.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body to allow connections reuse
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
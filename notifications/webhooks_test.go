@@ -0,0 +1,176 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/events"
+)
+
+type webhookRecorder struct {
+	lock       sync.Mutex
+	nbFailures int
+	calls      int
+	payloads   [][]byte
+	headers    []http.Header
+}
+
+func (wr *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	wr.calls++
+	if wr.calls <= wr.nbFailures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	b, _ := ioutil.ReadAll(r.Body)
+	wr.payloads = append(wr.payloads, b)
+	wr.headers = append(wr.headers, r.Header)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (wr *webhookRecorder) nbPayloads() int {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	return len(wr.payloads)
+}
+
+func testSubscriptions(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	_, err := CreateSubscription(kv, Subscription{URL: "not an url"})
+	require.Error(t, err)
+
+	id, err := CreateSubscription(kv, Subscription{URL: "http://localhost:8080/hook", Deployments: []string{"dep1"}, Secret: "s3cr3t"})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	s, err := GetSubscription(kv, id)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, Subscription{ID: id, URL: "http://localhost:8080/hook", Deployments: []string{"dep1"}, Secret: "s3cr3t"}, *s)
+
+	subscriptions, err := ListSubscriptions(kv)
+	require.NoError(t, err)
+	require.Contains(t, subscriptions, *s)
+
+	err = storeDeadLetter(kv, DeadLetter{ID: "dl1", SubscriptionID: id, URL: s.URL, Event: json.RawMessage(`{}`), Attempts: 1, Error: "failed"})
+	require.NoError(t, err)
+	deadLetters, err := ListDeadLetters(kv, id)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+
+	err = DeleteSubscription(kv, id)
+	require.NoError(t, err)
+	s, err = GetSubscription(kv, id)
+	require.NoError(t, err)
+	require.Nil(t, s)
+	deadLetters, err = ListDeadLetters(kv, id)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 0)
+}
+
+func testDeliver(t *testing.T, client *api.Client) {
+	deliveryBackoff = 10 * time.Millisecond
+	deliveryAttempts = 3
+	mgr := newManager(client)
+	defer close(mgr.chShutdown)
+	payload := []byte(`{"deploymentId":"dep1","status":"failed","type":"Workflow"}`)
+
+	t.Run("RetryUntilSuccess", func(t *testing.T) {
+		recorder := &webhookRecorder{nbFailures: 2}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		s := Subscription{ID: "retry", URL: srv.URL, Secret: "s3cr3t"}
+		mgr.deliver(s, "Workflow", payload)
+
+		require.Equal(t, 3, recorder.calls)
+		require.Equal(t, [][]byte{payload}, recorder.payloads)
+		require.Equal(t, Signature("s3cr3t", payload), recorder.headers[0].Get(SignatureHeader))
+		require.Equal(t, "Workflow", recorder.headers[0].Get(EventTypeHeader))
+		require.NotEmpty(t, recorder.headers[0].Get(DeliveryHeader))
+		deadLetters, err := ListDeadLetters(client.KV(), s.ID)
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 0)
+	})
+	t.Run("DeadLetter", func(t *testing.T) {
+		recorder := &webhookRecorder{nbFailures: 5}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		s := Subscription{ID: "deadletter", URL: srv.URL}
+		mgr.deliver(s, "Workflow", payload)
+
+		require.Equal(t, 3, recorder.calls)
+		deadLetters, err := ListDeadLetters(client.KV(), s.ID)
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 1)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Equal(t, srv.URL, deadLetters[0].URL)
+		require.JSONEq(t, string(payload), string(deadLetters[0].Event))
+		require.Contains(t, deadLetters[0].Error, "503")
+	})
+}
+
+func testWatchEvents(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	recorder := &webhookRecorder{}
+	srv := httptest.NewServer(recorder)
+	defer srv.Close()
+	id, err := CreateSubscription(kv, Subscription{URL: srv.URL, EventTypes: []string{"deployment"}, Deployments: []string{"watchedDep"}})
+	require.NoError(t, err)
+	defer DeleteSubscription(kv, id)
+
+	// Past events are not delivered
+	_, err = events.PublishAndLogDeploymentStatusChange(context.Background(), kv, "watchedDep", "deployed")
+	require.NoError(t, err)
+
+	mgr := newManager(client)
+	mgr.startDelivery()
+	defer func() {
+		mgr.stopDelivery()
+		close(mgr.chShutdown)
+	}()
+	// let the manager start watching events
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = events.PublishAndLogDeploymentStatusChange(context.Background(), kv, "otherDep", "deployment_failed")
+	require.NoError(t, err)
+	_, err = events.PublishAndLogWorkflowStatusChange(context.Background(), kv, "watchedDep", "task1", "backup", "failed")
+	require.NoError(t, err)
+	_, err = events.PublishAndLogDeploymentStatusChange(context.Background(), kv, "watchedDep", "undeployment_in_progress")
+	require.NoError(t, err)
+
+	for i := 0; i < 100 && recorder.nbPayloads() == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	// Give a chance to unexpected deliveries
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 1, recorder.nbPayloads())
+	var event map[string]interface{}
+	err = json.Unmarshal(recorder.payloads[0], &event)
+	require.NoError(t, err)
+	require.Equal(t, "watchedDep", event["deploymentId"])
+	require.Equal(t, "undeployment_in_progress", event["status"])
+}
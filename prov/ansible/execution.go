@@ -126,6 +126,16 @@ type hostConnection struct {
 	instanceID string
 	privateKey string
 	password   string
+	jumpHosts  []jumpHost
+}
+
+// jumpHost is an SSH bastion used to reach a host
+type jumpHost struct {
+	host       string
+	port       int
+	user       string
+	privateKey string
+	password   string
 }
 
 type sshCredentials struct {
@@ -229,6 +239,15 @@ func newExecution(ctx context.Context, kv *api.KV, cfg config.Configuration, tas
 	}
 	var exec execution
 	if isBash || isPython {
+		nativeSSH, err := isNativeSSHExecutorEnabled(kv, cfg, deploymentID)
+		if err != nil {
+			return nil, err
+		}
+		// Operations hosted on the orchestrator are still run by Ansible as they may be sandboxed
+		if nativeSSH && !execCommon.isOrchestratorOperation {
+			exec = &executionNativeSSH{executionCommon: execCommon, isPython: isPython}
+			return exec, exec.resolveExecution()
+		}
 		execScript := &executionScript{executionCommon: execCommon, isPython: isPython}
 		execCommon.ansibleRunner = execScript
 		exec = execScript
@@ -313,12 +332,12 @@ func (e *executionCommon) resolveOperation() error {
 func (e *executionCommon) resolveInstances() error {
 	var err error
 	if e.operation.RelOp.IsRelationshipOperation {
-		e.targetNodeInstances, err = tasks.GetInstances(e.kv, e.taskID, e.deploymentID, e.operation.RelOp.TargetNodeName)
+		e.targetNodeInstances, err = tasks.GetInstancesWithContext(e.ctx, e.kv, e.taskID, e.deploymentID, e.operation.RelOp.TargetNodeName)
 		if err != nil {
 			return err
 		}
 	}
-	e.sourceNodeInstances, err = tasks.GetInstances(e.kv, e.taskID, e.deploymentID, e.NodeName)
+	e.sourceNodeInstances, err = tasks.GetInstancesWithContext(e.ctx, e.kv, e.taskID, e.deploymentID, e.NodeName)
 
 	return err
 }
@@ -400,10 +419,28 @@ func (e *executionCommon) setHostConnection(kv *api.KV, host, instanceID, capTyp
 				return errors.Wrapf(err, "Failed to convert port value:%q to int", port)
 			}
 		}
+		conn.jumpHosts, err = e.getJumpHosts(host, instanceID, conn.user)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// getJumpHosts returns the ordered chain of jump hosts defined in the endpoint credentials
+// of the given host instance
+func (e *executionCommon) getJumpHosts(host, instanceID, defaultUser string) ([]jumpHost, error) {
+	endpointJumpHosts, err := deployments.GetInstanceEndpointJumpHosts(e.kv, e.deploymentID, host, instanceID, defaultUser)
+	if err != nil {
+		return nil, err
+	}
+	var jumpHosts []jumpHost
+	for _, jh := range endpointJumpHosts {
+		jumpHosts = append(jumpHosts, jumpHost{host: jh.Host, port: jh.Port, user: jh.User, password: jh.Password, privateKey: jh.PrivateKey})
+	}
+	return jumpHosts, nil
+}
+
 func (e *executionCommon) resolveHostsOrchestratorLocal(nodeName string, instances []string) error {
 	e.hosts = make(map[string]*hostConnection, len(instances))
 	for i := range instances {
@@ -657,6 +694,18 @@ func (e *executionCommon) resolveOperationOutputPath() error {
 	return nil
 }
 
+// resolveOperationRemotePath sets the remote directories where operation scripts and artifacts are copied
+func (e *executionCommon) resolveOperationRemotePath() {
+	// e.OperationRemoteBaseDir is an unique base temp directory for multiple executions
+	e.OperationRemoteBaseDir = stringutil.UniqueTimestampedName(e.cfg.Ansible.OperationRemoteBaseDir+"_", "")
+	if e.operation.RelOp.IsRelationshipOperation {
+		e.OperationRemotePath = path.Join(e.OperationRemoteBaseDir, e.NodeName, e.relationshipType, e.operation.Name)
+	} else {
+		e.OperationRemotePath = path.Join(e.OperationRemoteBaseDir, e.NodeName, e.operation.Name)
+	}
+	log.Debugf("OperationRemotePath:%s", e.OperationRemotePath)
+}
+
 func (e *executionCommon) addRunnablesSpecificInputsAndOutputs() error {
 	opName := strings.ToLower(e.operation.Name)
 	if !strings.HasPrefix(opName, tosca.RunnableInterfaceName) {
@@ -750,6 +799,14 @@ func (e *executionCommon) resolveExecution() error {
 }
 
 func (e *executionCommon) execute(ctx context.Context, retry bool) error {
+	return e.executeForCurrentInstances(ctx, func(ctx context.Context, currentInstance string) error {
+		return e.executeWithCurrentInstance(ctx, retry, currentInstance)
+	})
+}
+
+// executeForCurrentInstances calls the given function once per instance for per-instance operations
+// or once with an empty current instance otherwise
+func (e *executionCommon) executeForCurrentInstances(ctx context.Context, execFn func(ctx context.Context, currentInstance string) error) error {
 	if e.isPerInstanceOperation {
 		var nodeName string
 		var instances []string
@@ -765,13 +822,13 @@ func (e *executionCommon) execute(ctx context.Context, retry bool) error {
 			instanceName := operations.GetInstanceName(nodeName, instanceID)
 			log.Debugf("Executing operation %q, on node %q, with current instance %q", e.operation.Name, e.NodeName, instanceName)
 			ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instanceID})
-			err := e.executeWithCurrentInstance(ctx, retry, instanceName)
+			err := execFn(ctx, instanceName)
 			if err != nil {
 				return err
 			}
 		}
 	} else {
-		return e.executeWithCurrentInstance(ctx, retry, "")
+		return execFn(ctx, "")
 	}
 	return nil
 }
@@ -847,11 +904,41 @@ func (e *executionCommon) generateHostConnection(ctx context.Context, buffer *by
 		if host.port != 0 && host.port != 22 {
 			buffer.WriteString(fmt.Sprintf(" ansible_ssh_port=%d", host.port))
 		}
+		if len(host.jumpHosts) > 0 {
+			for _, jh := range host.jumpHosts {
+				if jh.privateKey == "" && jh.password != "" {
+					events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, e.deploymentID).Registerf("Ansible provisioning: password authentication is not supported for jump host %q, a private key is expected.", jh.host)
+				}
+			}
+			buffer.WriteString(fmt.Sprintf(" ansible_ssh_common_args='%s'", getJumpHostsSSHArgs(host.jumpHosts, e.cfg.DisableSSHAgent)))
+		}
 	}
 	buffer.WriteString("\n")
 	return nil
 }
 
+// getJumpHostsSSHArgs returns the SSH arguments allowing to reach a host through a chain of jump hosts.
+//
+// The last jump host is used in a ProxyCommand that goes through the previous ones using the ProxyJump
+// option. When the SSH agent is disabled only the private key of the last jump host could be specified,
+// previous ones should be reachable using the default SSH identities.
+func getJumpHostsSSHArgs(jumpHosts []jumpHost, disableSSHAgent bool) string {
+	last := jumpHosts[len(jumpHosts)-1]
+	proxyCmd := "ssh -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null"
+	if is, _ := pathutil.IsValidPath(last.privateKey); disableSSHAgent && is {
+		proxyCmd += " -i " + last.privateKey
+	}
+	if len(jumpHosts) > 1 {
+		hops := make([]string, len(jumpHosts)-1)
+		for i, jh := range jumpHosts[:len(jumpHosts)-1] {
+			hops[i] = fmt.Sprintf("%s@%s:%d", jh.user, jh.host, jh.port)
+		}
+		proxyCmd += " -J " + strings.Join(hops, ",")
+	}
+	proxyCmd += fmt.Sprintf(" -W %%h:%%p -p %d %s@%s", last.port, last.user, last.host)
+	return fmt.Sprintf("-o ConnectionAttempts=20 -o ProxyCommand=\"%s\"", proxyCmd)
+}
+
 func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry bool, currentInstance string) error {
 	// Create a cancel func here to remove docker sandboxes as soon as we exit this function
 	ctx, cancelFn := context.WithCancel(ctx)
@@ -927,77 +1014,19 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 		}
 		var perInstanceInputsBuffer bytes.Buffer
 		for _, varInput := range e.VarInputsNames {
-			if varInput == "INSTANCE" {
-				perInstanceInputsBuffer.WriteString(fmt.Sprintf("INSTANCE: %q\n", instanceName))
-			} else if varInput == "SOURCE_INSTANCE" {
-				if !e.isPerInstanceOperation {
-					perInstanceInputsBuffer.WriteString(fmt.Sprintf("SOURCE_INSTANCE: %q\n", instanceName))
-				} else {
-					if e.isRelationshipTargetNode {
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("SOURCE_INSTANCE: %q\n", currentInstance))
-					} else {
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("SOURCE_INSTANCE: %q\n", instanceName))
-					}
-				}
-			} else if varInput == "TARGET_INSTANCE" {
-				if !e.isPerInstanceOperation {
-					perInstanceInputsBuffer.WriteString(fmt.Sprintf("TARGET_INSTANCE: %q\n", instanceName))
-				} else {
-					if e.isRelationshipTargetNode {
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("TARGET_INSTANCE: %q\n", instanceName))
-					} else {
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("TARGET_INSTANCE: %q\n", currentInstance))
-					}
+			value, envInput, err := e.resolveVarInput(varInput, instanceName, currentInstance)
+			if err != nil {
+				return err
+			}
+			if envInput != nil {
+				value, err = e.encodeEnvInputValue(envInput, ansibleRecipePath)
+				if err != nil {
+					return err
 				}
 			} else {
-				for _, envInput := range e.EnvInputs {
-					if envInput.Name == varInput && (envInput.InstanceName == instanceName || e.isPerInstanceOperation && envInput.InstanceName == currentInstance) {
-						v, err := e.encodeEnvInputValue(envInput, ansibleRecipePath)
-						if err != nil {
-							return err
-						}
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("%s: %s\n", varInput, v))
-						goto NEXT
-					}
-				}
-				if e.operation.RelOp.IsRelationshipOperation {
-					var hostedOn bool
-					hostedOn, err = deployments.IsTypeDerivedFrom(e.kv, e.deploymentID, e.relationshipType, "tosca.relationships.HostedOn")
-					if err != nil {
-						return err
-					} else if hostedOn {
-						// In case of operation for relationships derived from HostedOn we should match the inputs with the same instanceID
-						instanceIDIdx := strings.LastIndex(instanceName, "_")
-						// Get index
-						if instanceIDIdx > 0 {
-							instanceID := instanceName[instanceIDIdx:]
-							for _, envInput := range e.EnvInputs {
-								if envInput.Name == varInput && strings.HasSuffix(envInput.InstanceName, instanceID) {
-									v, err := e.encodeEnvInputValue(envInput, ansibleRecipePath)
-									if err != nil {
-										return err
-									}
-									perInstanceInputsBuffer.WriteString(fmt.Sprintf("%s: %s\n", varInput, v))
-									goto NEXT
-								}
-							}
-						}
-					}
-				}
-				// Not found with the combination inputName/instanceName let's use the first that matches the input name
-				for _, envInput := range e.EnvInputs {
-					if envInput.Name == varInput {
-						v, err := e.encodeEnvInputValue(envInput, ansibleRecipePath)
-						if err != nil {
-							return err
-						}
-						perInstanceInputsBuffer.WriteString(fmt.Sprintf("%s: %s\n", varInput, v))
-						goto NEXT
-					}
-				}
-				return errors.Errorf("Unable to find a suitable input for input name %q and instance %q", varInput, instanceName)
+				value = strconv.Quote(value)
 			}
-		NEXT:
+			perInstanceInputsBuffer.WriteString(fmt.Sprintf("%s: %s\n", varInput, value))
 		}
 		if perInstanceInputsBuffer.Len() > 0 {
 			if err = ioutil.WriteFile(filepath.Join(ansibleHostVarsPath, host.host+".yml"), perInstanceInputsBuffer.Bytes(), 0664); err != nil {
@@ -1042,14 +1071,7 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 		return err
 	}
 
-	// e.OperationRemoteBaseDir is an unique base temp directory for multiple executions
-	e.OperationRemoteBaseDir = stringutil.UniqueTimestampedName(e.cfg.Ansible.OperationRemoteBaseDir+"_", "")
-	if e.operation.RelOp.IsRelationshipOperation {
-		e.OperationRemotePath = path.Join(e.OperationRemoteBaseDir, e.NodeName, e.relationshipType, e.operation.Name)
-	} else {
-		e.OperationRemotePath = path.Join(e.OperationRemoteBaseDir, e.NodeName, e.operation.Name)
-	}
-	log.Debugf("OperationRemotePath:%s", e.OperationRemotePath)
+	e.resolveOperationRemotePath()
 	// Build archives for artifacts
 	for artifactName, artifactPath := range e.Artifacts {
 		tarPath := filepath.Join(ansibleRecipePath, artifactName+".tar")
@@ -1089,46 +1111,105 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
 				return err
 			}
-			for _, line := range records {
-				splits := strings.Split(line[0], "_")
-				instanceID := splits[len(splits)-1]
-				if instanceID != fileInstanceID {
-					continue
-				}
-				if e.Outputs[line[0]] != taskContextOutput {
-					// TODO this should be part of the deployments package
-					if err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.DeploymentKVPrefix, e.deploymentID, "topology", e.Outputs[line[0]]), line[1]); err != nil {
-						return err
-					}
+			if err = e.storeOutputs(records, fileInstanceID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 
-					// Notify attributes on value change
-					ind := strings.LastIndex(e.Outputs[line[0]], "/outputs/")
-					if ind != -1 {
-						outputPath := e.Outputs[line[0]][ind+len("/outputs/"):]
-						data := strings.Split(outputPath, "/")
-						if len(data) > 2 {
-							notifier := &deployments.OperationOutputNotifier{
-								InstanceName:  instanceID,
-								NodeName:      e.NodeName,
-								InterfaceName: data[0],
-								OperationName: data[1],
-								OutputName:    data[2],
-							}
-							err = notifier.NotifyValueChange(e.kv, e.deploymentID)
-							if err != nil {
-								return err
-							}
-						}
+}
+
+// resolveVarInput returns the value of a variable input for the given host instance.
+//
+// INSTANCE, SOURCE_INSTANCE and TARGET_INSTANCE inputs are returned as plain instance names
+// while other inputs are returned as the matching environment input.
+func (e *executionCommon) resolveVarInput(varInput, instanceName, currentInstance string) (string, *operations.EnvInput, error) {
+	switch varInput {
+	case "INSTANCE":
+		return instanceName, nil, nil
+	case "SOURCE_INSTANCE":
+		if e.isPerInstanceOperation && e.isRelationshipTargetNode {
+			return currentInstance, nil, nil
+		}
+		return instanceName, nil, nil
+	case "TARGET_INSTANCE":
+		if e.isPerInstanceOperation && !e.isRelationshipTargetNode {
+			return currentInstance, nil, nil
+		}
+		return instanceName, nil, nil
+	}
+	for _, envInput := range e.EnvInputs {
+		if envInput.Name == varInput && (envInput.InstanceName == instanceName || e.isPerInstanceOperation && envInput.InstanceName == currentInstance) {
+			return "", envInput, nil
+		}
+	}
+	if e.operation.RelOp.IsRelationshipOperation {
+		hostedOn, err := deployments.IsTypeDerivedFrom(e.kv, e.deploymentID, e.relationshipType, "tosca.relationships.HostedOn")
+		if err != nil {
+			return "", nil, err
+		} else if hostedOn {
+			// In case of operation for relationships derived from HostedOn we should match the inputs with the same instanceID
+			instanceIDIdx := strings.LastIndex(instanceName, "_")
+			// Get index
+			if instanceIDIdx > 0 {
+				instanceID := instanceName[instanceIDIdx:]
+				for _, envInput := range e.EnvInputs {
+					if envInput.Name == varInput && strings.HasSuffix(envInput.InstanceName, instanceID) {
+						return "", envInput, nil
 					}
+				}
+			}
+		}
+	}
+	// Not found with the combination inputName/instanceName let's use the first that matches the input name
+	for _, envInput := range e.EnvInputs {
+		if envInput.Name == varInput {
+			return "", envInput, nil
+		}
+	}
+	return "", nil, errors.Errorf("Unable to find a suitable input for input name %q and instance %q", varInput, instanceName)
+}
 
-				} else {
-					tasks.SetTaskData(e.kv, e.taskID, e.NodeName+"-"+instanceID+"-"+strings.Join(splits[0:len(splits)-1], "_"), line[1])
+// storeOutputs stores operation outputs records (output name, value) retrieved from the host of the given instance
+func (e *executionCommon) storeOutputs(records [][]string, fileInstanceID string) error {
+	for _, line := range records {
+		splits := strings.Split(line[0], "_")
+		instanceID := splits[len(splits)-1]
+		if instanceID != fileInstanceID {
+			continue
+		}
+		if e.Outputs[line[0]] != taskContextOutput {
+			// TODO this should be part of the deployments package
+			if err := consulutil.StoreConsulKeyAsString(path.Join(consulutil.DeploymentKVPrefix, e.deploymentID, "topology", e.Outputs[line[0]]), line[1]); err != nil {
+				return err
+			}
+
+			// Notify attributes on value change
+			ind := strings.LastIndex(e.Outputs[line[0]], "/outputs/")
+			if ind != -1 {
+				outputPath := e.Outputs[line[0]][ind+len("/outputs/"):]
+				data := strings.Split(outputPath, "/")
+				if len(data) > 2 {
+					notifier := &deployments.OperationOutputNotifier{
+						InstanceName:  instanceID,
+						NodeName:      e.NodeName,
+						InterfaceName: data[0],
+						OperationName: data[1],
+						OutputName:    data[2],
+					}
+					err := notifier.NotifyValueChange(e.kv, e.deploymentID)
+					if err != nil {
+						return err
+					}
 				}
 			}
+
+		} else {
+			tasks.SetTaskData(e.kv, e.taskID, e.NodeName+"-"+instanceID+"-"+strings.Join(splits[0:len(splits)-1], "_"), line[1])
 		}
 	}
 	return nil
-
 }
 
 func (e *executionCommon) checkAnsibleRetriableError(ctx context.Context, err error) error {
@@ -1239,6 +1320,11 @@ func (e *executionCommon) configureSSHAgent(ctx context.Context) (*sshutil.SSHAg
 			addSSHAgent = true
 			break
 		}
+		for _, jh := range host.jumpHosts {
+			if jh.privateKey != "" {
+				addSSHAgent = true
+			}
+		}
 	}
 	if !addSSHAgent {
 		return nil, nil
@@ -1254,6 +1340,14 @@ func (e *executionCommon) configureSSHAgent(ctx context.Context) (*sshutil.SSHAg
 				return nil, err
 			}
 		}
+		// Jump hosts keys are used by the ssh ProxyCommand through the agent
+		for _, jh := range host.jumpHosts {
+			if jh.privateKey != "" {
+				if err = agent.AddKey(jh.privateKey, 3600); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 	return agent, nil
 }
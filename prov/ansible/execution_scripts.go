@@ -207,6 +207,24 @@ func getExecutionScriptTemplateFnMap(e *executionCommon, ansibleRecipePath strin
 	}
 }
 
+// generateScriptWrapper generates the wrapper in charge of running a Bash or Python script
+// and of retrieving its outputs on the remote host
+func generateScriptWrapper(tmpl *template.Template, data interface{}, isPython bool) ([]byte, error) {
+	wrapper := scriptCustomWrapper
+	if isPython {
+		wrapper = pythonCustomWrapper
+	}
+	wrapTemplate, err := tmpl.Parse(wrapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse wrapper template")
+	}
+	var buffer bytes.Buffer
+	if err = wrapTemplate.Execute(&buffer, data); err != nil {
+		return nil, errors.Wrap(err, "Failed to Generate wrapper template")
+	}
+	return buffer.Bytes(), nil
+}
+
 func (e *executionScript) runAnsible(ctx context.Context, retry bool, currentInstance, ansibleRecipePath string) error {
 	var err error
 	e.ScriptToRun, err = filepath.Abs(filepath.Join(e.OverlayPath, e.Primary))
@@ -228,28 +246,17 @@ func (e *executionScript) runAnsible(ctx context.Context, retry bool, currentIns
 	tmpl := template.New("execTemplate")
 	tmpl = tmpl.Delims("[[[", "]]]")
 	tmpl = tmpl.Funcs(getExecutionScriptTemplateFnMap(e.executionCommon, ansibleRecipePath, outputHandler.getWrappedCommand))
-	wrapTemplate := template.New("execTemplate")
-	wrapTemplate = wrapTemplate.Delims("[[[", "]]]")
-	if e.isPython {
-		wrapTemplate, err = tmpl.Parse(pythonCustomWrapper)
-	} else {
-		wrapTemplate, err = tmpl.Parse(scriptCustomWrapper)
-	}
+	wrapper, err := generateScriptWrapper(tmpl, e, e.isPython)
 	if err != nil {
-		return err
-	}
-	if err := wrapTemplate.Execute(&buffer, e); err != nil {
-		err = errors.Wrap(err, "Failed to Generate wrapper template")
 		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
 		return err
 	}
-	if err := ioutil.WriteFile(e.WrapperLocation, buffer.Bytes(), 0664); err != nil {
+	if err := ioutil.WriteFile(e.WrapperLocation, wrapper, 0664); err != nil {
 		err = errors.Wrap(err, "Failed to write playbook file")
 		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
 		return err
 	}
 
-	buffer.Reset()
 	tmpl, err = tmpl.Parse(shellAnsiblePlaybook)
 	if err != nil {
 		err = errors.Wrap(err, "Failed to Generate ansible playbook")
@@ -0,0 +1,391 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/sshutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+// nativeSSHExecutorMetadata is the topology template metadata allowing to enable or disable
+// the native SSH executor for a given deployment, it overrides the Yorc configuration
+const nativeSSHExecutorMetadata = "yorc.execution.native_ssh_executor"
+
+// isNativeSSHExecutorEnabled checks if Bash and Python operations of a deployment should be run
+// directly over SSH instead of using Ansible
+func isNativeSSHExecutorEnabled(kv *api.KV, cfg config.Configuration, deploymentID string) (bool, error) {
+	found, value, err := deployments.GetTopologyMetadata(kv, deploymentID, nativeSSHExecutorMetadata)
+	if err != nil || !found {
+		return cfg.Ansible.NativeSSHExecutor, err
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid value %q for topology metadata %q, expecting a boolean", value, nativeSSHExecutorMetadata)
+	}
+	return enabled, nil
+}
+
+// envVarNamePattern matches valid shell variable names
+var envVarNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// executionNativeSSH runs Bash and Python scripts on remote hosts directly over SSH
+// using the same wrapper than Ansible executions but without generating any playbook
+type executionNativeSSH struct {
+	*executionCommon
+	isPython bool
+	// succeeded holds the keys of the instances on which the script was successfully run,
+	// like the Ansible retry file, they are skipped when retrying an execution
+	succeeded     map[string]bool
+	succeededLock sync.Mutex
+}
+
+func (e *executionNativeSSH) execute(ctx context.Context, retry bool) error {
+	return e.executeForCurrentInstances(ctx, func(ctx context.Context, currentInstance string) error {
+		return e.runScriptOnHosts(ctx, retry, currentInstance)
+	})
+}
+
+// isSucceeded returns true if the script was already successfully run for the given key
+func (e *executionNativeSSH) isSucceeded(key string) bool {
+	e.succeededLock.Lock()
+	defer e.succeededLock.Unlock()
+	return e.succeeded[key]
+}
+
+func (e *executionNativeSSH) setSucceeded(key string) {
+	e.succeededLock.Lock()
+	defer e.succeededLock.Unlock()
+	if e.succeeded == nil {
+		e.succeeded = make(map[string]bool)
+	}
+	e.succeeded[key] = true
+}
+
+func (e *executionNativeSSH) runScriptOnHosts(ctx context.Context, retry bool, currentInstance string) error {
+	e.resolveOperationRemotePath()
+
+	tmpl := template.New("execTemplate")
+	tmpl = tmpl.Delims("[[[", "]]]")
+	tmpl = tmpl.Funcs(getExecutionScriptTemplateFnMap(e.executionCommon, "", e.getWrapperPath))
+	wrapper, err := generateScriptWrapper(tmpl, e, e.isPython)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Node %q: executing %q on remote host(s) over SSH", e.NodeName, e.BasePrimary)
+	var g errgroup.Group
+	for instanceName, host := range e.hosts {
+		key := instanceName + "/" + currentInstance
+		if retry && e.isSucceeded(key) {
+			continue
+		}
+		func(ctx context.Context, instanceName string, host *hostConnection) {
+			g.Go(func() error {
+				err := e.runScriptOnHost(ctx, wrapper, instanceName, currentInstance, host)
+				if err == nil {
+					e.setSucceeded(key)
+				}
+				return err
+			})
+		}(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: host.instanceID}), instanceName, host)
+	}
+	return g.Wait()
+}
+
+func (e *executionNativeSSH) runScriptOnHost(ctx context.Context, wrapper []byte, instanceName, currentInstance string, host *hostConnection) error {
+	client, err := e.getSSHClient(ctx, host)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
+	exports, err := e.getEnvExports(instanceName, currentInstance)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+	envFilePath := e.getEnvFilePath(instanceName)
+
+	if !e.KeepOperationRemotePath {
+		defer func() {
+			_, err := client.RunCommand("rm -rf " + shellQuote(e.OperationRemoteBaseDir))
+			if err != nil {
+				log.Printf("Failed to remove remote directory %q on host %q: %v", e.OperationRemoteBaseDir, host.host, err)
+			}
+		}()
+	}
+
+	// The environment may hold secrets, it is only readable by the remote user and removed once the script is run
+	defer func() {
+		_, err := client.RunCommand("rm -f " + shellQuote(envFilePath))
+		if err != nil {
+			log.Printf("Failed to remove remote file %q on host %q: %v", envFilePath, host.host, err)
+		}
+	}()
+	if err = e.uploadFiles(client, wrapper); err == nil {
+		err = client.CopyFile(strings.NewReader(strings.Join(exports, "\n")+"\n"), envFilePath, "0600")
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to copy operation files on host %q", host.host)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return ansibleRetriableError{root: err}
+	}
+
+	sw, err := client.GetSessionWrapper()
+	if err != nil {
+		err = errors.Wrapf(err, "failed to open SSH session on host %q", host.host)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return ansibleRetriableError{root: err}
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go e.logOutput(ctx, &wg, sw.Stdout, events.LogLevelINFO)
+	go e.logOutput(ctx, &wg, sw.Stderr, events.LogLevelWARN)
+	err = sw.RunCommand(ctx, e.getRemoteCommand(envFilePath))
+	wg.Wait()
+	if err != nil {
+		if exitErr, ok := errors.Cause(err).(*ssh.ExitError); ok {
+			err = errors.Errorf("script %q exited with status %d on host %q", e.BasePrimary, exitErr.ExitStatus(), host.host)
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+			return err
+		}
+		// The connection was lost before the script exited
+		err = errors.Wrapf(err, "failed to run script %q on host %q", e.BasePrimary, host.host)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return ansibleRetriableError{root: err}
+	}
+
+	if !e.HaveOutput {
+		return nil
+	}
+	out, err := client.RunCommand(fmt.Sprintf("cat %s", shellQuote(path.Join(e.OperationRemotePath, "out.csv"))))
+	if err != nil {
+		err = errors.Wrapf(err, "Output retrieving of SSH execution for node %q failed", e.NodeName)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+	r := csv.NewReader(strings.NewReader(out))
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		err = errors.Wrapf(err, "Output retrieving of SSH execution for node %q failed", e.NodeName)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+	return e.storeOutputs(records, host.instanceID)
+}
+
+func (e *executionNativeSSH) getWrapperPath() string {
+	return path.Join(e.OperationRemotePath, "wrapper")
+}
+
+// getEnvFilePath returns the path, relative to the remote user home, of the file holding
+// the environment variables of the script for the given host instance
+func (e *executionNativeSSH) getEnvFilePath(instanceName string) string {
+	return path.Join(e.OperationRemotePath, "env_"+instanceName)
+}
+
+// getRemoteCommand returns the command running the wrapper in a login shell once the given environment file
+// is sourced, environment variables are not passed on the command line as they may hold secrets
+// and as SSH servers generally do not accept to set them
+func (e *executionNativeSSH) getRemoteCommand(envFilePath string) string {
+	wrapper := "$HOME/" + e.getWrapperPath()
+	if e.isPython {
+		wrapper = "$(command -v python || command -v python3) " + wrapper
+	}
+	return fmt.Sprintf(`. "$HOME"/%s && /bin/bash -l -c %s`, shellQuote(envFilePath), shellQuote(wrapper))
+}
+
+// getEnvExports returns the shell exports of operation inputs, artifacts and context for the given host instance
+//
+// An error is returned if a variable name is not a valid shell identifier.
+func (e *executionNativeSSH) getEnvExports(instanceName, currentInstance string) ([]string, error) {
+	exports := make([]string, 0)
+	var invalidNames []string
+	export := func(name, value string) {
+		if !envVarNamePattern.MatchString(name) {
+			invalidNames = append(invalidNames, name)
+			return
+		}
+		exports = append(exports, fmt.Sprintf("export %s=%s", name, value))
+	}
+	for _, envInput := range e.EnvInputs {
+		name := envInput.Name
+		if envInput.InstanceName != "" {
+			name = envInput.InstanceName + "_" + name
+		}
+		export(name, shellQuote(envInput.Value))
+	}
+	for _, artName := range sortedKeys(e.Artifacts) {
+		export(artName, `"$HOME"/`+shellQuote(path.Join(e.OperationRemotePath, e.Artifacts[artName])))
+	}
+	for _, name := range sortedKeys(e.Context) {
+		export(name, shellQuote(e.Context[name]))
+	}
+	capNames := make([]string, 0, len(e.CapabilitiesCtx))
+	for name := range e.CapabilitiesCtx {
+		capNames = append(capNames, name)
+	}
+	sort.Strings(capNames)
+	for _, name := range capNames {
+		export(name, shellQuote(e.CapabilitiesCtx[name].RawString()))
+	}
+	for _, varInput := range e.VarInputsNames {
+		value, envInput, err := e.resolveVarInput(varInput, instanceName, currentInstance)
+		if err != nil {
+			return nil, err
+		}
+		if envInput != nil {
+			value = envInput.Value
+		}
+		// Values are prefixed by a space as the wrapper removes it (see the wrapper for details)
+		export(varInput, shellQuote(" "+value))
+	}
+	if len(invalidNames) > 0 {
+		return nil, errors.Errorf("invalid environment variable names %q for node %q: names should only contain letters, digits and underscores and not start with a digit", invalidNames, e.NodeName)
+	}
+	return exports, nil
+}
+
+// uploadFiles copies the wrapper, the script to run and operation artifacts on the remote host
+func (e *executionNativeSSH) uploadFiles(client *sshutil.SSHClient, wrapper []byte) error {
+	err := client.CopyFile(bytes.NewReader(wrapper), e.getWrapperPath(), "0744")
+	if err != nil {
+		return err
+	}
+	err = copyLocalFile(client, filepath.Join(e.OverlayPath, e.Primary), path.Join(e.OperationRemotePath, e.BasePrimary), "0744")
+	if err != nil {
+		return err
+	}
+	for _, artPath := range e.Artifacts {
+		localArtPath := filepath.Join(e.OverlayPath, artPath)
+		err = filepath.Walk(localArtPath, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(localArtPath, p)
+			if err != nil {
+				return err
+			}
+			return copyLocalFile(client, p, path.Join(e.OperationRemotePath, artPath, filepath.ToSlash(rel)), fmt.Sprintf("%#o", info.Mode().Perm()))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyLocalFile(client *sshutil.SSHClient, localPath, remotePath, permissions string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open file %q", localPath)
+	}
+	defer f.Close()
+	return client.CopyFile(f, remotePath, permissions)
+}
+
+// logOutput registers each line of a script output as a log entry
+func (e *executionNativeSSH) logOutput(ctx context.Context, wg *sync.WaitGroup, output io.Reader, level events.LogLevel) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		events.WithContextOptionalFields(ctx).NewLogEntry(level, e.deploymentID).RegisterAsString(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("Failed to read output of script %q: %v", e.BasePrimary, err)
+	}
+}
+
+func (e *executionNativeSSH) getSSHClient(ctx context.Context, host *hostConnection) (*sshutil.SSHClient, error) {
+	creds := e.getSSHCredentials(ctx, host, true)
+	conf, err := getSSHClientConfig(creds.user, creds.privateKey, creds.password)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get SSH configuration for host %q", host.host)
+	}
+	client := &sshutil.SSHClient{
+		Config: conf,
+		Host:   host.host,
+		Port:   host.port,
+	}
+	if client.Port == 0 {
+		client.Port = 22
+	}
+	for _, jh := range host.jumpHosts {
+		jhConf, err := getSSHClientConfig(jh.user, jh.privateKey, jh.password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get SSH configuration for jump host %q", jh.host)
+		}
+		client.JumpHosts = append(client.JumpHosts, sshutil.JumpHost{Config: jhConf, Host: jh.host, Port: jh.port})
+	}
+	return client, nil
+}
+
+func getSSHClientConfig(user, privateKey, password string) (*ssh.ClientConfig, error) {
+	conf := &ssh.ClientConfig{
+		User:            user,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+	if privateKey != "" {
+		keyAuth, err := sshutil.ReadPrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		conf.Auth = append(conf.Auth, keyAuth)
+	}
+	if password != "" {
+		conf.Auth = append(conf.Auth, ssh.Password(password))
+	}
+	return conf, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shellQuote returns the given string single-quoted for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ansible
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/prov/operations"
+)
+
+func newTestNativeSSHExecution(isPython bool) *executionNativeSSH {
+	return &executionNativeSSH{
+		executionCommon: &executionCommon{
+			NodeName:            "Welcome",
+			operation:           prov.Operation{Name: "standard.start"},
+			BasePrimary:         "start.sh",
+			OperationRemotePath: ".yorc_1/Welcome/standard.start",
+			EnvInputs: []*operations.EnvInput{
+				{Name: "PORT", InstanceName: "Welcome_0", Value: "8080"},
+				{Name: "MSG", Value: "it's ok"},
+			},
+			VarInputsNames:  []string{"INSTANCE", "PORT"},
+			Artifacts:       map[string]string{"scripts": "my_scripts"},
+			Context:         map[string]string{"NODE": "Welcome"},
+			CapabilitiesCtx: map[string]*deployments.TOSCAValue{"CAP_PORT": {Value: "80"}},
+			Outputs:         map[string]string{"OUT_Welcome_0": "instances/Welcome/0/outputs/standard/start/OUT"},
+			HaveOutput:      true,
+		},
+		isPython: isPython,
+	}
+}
+
+func TestNativeSSHEnvExports(t *testing.T) {
+	t.Parallel()
+	e := newTestNativeSSHExecution(false)
+	exports, err := e.getEnvExports("Welcome_0", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"export Welcome_0_PORT='8080'",
+		`export MSG='it'\''s ok'`,
+		`export scripts="$HOME"/'.yorc_1/Welcome/standard.start/my_scripts'`,
+		"export NODE='Welcome'",
+		"export CAP_PORT='80'",
+		"export INSTANCE=' Welcome_0'",
+		"export PORT=' 8080'",
+	}, exports)
+
+	// Names which are not shell identifiers are rejected
+	e.EnvInputs = append(e.EnvInputs, &operations.EnvInput{Name: "image.tag", Value: "1.0"}, &operations.EnvInput{Name: "X;rm -rf /", Value: "1"})
+	_, err = e.getEnvExports("Welcome_0", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"image.tag"`)
+}
+
+func TestNativeSSHRemoteCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		isPython bool
+		want     string
+	}{
+		{"Bash", false, `. "$HOME"/'.yorc_1/Welcome/standard.start/env_Welcome_0' && /bin/bash -l -c '$HOME/.yorc_1/Welcome/standard.start/wrapper'`},
+		{"Python", true, `. "$HOME"/'.yorc_1/Welcome/standard.start/env_Welcome_0' && /bin/bash -l -c '$(command -v python || command -v python3) $HOME/.yorc_1/Welcome/standard.start/wrapper'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestNativeSSHExecution(tt.isPython)
+			assert.Equal(t, tt.want, e.getRemoteCommand(e.getEnvFilePath("Welcome_0")))
+		})
+	}
+}
+
+func TestNativeSSHWrapper(t *testing.T) {
+	t.Parallel()
+	e := newTestNativeSSHExecution(false)
+	tmpl := template.New("execTest")
+	tmpl = tmpl.Delims("[[[", "]]]")
+	tmpl = tmpl.Funcs(getExecutionScriptTemplateFnMap(e.executionCommon, "", e.getWrapperPath))
+	wrapper, err := generateScriptWrapper(tmpl, e, e.isPython)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(wrapper), `echo OUT_Welcome_0,\"$OUT\" >> $HOME/.yorc_1/Welcome/standard.start/out.csv`), "unexpected wrapper:\n%s", wrapper)
+	assert.True(t, strings.Contains(string(wrapper), ". $HOME/.yorc_1/Welcome/standard.start/start.sh"), "unexpected wrapper:\n%s", wrapper)
+}
@@ -74,6 +74,32 @@ func TestTemplates(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestGetJumpHostsSSHArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		jumpHosts       []jumpHost
+		disableSSHAgent bool
+		want            string
+	}{
+		{"SingleJumpHost", []jumpHost{{host: "bastion1", port: 22, user: "jump", privateKey: "execution_test.go"}}, false,
+			`-o ConnectionAttempts=20 -o ProxyCommand="ssh -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -W %h:%p -p 22 jump@bastion1"`},
+		{"SingleJumpHostNoAgent", []jumpHost{{host: "bastion1", port: 2222, user: "jump", privateKey: "execution_test.go"}}, true,
+			`-o ConnectionAttempts=20 -o ProxyCommand="ssh -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -i execution_test.go -W %h:%p -p 2222 jump@bastion1"`},
+		{"JumpHostsChain", []jumpHost{
+			{host: "bastion1", port: 22, user: "jump1"},
+			{host: "bastion2", port: 2222, user: "jump2"},
+			{host: "bastion3", port: 22, user: "jump3"},
+		}, false,
+			`-o ConnectionAttempts=20 -o ProxyCommand="ssh -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -J jump1@bastion1:22,jump2@bastion2:2222 -W %h:%p -p 22 jump3@bastion3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getJumpHostsSSHArgs(tt.jumpHosts, tt.disableSSHAgent))
+		})
+	}
+}
+
 func testExecution(t *testing.T, srv1 *testutil.TestServer, kv *api.KV) {
 	deploymentID := yorc_testutil.BuildDeploymentID(t)
 	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/execTemplate.yml")
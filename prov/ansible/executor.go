@@ -88,7 +88,7 @@ func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configu
 		return err
 	}
 
-	instances, err := tasks.GetInstances(kv, taskID, deploymentID, nodeName)
+	instances, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,267 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaling
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tasks/collector"
+)
+
+// defaultEvaluationInterval is the default delay between two evaluations of autoscaling policies
+const defaultEvaluationInterval = 30 * time.Second
+
+// defaultQueryTimeout is the default timeout of a metric query
+const defaultQueryTimeout = 10 * time.Second
+
+var defaultAutoscaler *autoscaler
+
+// An autoscaler evaluates the autoscaling policies of deployments and scales their targets accordingly
+//
+// Only the leader Yorc server evaluates policies.
+type autoscaler struct {
+	cc                *api.Client
+	collector         *collector.Collector
+	source            metricsSource
+	interval          time.Duration
+	serviceKey        string
+	chShutdown        chan struct{}
+	chStopEvaluations chan struct{}
+	isActive          bool
+	isActiveLock      sync.Mutex
+}
+
+// Start allows to instantiate a default autoscaler evaluating autoscaling policies when this server is the leader
+//
+// Autoscaling is disabled if no Prometheus address is defined in the autoscaling configuration.
+func Start(cfg config.Configuration, cc *api.Client) {
+	if cfg.Autoscaling.PrometheusAddress == "" {
+		log.Debugf("No Prometheus address defined, metric-driven autoscaling is disabled")
+		return
+	}
+	interval := cfg.Autoscaling.EvaluationInterval
+	if interval <= 0 {
+		interval = defaultEvaluationInterval
+	}
+	timeout := cfg.Autoscaling.QueryTimeout
+	if timeout <= 0 {
+		timeout = defaultQueryTimeout
+	}
+	defaultAutoscaler = &autoscaler{
+		cc:         cc,
+		collector:  collector.NewCollector(cc),
+		source:     newPrometheusSource(cfg.Autoscaling.PrometheusAddress, timeout),
+		interval:   interval,
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/autoscaling/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	// Watch leader election for autoscaler
+	go consulutil.WatchLeaderElection(cc, defaultAutoscaler.serviceKey, defaultAutoscaler.chShutdown, defaultAutoscaler.startEvaluations, defaultAutoscaler.stopEvaluations)
+}
+
+// Stop allows to stop evaluating autoscaling policies
+func Stop() {
+	if defaultAutoscaler == nil {
+		return
+	}
+	defaultAutoscaler.stopEvaluations()
+
+	// Stop watch leader election
+	close(defaultAutoscaler.chShutdown)
+}
+
+func handleError(err error) {
+	err = errors.Wrap(err, "[WARN] Error during autoscaling policies evaluation")
+	log.Print(err)
+	log.Debugf("%+v", err)
+}
+
+func (a *autoscaler) startEvaluations() {
+	a.isActiveLock.Lock()
+	defer a.isActiveLock.Unlock()
+	if a.isActive {
+		log.Println("Autoscaler is already running.")
+		return
+	}
+	log.Debugf("Autoscaler is now running.")
+	a.isActive = true
+	a.chStopEvaluations = make(chan struct{})
+	go a.run(a.chStopEvaluations)
+}
+
+func (a *autoscaler) stopEvaluations() {
+	a.isActiveLock.Lock()
+	defer a.isActiveLock.Unlock()
+	if a.isActive {
+		log.Debugf("Autoscaler is about to be stopped")
+		close(a.chStopEvaluations)
+		a.isActive = false
+	}
+}
+
+func (a *autoscaler) run(chStop chan struct{}) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-chStop:
+			log.Debugf("Ending autoscaler has been requested: stop it now.")
+			return
+		case <-a.chShutdown:
+			log.Debugf("Shutdown has been sent: stop autoscaler now.")
+			return
+		case <-ticker.C:
+			if err := a.evaluate(time.Now()); err != nil {
+				handleError(err)
+			}
+		}
+	}
+}
+
+// evaluate evaluates the autoscaling policies of all deployed deployments at the given time
+func (a *autoscaler) evaluate(now time.Time) error {
+	kv := a.cc.KV()
+	depPaths, _, err := kv.Keys(consulutil.DeploymentKVPrefix+"/", "/", nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, depPath := range depPaths {
+		deploymentID := path.Base(depPath)
+		status, err := deployments.GetDeploymentStatus(kv, deploymentID)
+		if err != nil {
+			if deployments.IsDeploymentNotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		if status != deployments.DEPLOYED && status != deployments.UPDATED {
+			// Scaling or other tasks are in progress
+			continue
+		}
+		policies, err := deployments.GetPoliciesForType(kv, deploymentID, autoscalingPolicyType)
+		if err != nil {
+			return err
+		}
+		for _, policyName := range policies {
+			policy, err := getAutoscalingPolicy(kv, deploymentID, policyName)
+			if err != nil {
+				handleError(errors.Wrapf(err, "invalid autoscaling policy %q of deployment %q", policyName, deploymentID))
+				continue
+			}
+			for _, nodeName := range policy.targets {
+				err = a.evaluateNode(deploymentID, policy, nodeName, now)
+				if err != nil {
+					handleError(errors.Wrapf(err, "failed to evaluate autoscaling policy %q for node %q of deployment %q", policyName, nodeName, deploymentID))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateNode queries the policy metric for a target node and registers a scaling task if needed
+func (a *autoscaler) evaluateNode(deploymentID string, policy *autoscalingPolicy, nodeName string, now time.Time) error {
+	kv := a.cc.KV()
+	query := policy.query(deploymentID, nodeName)
+	value, err := a.source.query(context.Background(), query)
+	if err != nil {
+		return err
+	}
+
+	current, err := deployments.GetNbInstancesForNode(kv, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	nodeMin, err := deployments.GetMinNbInstancesForNode(kv, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	nodeMax, err := deployments.GetMaxNbInstancesForNode(kv, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	delta := policy.scalingDelta(value, current, nodeMin, nodeMax)
+	ctx := events.AddLogOptionalFields(context.Background(), events.LogOptionalFields{events.NodeID: nodeName})
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf(
+		"autoscaling policy %q: metric value for node %q is %v with %d instances (min %d, max %d)", policy.name, nodeName, value, current, nodeMin, nodeMax)
+	if delta == 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf(
+			"autoscaling policy %q: no scaling needed for node %q (scale out threshold %v, scale in threshold %v)",
+			policy.name, nodeName, policy.scaleOutThreshold, policy.scaleInThreshold)
+		return nil
+	}
+
+	lastScalingPath := path.Join(consulutil.AutoscalingKVPrefix, deploymentID, policy.name, nodeName, "last_scaling")
+	kvp, _, err := kv.Get(lastScalingPath, nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		lastScaling, err := time.Parse(time.RFC3339Nano, string(kvp.Value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse last scaling time %q", string(kvp.Value))
+		}
+		if now.Before(lastScaling.Add(policy.cooldown)) {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf(
+				"autoscaling policy %q: scaling node %q by %d instance(s) is skipped as it is in cooldown until %s",
+				policy.name, nodeName, delta, lastScaling.Add(policy.cooldown).Format(time.RFC3339))
+			return nil
+		}
+	}
+
+	var taskID string
+	if delta > 0 {
+		taskID, err = a.collector.RegisterScaleOutTask(deploymentID, nodeName, uint32(delta), nil)
+	} else {
+		taskID, err = a.collector.RegisterScaleInTask(deploymentID, nodeName, uint32(-delta), nil)
+	}
+	if err != nil {
+		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf(
+				"autoscaling policy %q: another task is running, scaling node %q by %d instance(s) is postponed", policy.name, nodeName, delta)
+			return nil
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).Registerf(
+			"autoscaling policy %q failed to scale node %q by %d instance(s): %v", policy.name, nodeName, delta, err)
+		return err
+	}
+	err = consulutil.StoreConsulKeyAsString(lastScalingPath, now.Format(time.RFC3339Nano))
+	if err != nil {
+		return err
+	}
+
+	if delta > 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf(
+			"autoscaling policy %q: metric value %v is above scale out threshold %v, scaling out node %q from %d to %d instances (task %q)",
+			policy.name, value, policy.scaleOutThreshold, nodeName, current, int(current)+delta, taskID)
+	} else {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf(
+			"autoscaling policy %q: metric value %v is below scale in threshold %v, scaling in node %q from %d to %d instances (task %q)",
+			policy.name, value, policy.scaleInThreshold, nodeName, current, int(current)+delta, taskID)
+	}
+	return nil
+}
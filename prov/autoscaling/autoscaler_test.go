@@ -0,0 +1,161 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaling
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tasks/collector"
+)
+
+type mockMetricsSource struct {
+	values map[string]float64
+}
+
+func (m *mockMetricsSource) query(ctx context.Context, query string) (float64, error) {
+	v, ok := m.values[query]
+	if !ok {
+		return 0, errors.Errorf("unexpected query %q", query)
+	}
+	return v, nil
+}
+
+func storeAutoscalingDeployment(t *testing.T, client *api.Client, deploymentID string) {
+	kv := client.KV()
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/autoscaling.yaml")
+	require.Nil(t, err)
+	for _, instance := range []string{"0", "1"} {
+		_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology/instances/Compute", instance, "attributes/state"), Value: []byte("started")}, nil)
+		require.Nil(t, err)
+	}
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.DeploymentKVPrefix, deploymentID, "status"), Value: []byte(deployments.DEPLOYED.String())}, nil)
+	require.Nil(t, err)
+}
+
+func requireDeploymentLogContains(t *testing.T, kv *api.KV, deploymentID, content string) {
+	t.Helper()
+	logs, _, err := events.LogsEvents(kv, deploymentID, 0, time.Second)
+	require.Nil(t, err)
+	for _, logEntry := range logs {
+		var logMap map[string]string
+		require.Nil(t, json.Unmarshal(logEntry, &logMap))
+		if strings.Contains(logMap["content"], content) {
+			return
+		}
+	}
+	require.Fail(t, "missing deployment log", "no log of deployment %q contains %q", deploymentID, content)
+}
+
+func testGetAutoscalingPolicy(t *testing.T, client *api.Client) {
+	deploymentID := "testGetAutoscalingPolicy"
+	storeAutoscalingDeployment(t, client, deploymentID)
+
+	p, err := getAutoscalingPolicy(client.KV(), deploymentID, "CPUAutoscaling")
+	require.Nil(t, err)
+	require.Equal(t, []string{"Compute"}, p.targets)
+	require.Equal(t, uint32(1), p.minInstances)
+	require.Equal(t, uint32(3), p.maxInstances)
+	require.Equal(t, uint32(1), p.increment)
+	require.Equal(t, 0.8, p.scaleOutThreshold)
+	require.Equal(t, 0.2, p.scaleInThreshold)
+	require.Equal(t, time.Hour, p.cooldown)
+	require.Equal(t, "avg(cpu_usage{deployment='testGetAutoscalingPolicy',node='Compute'})", p.query(deploymentID, "Compute"))
+}
+
+func newTestAutoscaler(client *api.Client, source metricsSource) *autoscaler {
+	return &autoscaler{
+		cc:        client,
+		collector: collector.NewCollector(client),
+		source:    source,
+	}
+}
+
+func testEvaluateScaleOut(t *testing.T, client *api.Client) {
+	deploymentID := "testEvaluateScaleOut"
+	storeAutoscalingDeployment(t, client, deploymentID)
+	kv := client.KV()
+	a := newTestAutoscaler(client, &mockMetricsSource{values: map[string]float64{
+		"avg(cpu_usage{deployment='testEvaluateScaleOut',node='Compute'})": 0.95,
+	}})
+
+	now := time.Now()
+	p, err := getAutoscalingPolicy(kv, deploymentID, "CPUAutoscaling")
+	require.Nil(t, err)
+	err = a.evaluateNode(deploymentID, p, "Compute", now)
+	require.Nil(t, err)
+
+	tasksIDs, err := tasks.GetTasksIdsForTarget(kv, deploymentID)
+	require.Nil(t, err)
+	require.Len(t, tasksIDs, 1)
+	taskType, err := tasks.GetTaskType(kv, tasksIDs[0])
+	require.Nil(t, err)
+	require.Equal(t, tasks.TaskTypeScaleOut, taskType)
+	delta, err := tasks.GetTaskData(kv, tasksIDs[0], "instancesDelta")
+	require.Nil(t, err)
+	require.Equal(t, "1", delta)
+
+	// Mark the task as done, the cooldown should prevent another scaling
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.TasksPrefix, tasksIDs[0], "status"), Value: []byte(strconv.Itoa(int(tasks.TaskStatusDONE)))}, nil)
+	require.Nil(t, err)
+	err = a.evaluateNode(deploymentID, p, "Compute", now.Add(time.Minute))
+	require.Nil(t, err)
+	tasksIDs, err = tasks.GetTasksIdsForTarget(kv, deploymentID)
+	require.Nil(t, err)
+	require.Len(t, tasksIDs, 1, "cooldown should prevent another scaling")
+	requireDeploymentLogContains(t, kv, deploymentID, "is in cooldown until")
+}
+
+func testEvaluateScaleIn(t *testing.T, client *api.Client) {
+	deploymentID := "testEvaluateScaleIn"
+	storeAutoscalingDeployment(t, client, deploymentID)
+	kv := client.KV()
+	a := newTestAutoscaler(client, &mockMetricsSource{values: map[string]float64{
+		"avg(cpu_usage{deployment='testEvaluateScaleIn',node='Compute'})": 0.05,
+	}})
+
+	err := a.evaluate(time.Now())
+	require.Nil(t, err)
+
+	tasksIDs, err := tasks.GetTasksIdsForTarget(kv, deploymentID)
+	require.Nil(t, err)
+	require.Len(t, tasksIDs, 1)
+	taskType, err := tasks.GetTaskType(kv, tasksIDs[0])
+	require.Nil(t, err)
+	require.Equal(t, tasks.TaskTypeScaleIn, taskType)
+	instances, err := tasks.GetInstances(kv, tasksIDs[0], deploymentID, "Compute")
+	require.Nil(t, err)
+	require.Equal(t, []string{"1"}, instances)
+
+	// After the cooldown, scaling is postponed as the scale in task is still running
+	p, err := getAutoscalingPolicy(kv, deploymentID, "CPUAutoscaling")
+	require.Nil(t, err)
+	err = a.evaluateNode(deploymentID, p, "Compute", time.Now().Add(2*time.Hour))
+	require.Nil(t, err)
+	requireDeploymentLogContains(t, kv, deploymentID, "another task is running")
+}
@@ -0,0 +1,169 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaling
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+)
+
+const autoscalingPolicyType = "yorc.policies.Autoscaling"
+
+// autoscalingPolicy defines when the instances of its targets should be scaled out or in
+type autoscalingPolicy struct {
+	name    string
+	targets []string
+	// metric is a Prometheus query, ${DEPLOYMENT_ID} and ${NODE} are replaced by the deployment ID and the target node name
+	metric            string
+	minInstances      uint32
+	maxInstances      uint32
+	scaleOutThreshold float64
+	scaleInThreshold  float64
+	increment         uint32
+	cooldown          time.Duration
+}
+
+// getAutoscalingPolicy reads and checks the definition of an autoscaling policy
+func getAutoscalingPolicy(kv *api.KV, deploymentID, policyName string) (*autoscalingPolicy, error) {
+	p := &autoscalingPolicy{name: policyName}
+	var err error
+	p.targets, err = deployments.GetPolicyTargets(kv, deploymentID, policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "metric")
+	if err != nil {
+		return nil, err
+	}
+	if value == nil || value.RawString() == "" {
+		return nil, errors.Errorf("Missing mandatory property metric for autoscaling policy:%q", policyName)
+	}
+	p.metric = value.RawString()
+
+	p.minInstances, err = getUintProperty(kv, deploymentID, policyName, "min_instances", 1)
+	if err != nil {
+		return nil, err
+	}
+	p.maxInstances, err = getUintProperty(kv, deploymentID, policyName, "max_instances", 0)
+	if err != nil {
+		return nil, err
+	}
+	if p.maxInstances == 0 || p.maxInstances < p.minInstances {
+		return nil, errors.Errorf("max_instances of autoscaling policy:%q should be greater than or equal to min_instances and to 1", policyName)
+	}
+	p.increment, err = getUintProperty(kv, deploymentID, policyName, "increment", 1)
+	if err != nil {
+		return nil, err
+	}
+	if p.increment == 0 {
+		return nil, errors.Errorf("increment of autoscaling policy:%q should be greater than or equal to 1", policyName)
+	}
+
+	p.scaleOutThreshold, err = getFloatProperty(kv, deploymentID, policyName, "scale_out_threshold")
+	if err != nil {
+		return nil, err
+	}
+	p.scaleInThreshold, err = getFloatProperty(kv, deploymentID, policyName, "scale_in_threshold")
+	if err != nil {
+		return nil, err
+	}
+	if p.scaleInThreshold >= p.scaleOutThreshold {
+		return nil, errors.Errorf("scale_in_threshold of autoscaling policy:%q should be lower than scale_out_threshold", policyName)
+	}
+
+	p.cooldown = 5 * time.Minute
+	value, err = deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "cooldown")
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.RawString() != "" {
+		p.cooldown, err = time.ParseDuration(value.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve cooldown as correct duration for autoscaling policy:%q", policyName)
+		}
+	}
+	return p, nil
+}
+
+func getUintProperty(kv *api.KV, deploymentID, policyName, propertyName string, defaultValue uint32) (uint32, error) {
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, propertyName)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil || value.RawString() == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.ParseUint(value.RawString(), 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to retrieve %s as correct positive integer for autoscaling policy:%q", propertyName, policyName)
+	}
+	return uint32(i), nil
+}
+
+func getFloatProperty(kv *api.KV, deploymentID, policyName, propertyName string) (float64, error) {
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, propertyName)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil || value.RawString() == "" {
+		return 0, errors.Errorf("Missing mandatory property %s for autoscaling policy:%q", propertyName, policyName)
+	}
+	f, err := strconv.ParseFloat(value.RawString(), 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to retrieve %s as correct number for autoscaling policy:%q", propertyName, policyName)
+	}
+	return f, nil
+}
+
+// query returns the metric query of the policy for a given target node
+func (p *autoscalingPolicy) query(deploymentID, nodeName string) string {
+	return strings.NewReplacer("${DEPLOYMENT_ID}", deploymentID, "${NODE}", nodeName).Replace(p.metric)
+}
+
+// scalingDelta returns the number of instances to add (positive) or remove (negative) for a given metric value.
+//
+// The policy bounds are restricted to the bounds of the node scalable capability.
+func (p *autoscalingPolicy) scalingDelta(value float64, current, nodeMin, nodeMax uint32) int {
+	minInstances := p.minInstances
+	if minInstances < nodeMin {
+		minInstances = nodeMin
+	}
+	maxInstances := p.maxInstances
+	if maxInstances > nodeMax {
+		maxInstances = nodeMax
+	}
+	switch {
+	case value > p.scaleOutThreshold && current < maxInstances:
+		delta := p.increment
+		if current+delta > maxInstances {
+			delta = maxInstances - current
+		}
+		return int(delta)
+	case value < p.scaleInThreshold && current > minInstances:
+		delta := p.increment
+		if current-minInstances < delta {
+			delta = current - minInstances
+		}
+		return -int(delta)
+	}
+	return 0
+}
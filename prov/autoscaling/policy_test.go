@@ -0,0 +1,56 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalingDelta(t *testing.T) {
+	p := &autoscalingPolicy{
+		minInstances:      2,
+		maxInstances:      6,
+		scaleOutThreshold: 80,
+		scaleInThreshold:  20,
+		increment:         2,
+	}
+	tests := []struct {
+		name     string
+		value    float64
+		current  uint32
+		nodeMin  uint32
+		nodeMax  uint32
+		expected int
+	}{
+		{"BetweenThresholds", 50, 3, 1, 10, 0},
+		{"ScaleOut", 90, 3, 1, 10, 2},
+		{"ScaleOutLimitedByPolicyMax", 90, 5, 1, 10, 1},
+		{"ScaleOutLimitedByNodeMax", 90, 3, 1, 4, 1},
+		{"ScaleOutAtMax", 90, 6, 1, 10, 0},
+		{"ScaleIn", 10, 5, 1, 10, -2},
+		{"ScaleInLimitedByPolicyMin", 10, 3, 1, 10, -1},
+		{"ScaleInLimitedByNodeMin", 10, 4, 3, 10, -1},
+		{"ScaleInAtMin", 10, 2, 1, 10, 0},
+		{"OnScaleOutThreshold", 80, 3, 1, 10, 0},
+		{"OnScaleInThreshold", 20, 3, 1, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.scalingDelta(tt.value, tt.current, tt.nodeMin, tt.nodeMax))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A metricsSource retrieves the current value of a metric
+type metricsSource interface {
+	query(ctx context.Context, query string) (float64, error)
+}
+
+// prometheusSource retrieves metrics values using the Prometheus HTTP API
+type prometheusSource struct {
+	address string
+	client  *http.Client
+}
+
+type prometheusResponse struct {
+	Status    string         `json:"status"`
+	ErrorType string         `json:"errorType,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Data      prometheusData `json:"data"`
+}
+
+type prometheusData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+type prometheusSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+}
+
+func newPrometheusSource(address string, timeout time.Duration) *prometheusSource {
+	return &prometheusSource{
+		address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// query evaluates an instant query and returns its value.
+//
+// Scalar results are returned as is, the value of a vector result is the average of its samples.
+func (s *prometheusSource) query(ctx context.Context, query string) (float64, error) {
+	req, err := http.NewRequest(http.MethodGet, s.address+"/api/v1/query?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to build Prometheus query %q", query)
+	}
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to send Prometheus query %q", query)
+	}
+	defer resp.Body.Close()
+
+	var promResp prometheusResponse
+	err = json.NewDecoder(resp.Body).Decode(&promResp)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to decode Prometheus response to query %q (HTTP status %q)", query, resp.Status)
+	}
+	if promResp.Status != "success" {
+		return 0, errors.Errorf("Prometheus query %q failed: %s: %s", query, promResp.ErrorType, promResp.Error)
+	}
+
+	switch promResp.Data.ResultType {
+	case "scalar":
+		var value []interface{}
+		if err = json.Unmarshal(promResp.Data.Result, &value); err != nil {
+			return 0, errors.Wrapf(err, "failed to decode scalar result of Prometheus query %q", query)
+		}
+		return parseSampleValue(value)
+	case "vector":
+		var samples []prometheusSample
+		if err = json.Unmarshal(promResp.Data.Result, &samples); err != nil {
+			return 0, errors.Wrapf(err, "failed to decode vector result of Prometheus query %q", query)
+		}
+		if len(samples) == 0 {
+			return 0, errors.Errorf("Prometheus query %q returned no data", query)
+		}
+		var sum float64
+		for _, sample := range samples {
+			v, err := parseSampleValue(sample.Value)
+			if err != nil {
+				return 0, errors.Wrapf(err, "invalid sample in result of Prometheus query %q", query)
+			}
+			sum += v
+		}
+		return sum / float64(len(samples)), nil
+	}
+	return 0, errors.Errorf("unsupported result type %q for Prometheus query %q, expecting a scalar or an instant vector", promResp.Data.ResultType, query)
+}
+
+// parseSampleValue parses a Prometheus sample value formatted as [<unix_time>, "<value>"]
+func parseSampleValue(value []interface{}) (float64, error) {
+	if len(value) != 2 {
+		return 0, errors.Errorf("malformed sample value %v", value)
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, errors.Errorf("malformed sample value %v", value)
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/hashicorp/consul/api"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+)
+
+const (
+	infrastructureName = "docker"
+	defaultAPIVersion  = "1.25"
+)
+
+// getDockerHost returns the Docker Engine API endpoint managing the given node instance.
+//
+// If the node is hosted on a Compute the endpoint is built from the IP address of this Compute,
+// otherwise the host defined in the docker infrastructure configuration is returned.
+// An empty string means that the endpoint is defined by the environment of the Yorc server.
+func getDockerHost(kv *api.KV, cfg config.Configuration, deploymentID, nodeName, instanceName string) (string, error) {
+	infra := cfg.Infrastructures[infrastructureName]
+	hostNode, hostInstance, err := deployments.GetHostedOnNodeInstance(kv, deploymentID, nodeName, instanceName)
+	if err != nil {
+		return "", err
+	}
+	if hostNode == "" {
+		return infra.GetString("host"), nil
+	}
+	ipAddress, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, hostInstance, "endpoint", "ip_address")
+	if err != nil {
+		return "", err
+	}
+	if ipAddress == nil || ipAddress.RawString() == "" {
+		return "", errors.Errorf("no endpoint ip_address found for host node %q instance %q of node %q", hostNode, hostInstance, nodeName)
+	}
+	port := infra.GetInt("host_port")
+	if port == 0 {
+		port = 2375
+		if isTLSConfigured(infra) {
+			port = 2376
+		}
+	}
+	return fmt.Sprintf("tcp://%s:%d", ipAddress.RawString(), port), nil
+}
+
+func isTLSConfigured(infra config.DynamicMap) bool {
+	return infra.GetString("ca_cert") != "" || infra.GetString("cert_file") != ""
+}
+
+// newDockerClient returns a client of the Docker Engine API available at the given host
+func newDockerClient(cfg config.Configuration, host string) (*client.Client, error) {
+	if host == "" {
+		cli, err := client.NewEnvClient()
+		return cli, errors.Wrap(err, "failed to create Docker client from environment")
+	}
+	infra := cfg.Infrastructures[infrastructureName]
+	var httpClient *http.Client
+	if isTLSConfigured(infra) && strings.HasPrefix(host, "tcp://") {
+		tlsc, err := tlsconfig.Client(tlsconfig.Options{
+			CAFile:             infra.GetString("ca_cert"),
+			CertFile:           infra.GetString("cert_file"),
+			KeyFile:            infra.GetString("key_file"),
+			InsecureSkipVerify: infra.GetBool("insecure"),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create Docker client TLS configuration")
+		}
+		httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsc}}
+	}
+	cli, err := client.NewClient(host, infra.GetStringOrDefault("api_version", defaultAPIVersion), httpClient, nil)
+	return cli, errors.Wrapf(err, "failed to create Docker client for host %q", host)
+}
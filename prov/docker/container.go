@@ -0,0 +1,313 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/prov/scheduling"
+)
+
+const defaultLogsPeriod = 10 * time.Second
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// sanitizeName returns a name usable as a Docker container or volume name
+func sanitizeName(parts ...string) string {
+	return invalidNameChars.ReplaceAllString(strings.Join(parts, "-"), "_")
+}
+
+func (e *execution) getNodePropertyString(propertyName string) (string, error) {
+	value, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.nodeName, propertyName)
+	if err != nil || value == nil {
+		return "", err
+	}
+	return value.RawString(), nil
+}
+
+// getContainerImage returns the image property of the node if set
+// or the file of the create operation implementation artifact otherwise
+func (e *execution) getContainerImage() (string, error) {
+	image, err := e.getNodePropertyString("image")
+	if err != nil || image != "" {
+		return image, err
+	}
+	nodeTemplateImpl, nodeTypeImpl := "", ""
+	isNodeImpl, err := deployments.IsNodeTemplateImplementingOperation(e.kv, e.deploymentID, e.nodeName, "standard.create")
+	if err != nil {
+		return "", err
+	}
+	if isNodeImpl {
+		nodeTemplateImpl = e.nodeName
+	} else {
+		nodeTypeImpl, err = deployments.GetNodeTypeImplementingAnOperation(e.kv, e.deploymentID, e.nodeName, "standard.create")
+		if err != nil {
+			return "", errors.Errorf("no image defined for node %q: the image property is not set and there is no create operation implementation", e.nodeName)
+		}
+	}
+	return deployments.GetOperationImplementationFile(e.kv, e.deploymentID, nodeTemplateImpl, nodeTypeImpl, "standard.create")
+}
+
+// getVolumesBinds returns the volume binds of the container based on its use_volume requirements
+func (e *execution) getVolumesBinds() ([]string, error) {
+	useVolumeKeys, err := deployments.GetRequirementsKeysByTypeForNode(e.kv, e.deploymentID, e.nodeName, "use_volume")
+	if err != nil {
+		return nil, err
+	}
+	binds := make([]string, 0, len(useVolumeKeys))
+	for _, useVolumeReqPrefix := range useVolumeKeys {
+		requirementIndex := deployments.GetRequirementIndexFromRequirementKey(useVolumeReqPrefix)
+		volumeNodeName, err := deployments.GetTargetNodeForRequirement(e.kv, e.deploymentID, e.nodeName, requirementIndex)
+		if err != nil {
+			return nil, err
+		}
+		mountPath, err := deployments.GetCapabilityPropertyValue(e.kv, e.deploymentID, volumeNodeName, "mount", "mount_path")
+		if err != nil {
+			return nil, err
+		}
+		if mountPath == nil || mountPath.RawString() == "" {
+			return nil, errors.Errorf("no mount_path defined on the mount capability of volume %q used by node %q", volumeNodeName, e.nodeName)
+		}
+		volumeName, err := getVolumeName(e.kv, e.deploymentID, volumeNodeName)
+		if err != nil {
+			return nil, err
+		}
+		binds = append(binds, volumeName+":"+mountPath.RawString())
+	}
+	return binds, nil
+}
+
+// generateContainerConfig generates the configurations of a container
+func generateContainerConfig(image, runCmd, ports string, labels map[string]string) (*container.Config, *container.HostConfig, error) {
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(strings.Fields(ports))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid docker_ports %q", ports)
+	}
+	cc := &container.Config{
+		Image:        image,
+		ExposedPorts: exposedPorts,
+		Labels:       labels,
+	}
+	if cmd := strings.Fields(runCmd); len(cmd) > 0 {
+		cc.Cmd = strslice.StrSlice(cmd)
+	}
+	hc := &container.HostConfig{
+		PortBindings: portBindings,
+	}
+	return cc, hc, nil
+}
+
+// formatPublishedPorts formats published ports using the docker_ports property format
+func formatPublishedPorts(ports nat.PortMap) string {
+	published := make([]string, 0)
+	for port, bindings := range ports {
+		containerPort := port.Port()
+		if port.Proto() != "tcp" {
+			containerPort += "/" + port.Proto()
+		}
+		for _, binding := range bindings {
+			if binding.HostPort == "" {
+				continue
+			}
+			published = append(published, binding.HostPort+":"+containerPort)
+		}
+	}
+	sort.Strings(published)
+	return strings.Join(published, " ")
+}
+
+func (e *execution) createContainer(ctx context.Context, instance string) error {
+	dockerHost, err := getDockerHost(e.kv, e.cfg, e.deploymentID, e.nodeName, instance)
+	if err != nil {
+		return err
+	}
+	cli, err := newDockerClient(e.cfg, dockerHost)
+	if err != nil {
+		return err
+	}
+
+	image, err := e.getContainerImage()
+	if err != nil {
+		return err
+	}
+	runCmd, err := e.getNodePropertyString("docker_run_cmd")
+	if err != nil {
+		return err
+	}
+	ports, err := e.getNodePropertyString("docker_ports")
+	if err != nil {
+		return err
+	}
+	labels := map[string]string{
+		"yorc.deployment": e.deploymentID,
+		"yorc.node":       e.nodeName,
+		"yorc.instance":   instance,
+	}
+	cc, hc, err := generateContainerConfig(image, runCmd, ports, labels)
+	if err != nil {
+		return err
+	}
+	hc.Binds, err = e.getVolumesBinds()
+	if err != nil {
+		return err
+	}
+	cpuShare, err := e.getNodePropertyString("cpu_share")
+	if err != nil {
+		return err
+	}
+	if cpuShare != "" {
+		hc.CPUShares, err = strconv.ParseInt(cpuShare, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid cpu_share %q", cpuShare)
+		}
+	}
+	memShare, err := e.getNodePropertyString("mem_share")
+	if err != nil {
+		return err
+	}
+	if memShare != "" {
+		hc.ShmSize, err = units.RAMInBytes(memShare)
+		if err != nil {
+			return errors.Wrapf(err, "invalid mem_share %q", memShare)
+		}
+	}
+	dockerOptions, err := e.getNodePropertyString("docker_options")
+	if err != nil {
+		return err
+	}
+	if dockerOptions != "" {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, e.deploymentID).Registerf("docker_options %q of node %q are not supported by the Docker executor and will be ignored", dockerOptions, e.nodeName)
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Pulling docker image: %s", image)
+	pullResp, err := cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	if pullResp != nil {
+		ioutil.ReadAll(pullResp)
+		pullResp.Close()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to pull docker image %q", image)
+	}
+
+	containerName := sanitizeName(e.deploymentID, e.nodeName, instance)
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Creating docker container %q from image: %s", containerName, image)
+	createResp, err := cli.ContainerCreate(ctx, cc, hc, nil, containerName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create docker container %q", containerName)
+	}
+	err = deployments.SetInstanceAttribute(e.deploymentID, e.nodeName, instance, "container_id", createResp.ID)
+	if err != nil {
+		return err
+	}
+	for _, warn := range createResp.Warnings {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, e.deploymentID).Registerf("Docker container %q: %s", containerName, warn)
+	}
+
+	err = cli.ContainerStart(ctx, createResp.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to start docker container %q", containerName)
+	}
+
+	info, err := cli.ContainerInspect(ctx, createResp.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to inspect docker container %q", containerName)
+	}
+	if info.NetworkSettings != nil {
+		ipAddress := info.NetworkSettings.IPAddress
+		if ipAddress == "" {
+			for _, network := range info.NetworkSettings.Networks {
+				if network != nil && network.IPAddress != "" {
+					ipAddress = network.IPAddress
+					break
+				}
+			}
+		}
+		err = deployments.SetInstanceAttribute(e.deploymentID, e.nodeName, instance, "ip_address", ipAddress)
+		if err != nil {
+			return err
+		}
+		err = deployments.SetInstanceAttribute(e.deploymentID, e.nodeName, instance, "published_ports", formatPublishedPorts(info.NetworkSettings.Ports))
+		if err != nil {
+			return err
+		}
+	}
+
+	logsPeriod := e.cfg.Infrastructures[infrastructureName].GetDuration("logs_period")
+	if logsPeriod <= 0 {
+		logsPeriod = defaultLogsPeriod
+	}
+	_, err = scheduling.RegisterAction(e.cc, e.deploymentID, logsPeriod, &prov.Action{
+		ActionType: containerLogsActionType,
+		Data: map[string]string{
+			"containerID": createResp.ID,
+			"dockerHost":  dockerHost,
+			"nodeName":    e.nodeName,
+			"instance":    instance,
+		},
+	})
+	return errors.Wrapf(err, "failed to register logs streaming of docker container %q", containerName)
+}
+
+func (e *execution) deleteContainer(ctx context.Context, instance string) error {
+	dockerHost, err := getDockerHost(e.kv, e.cfg, e.deploymentID, e.nodeName, instance)
+	if err != nil {
+		return err
+	}
+	cli, err := newDockerClient(e.cfg, dockerHost)
+	if err != nil {
+		return err
+	}
+
+	containerID := sanitizeName(e.deploymentID, e.nodeName, instance)
+	idValue, err := deployments.GetInstanceAttributeValue(e.kv, e.deploymentID, e.nodeName, instance, "container_id")
+	if err != nil {
+		return err
+	}
+	if idValue != nil && idValue.RawString() != "" {
+		containerID = idValue.RawString()
+	}
+
+	timeout := 10 * time.Second
+	err = cli.ContainerStop(ctx, containerID, &timeout)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Docker container %q not found, considering it as already deleted", containerID)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to stop docker container %q", containerID)
+	}
+	err = cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
+	if err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to remove docker container %q", containerID)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sanitizeName(t *testing.T) {
+	assert.Equal(t, "dep-1-Node_1-0", sanitizeName("dep-1", "Node_1", "0"))
+	assert.Equal(t, "my_dep-node.a-0", sanitizeName("my dep", "node.a", "0"))
+	assert.Equal(t, "d_e_p-node", sanitizeName("d/e:p", "node"))
+}
+
+func Test_generateContainerConfig(t *testing.T) {
+	labels := map[string]string{"yorc.node": "node"}
+	cc, hc, err := generateContainerConfig("nginx:latest", "nginx -g daemon", "8080:80 53:53/udp", labels)
+	require.NoError(t, err)
+	assert.Equal(t, "nginx:latest", cc.Image)
+	assert.Equal(t, strslice.StrSlice{"nginx", "-g", "daemon"}, cc.Cmd)
+	assert.Equal(t, labels, cc.Labels)
+	assert.Len(t, cc.ExposedPorts, 2)
+	assert.Contains(t, cc.ExposedPorts, nat.Port("80/tcp"))
+	assert.Contains(t, cc.ExposedPorts, nat.Port("53/udp"))
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8080"}}, hc.PortBindings[nat.Port("80/tcp")])
+	assert.Equal(t, []nat.PortBinding{{HostPort: "53"}}, hc.PortBindings[nat.Port("53/udp")])
+
+	cc, hc, err = generateContainerConfig("nginx", "", "", nil)
+	require.NoError(t, err)
+	assert.Nil(t, cc.Cmd)
+	assert.Len(t, cc.ExposedPorts, 0)
+	assert.Len(t, hc.PortBindings, 0)
+
+	_, _, err = generateContainerConfig("nginx", "", "notaport", nil)
+	assert.Error(t, err)
+}
+
+func Test_formatPublishedPorts(t *testing.T) {
+	ports := nat.PortMap{
+		nat.Port("80/tcp"):   []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}},
+		nat.Port("53/udp"):   []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "5353"}},
+		nat.Port("443/tcp"):  nil,
+		nat.Port("2100/tcp"): []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "21"}},
+	}
+	assert.Equal(t, "21:2100 5353:53/udp 8080:80", formatPublishedPorts(ports))
+	assert.Equal(t, "", formatPublishedPorts(nil))
+}
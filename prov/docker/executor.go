@@ -0,0 +1,100 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+const dockerVolumeType = "yorc.nodes.DockerVolume"
+
+type defaultExecutor struct {
+}
+
+// execution holds the context of a delegate operation on Docker containers or volumes
+type execution struct {
+	kv           *api.KV
+	cc           *api.Client
+	cfg          config.Configuration
+	deploymentID string
+	nodeName     string
+}
+
+func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return err
+	}
+	kv := cc.KV()
+	instances, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	nodeType, err := deployments.GetNodeType(kv, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	isVolume, err := deployments.IsTypeDerivedFrom(kv, deploymentID, nodeType, dockerVolumeType)
+	if err != nil {
+		return err
+	}
+	exec := &execution{kv: kv, cc: cc, cfg: cfg, deploymentID: deploymentID, nodeName: nodeName}
+
+	var instanceFn func(ctx context.Context, instance string) error
+	var startState, endState tosca.NodeState
+	switch strings.ToLower(delegateOperation) {
+	case "install":
+		startState, endState = tosca.NodeStateCreating, tosca.NodeStateStarted
+		instanceFn = exec.createContainer
+		if isVolume {
+			instanceFn = exec.createVolume
+		}
+	case "uninstall":
+		startState, endState = tosca.NodeStateDeleting, tosca.NodeStateDeleted
+		instanceFn = exec.deleteContainer
+		if isVolume {
+			instanceFn = exec.deleteVolume
+		}
+	default:
+		return errors.Errorf("operation %q not supported", delegateOperation)
+	}
+
+	var g errgroup.Group
+	for _, instance := range instances {
+		func(ctx context.Context, instance string) {
+			g.Go(func() error {
+				deployments.SetInstanceStateWithContextualLogs(ctx, kv, deploymentID, nodeName, instance, startState)
+				if err := instanceFn(ctx, instance); err != nil {
+					events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+					return err
+				}
+				deployments.SetInstanceStateWithContextualLogs(ctx, kv, deploymentID, nodeName, instance, endState)
+				return nil
+			})
+		}(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instance}), instance)
+	}
+	return g.Wait()
+}
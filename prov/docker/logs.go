@@ -0,0 +1,153 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/prov/scheduling"
+)
+
+type containerLog struct {
+	timestamp time.Time
+	line      string
+}
+
+type logsOperator struct {
+}
+
+// ExecAction publishes the logs of a container as deployment logs
+//
+// The action is deregistered as soon as the container is not running anymore
+func (o *logsOperator) ExecAction(ctx context.Context, cfg config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	containerID, ok := action.Data["containerID"]
+	if !ok {
+		return true, errors.New(`missing mandatory parameter "containerID" in logs action`)
+	}
+	nodeName := action.Data["nodeName"]
+	instance := action.Data["instance"]
+	ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName, events.InstanceID: instance})
+
+	cli, err := newDockerClient(cfg, action.Data["dockerHost"])
+	if err != nil {
+		return false, err
+	}
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to inspect docker container %q", containerID)
+	}
+
+	var since *time.Time
+	sinceStr := action.Data["latestPublishedLogTimestamp"]
+	options := types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Timestamps: true}
+	if sinceStr != "" {
+		t, err := time.Parse(time.RFC3339Nano, sinceStr)
+		if err == nil {
+			since = &t
+			options.Since = sinceStr
+		}
+	}
+	rc, err := cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read logs of docker container %q", containerID)
+	}
+	defer rc.Close()
+	var logs string
+	if info.Config != nil && info.Config.Tty {
+		b, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to read logs of docker container %q", containerID)
+		}
+		logs = string(b)
+	} else {
+		// Without TTY stdout and stderr are multiplexed
+		var stdout, stderr bytes.Buffer
+		_, err = stdcopy.StdCopy(&stdout, &stderr, rc)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to read logs of docker container %q", containerID)
+		}
+		logs = stdout.String() + stderr.String()
+	}
+
+	published := parseContainerLogs(logs, since)
+	for _, l := range published {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(l.line)
+	}
+	if len(published) > 0 {
+		// store latest publish log timestamp
+		lt := published[len(published)-1].timestamp
+		cc, err := cfg.GetConsulClient()
+		if err != nil {
+			return false, err
+		}
+		err = scheduling.UpdateActionData(cc, action.ID, "latestPublishedLogTimestamp", lt.Format(time.RFC3339Nano))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return info.State == nil || !info.State.Running, nil
+}
+
+// parseContainerLogs parses timestamped logs and returns those that are newer than since (if not nil)
+// sorted by timestamp
+func parseContainerLogs(logs string, since *time.Time) []containerLog {
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	r := make([]containerLog, 0, len(lines))
+	for _, line := range lines {
+		tokens := strings.SplitN(line, " ", 2)
+		ts, err := time.Parse(time.RFC3339Nano, tokens[0])
+		if err != nil || len(tokens) < 2 {
+			// not a timestamp + log so it is related to previous log
+			if len(r) != 0 {
+				r[len(r)-1].line += "\n" + line
+			}
+			continue
+		}
+		r = append(r, containerLog{timestamp: ts, line: tokens[1]})
+	}
+	// stdout and stderr are read separately so merge them
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].timestamp.Before(r[j].timestamp)
+	})
+	if since != nil {
+		// Logs API filters at the second level while timestamps are at the nanosec level
+		// so filter logs that were already published
+		b := r[:0]
+		for _, l := range r {
+			if l.timestamp.After(*since) {
+				b = append(b, l)
+			}
+		}
+		r = b
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func assertTime(t *testing.T, ti string) time.Time {
+	r, err := time.Parse(time.RFC3339Nano, ti)
+	require.NoError(t, err)
+	return r
+}
+
+func Test_parseContainerLogs(t *testing.T) {
+	since := assertTime(t, "2018-10-18T14:22:53Z")
+	type args struct {
+		logs  string
+		since *time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want []containerLog
+	}{
+		{"NormalBehavior", args{
+			logs: `2018-10-18T14:22:52.9826038Z 1
+2018-10-18T14:22:53Z 2
+2018-10-18T14:23:07.987815871Z Computation done!
+`},
+			[]containerLog{
+				{timestamp: assertTime(t, "2018-10-18T14:22:52.9826038Z"), line: "1"},
+				{timestamp: assertTime(t, "2018-10-18T14:22:53Z"), line: "2"},
+				{timestamp: assertTime(t, "2018-10-18T14:23:07.987815871Z"), line: "Computation done!"},
+			},
+		},
+		{"WithMultiLinesAndStrangeFirstLine", args{
+			logs: `fzfz sfs dsfsdf
+
+2018-10-18T14:22:52.9826038Z 1
+21
+  dfssdf
+2018-10-18T14:23:07.987815871Z Computation done!`},
+			[]containerLog{
+				{timestamp: assertTime(t, "2018-10-18T14:22:52.9826038Z"), line: "1\n21\n  dfssdf"},
+				{timestamp: assertTime(t, "2018-10-18T14:23:07.987815871Z"), line: "Computation done!"},
+			},
+		},
+		{"MergedStdoutAndStderr", args{
+			logs: `2018-10-18T14:22:52Z out1
+2018-10-18T14:22:54Z out2
+2018-10-18T14:22:53Z err1
+`},
+			[]containerLog{
+				{timestamp: assertTime(t, "2018-10-18T14:22:52Z"), line: "out1"},
+				{timestamp: assertTime(t, "2018-10-18T14:22:53Z"), line: "err1"},
+				{timestamp: assertTime(t, "2018-10-18T14:22:54Z"), line: "out2"},
+			},
+		},
+		{"FilterAlreadyPublished", args{
+			logs: `2018-10-18T14:22:52.9826038Z 1
+2018-10-18T14:22:53Z 2
+2018-10-18T14:22:53.5Z 3
+`, since: &since},
+			[]containerLog{
+				{timestamp: assertTime(t, "2018-10-18T14:22:53.5Z"), line: "3"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseContainerLogs(tt.args.logs, tt.args.since)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseContainerLogs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
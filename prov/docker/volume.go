@@ -0,0 +1,109 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/hashicorp/consul/api"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+)
+
+// getVolumeName returns the volume_name property of a volume node
+// or a name generated from the deployment ID and the node name if not set
+func getVolumeName(kv *api.KV, deploymentID, nodeName string) (string, error) {
+	value, err := deployments.GetNodePropertyValue(kv, deploymentID, nodeName, "volume_name")
+	if err != nil {
+		return "", err
+	}
+	if value != nil && value.RawString() != "" {
+		return value.RawString(), nil
+	}
+	return sanitizeName(deploymentID, nodeName), nil
+}
+
+func (e *execution) createVolume(ctx context.Context, instance string) error {
+	dockerHost, err := getDockerHost(e.kv, e.cfg, e.deploymentID, e.nodeName, instance)
+	if err != nil {
+		return err
+	}
+	cli, err := newDockerClient(e.cfg, dockerHost)
+	if err != nil {
+		return err
+	}
+	volumeName, err := getVolumeName(e.kv, e.deploymentID, e.nodeName)
+	if err != nil {
+		return err
+	}
+	driver, err := e.getNodePropertyString("driver")
+	if err != nil {
+		return err
+	}
+	driverOpts := make(map[string]string)
+	optsValue, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.nodeName, "driver_options")
+	if err != nil {
+		return err
+	}
+	if optsValue != nil && optsValue.Value != nil {
+		opts, ok := optsValue.Value.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("driver_options of node %q is expected to be a map", e.nodeName)
+		}
+		for k, v := range opts {
+			driverOpts[k] = fmt.Sprint(v)
+		}
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Creating docker volume %q", volumeName)
+	vol, err := cli.VolumeCreate(ctx, volumetypes.VolumesCreateBody{
+		Name:       volumeName,
+		Driver:     driver,
+		DriverOpts: driverOpts,
+		Labels: map[string]string{
+			"yorc.deployment": e.deploymentID,
+			"yorc.node":       e.nodeName,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create docker volume %q", volumeName)
+	}
+	return deployments.SetInstanceAttribute(e.deploymentID, e.nodeName, instance, "volume_name", vol.Name)
+}
+
+func (e *execution) deleteVolume(ctx context.Context, instance string) error {
+	dockerHost, err := getDockerHost(e.kv, e.cfg, e.deploymentID, e.nodeName, instance)
+	if err != nil {
+		return err
+	}
+	cli, err := newDockerClient(e.cfg, dockerHost)
+	if err != nil {
+		return err
+	}
+	volumeName, err := getVolumeName(e.kv, e.deploymentID, e.nodeName)
+	if err != nil {
+		return err
+	}
+	err = cli.VolumeRemove(ctx, volumeName, false)
+	if err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to remove docker volume %q", volumeName)
+	}
+	return nil
+}
@@ -47,6 +47,9 @@ func TestRunConsulHostsPoolPackageTests(t *testing.T) {
 	t.Run("TestConsulManagerList", func(t *testing.T) {
 		testConsulManagerList(t, client)
 	})
+	t.Run("TestConsulManagerListPools", func(t *testing.T) {
+		testConsulManagerListPools(t, client)
+	})
 	t.Run("TestConsulManagerGetHost", func(t *testing.T) {
 		testConsulManagerGetHost(t, client)
 	})
@@ -80,4 +83,16 @@ func TestRunConsulHostsPoolPackageTests(t *testing.T) {
 	t.Run("testConsulManagerAddLabelsWithAllocation", func(t *testing.T) {
 		testConsulManagerAddLabelsWithAllocation(t, client)
 	})
+	t.Run("testConsulManagerAllocateWithPlacement", func(t *testing.T) {
+		testConsulManagerAllocateWithPlacement(t, client)
+	})
+	t.Run("testConsulManagerAllocateWithLease", func(t *testing.T) {
+		testConsulManagerAllocateWithLease(t, client)
+	})
+	t.Run("testLeaseReaperUndeployOnExpiry", func(t *testing.T) {
+		testLeaseReaperUndeployOnExpiry(t, client)
+	})
+	t.Run("testHealthSweep", func(t *testing.T) {
+		testHealthSweep(t, client)
+	})
 }
@@ -67,3 +67,15 @@ func IsNoMatchingHostFoundError(err error) bool {
 	_, ok := errors.Cause(err).(noMatchingHostFoundError)
 	return ok
 }
+
+type allocationNotFoundError struct{}
+
+func (e allocationNotFoundError) Error() string {
+	return "allocation not found on host"
+}
+
+// IsAllocationNotFoundError checks if an error is an "allocation not found" error
+func IsAllocationNotFoundError(err error) bool {
+	_, ok := errors.Cause(err).(allocationNotFoundError)
+	return ok
+}
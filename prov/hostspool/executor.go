@@ -19,11 +19,13 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"github.com/spf13/cast"
 
 	"github.com/ystia/yorc/v3/config"
 	"github.com/ystia/yorc/v3/deployments"
@@ -33,6 +35,11 @@ import (
 	"github.com/ystia/yorc/v3/tosca"
 )
 
+const (
+	infrastructureName  = "hostspool"
+	placementPolicyType = "yorc.policies.hostspool.Placement"
+)
+
 type defaultExecutor struct {
 }
 
@@ -42,7 +49,7 @@ func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configura
 		return err
 	}
 
-	instances, err := tasks.GetInstances(cc.KV(), taskID, deploymentID, nodeName)
+	instances, err := tasks.GetInstancesWithContext(ctx, cc.KV(), taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
@@ -115,15 +122,30 @@ func (e *defaultExecutor) hostsPoolCreate(originalCtx context.Context, cc *api.C
 		}
 	}
 
-	instances, err := tasks.GetInstances(cc.KV(), taskID, deploymentID, nodeName)
+	poolName, err := getPoolName(cc.KV(), deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	placement, err := getPlacementPolicy(cc.KV(), cfg, deploymentID, nodeName, poolName)
+	if err != nil {
+		return err
+	}
+
+	leaseDuration, err := getLeaseDuration(cc.KV(), cfg, deploymentID, nodeName, poolName)
+	if err != nil {
+		return err
+	}
+
+	instances, err := tasks.GetInstancesWithContext(originalCtx, cc.KV(), taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
 	for _, instance := range instances {
 		ctx := events.AddLogOptionalFields(originalCtx, events.LogOptionalFields{events.InstanceID: instance})
 
-		allocation := &Allocation{NodeName: nodeName, Instance: instance, DeploymentID: deploymentID, Shareable: shareable, Resources: allocatedResources}
-		hostname, warnings, err := hpManager.Allocate(allocation, filters...)
+		allocation := &Allocation{NodeName: nodeName, Instance: instance, DeploymentID: deploymentID, Shareable: shareable, Resources: allocatedResources, PlacementPolicy: placement, LeaseDuration: leaseDuration}
+		hostname, warnings, err := hpManager.Allocate(poolName, allocation, filters...)
 		for _, warn := range warnings {
 			events.WithContextOptionalFields(ctx).
 				NewLogEntry(events.LogLevelWARN, deploymentID).Registerf(`%v`, warn)
@@ -135,7 +157,7 @@ func (e *defaultExecutor) hostsPoolCreate(originalCtx context.Context, cc *api.C
 		if err != nil {
 			return err
 		}
-		host, err := hpManager.GetHost(hostname)
+		host, err := hpManager.GetHost(poolName, hostname)
 		if err != nil {
 			return err
 		}
@@ -150,6 +172,13 @@ func (e *defaultExecutor) hostsPoolCreate(originalCtx context.Context, cc *api.C
 		if host.Connection.PrivateKey != "" {
 			credentials["keys"] = []string{host.Connection.PrivateKey}
 		}
+		if len(host.Connection.JumpHosts) > 0 {
+			jumpHosts := make([]deployments.JumpHost, len(host.Connection.JumpHosts))
+			for i, jh := range host.Connection.JumpHosts {
+				jumpHosts[i] = deployments.JumpHost{Host: jh.Host, Port: int(jh.Port), User: jh.User, Password: jh.Password, PrivateKey: jh.PrivateKey}
+			}
+			credentials["jump_hosts"] = deployments.JumpHostsCredentials(jumpHosts)
+		}
 		err = deployments.SetInstanceCapabilityAttributeComplex(deploymentID, nodeName, instance, "endpoint", "credentials", credentials)
 		if err != nil {
 			return err
@@ -208,12 +237,83 @@ func (e *defaultExecutor) hostsPoolCreate(originalCtx context.Context, cc *api.C
 			}
 		}
 
-		return hpManager.UpdateResourcesLabels(hostname, allocatedResources, subtract, updateResourcesLabels)
+		return hpManager.UpdateResourcesLabels(poolName, hostname, allocatedResources, subtract, updateResourcesLabels)
 	}
 
 	return nil
 }
 
+// getPoolName returns the name of the hosts pool in which hosts of a node are allocated
+func getPoolName(kv *api.KV, deploymentID, nodeName string) (string, error) {
+	pool, err := deployments.GetNodePropertyValue(kv, deploymentID, nodeName, "pool")
+	if err != nil {
+		return "", err
+	}
+	if pool == nil || pool.RawString() == "" {
+		return DefaultPool, nil
+	}
+	return pool.RawString(), nil
+}
+
+// getLeaseDuration returns the lease duration of the allocations of a node, 0 means that allocations never expire
+//
+// The lease_duration property of the node takes precedence over the default lease duration of its pool
+// which takes precedence over the default lease duration of all pools.
+func getLeaseDuration(kv *api.KV, cfg config.Configuration, deploymentID, nodeName, poolName string) (time.Duration, error) {
+	var duration string
+	leaseProp, err := deployments.GetNodePropertyValue(kv, deploymentID, nodeName, "lease_duration")
+	if err != nil {
+		return 0, err
+	}
+	hpCfg := cfg.Infrastructures[infrastructureName]
+	if leaseProp != nil && leaseProp.RawString() != "" {
+		duration = leaseProp.RawString()
+	} else if poolDuration, ok := cast.ToStringMapString(hpCfg.Get("pools_default_lease_duration"))[poolName]; ok {
+		duration = poolDuration
+	} else {
+		duration = hpCfg.GetString("default_lease_duration")
+	}
+	if duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid lease duration %q for node %q", duration, nodeName)
+	}
+	if d < 0 {
+		return 0, errors.Errorf("invalid lease duration %q for node %q: should be positive", duration, nodeName)
+	}
+	return d, nil
+}
+
+// getPlacementPolicy returns the name of the placement strategy to use for a node
+//
+// A placement policy targeting the node takes precedence over the hosts pool configuration,
+// where the strategy defined for the pool takes precedence over the default one.
+func getPlacementPolicy(kv *api.KV, cfg config.Configuration, deploymentID, nodeName, poolName string) (string, error) {
+	policies, err := deployments.GetPoliciesForTypeAndNode(kv, deploymentID, placementPolicyType, nodeName)
+	if err != nil {
+		return "", err
+	}
+	if len(policies) > 1 {
+		return "", errors.Errorf("found more than one placement policy to apply to node %q", nodeName)
+	}
+	if len(policies) == 1 {
+		algorithm, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policies[0], "algorithm")
+		if err != nil {
+			return "", err
+		}
+		if algorithm != nil && algorithm.RawString() != "" {
+			return algorithm.RawString(), nil
+		}
+	}
+	hpCfg := cfg.Infrastructures[infrastructureName]
+	if poolStrategy, ok := cast.ToStringMapString(hpCfg.Get("pools_placement_strategy"))[poolName]; ok {
+		return poolStrategy, nil
+	}
+	return hpCfg.GetString("placement_strategy"), nil
+}
+
 func (e *defaultExecutor) getAllocatedResourcesFromHostCapabilities(kv *api.KV, deploymentID, nodeName string) (map[string]string, error) {
 	res := make(map[string]string, 0)
 	p, err := deployments.GetCapabilityPropertyValue(kv, deploymentID, nodeName, "host", "num_cpus")
@@ -297,7 +397,11 @@ func createFiltersFromComputeCapabilities(kv *api.KV, deploymentID, nodeName str
 
 func (e *defaultExecutor) hostsPoolDelete(originalCtx context.Context, cc *api.Client, cfg config.Configuration, taskID, deploymentID, nodeName string, allocatedResources map[string]string) error {
 	hpManager := NewManager(cc)
-	instances, err := tasks.GetInstances(cc.KV(), taskID, deploymentID, nodeName)
+	poolName, err := getPoolName(cc.KV(), deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	instances, err := tasks.GetInstancesWithContext(originalCtx, cc.KV(), taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
@@ -313,11 +417,11 @@ func (e *defaultExecutor) hostsPoolDelete(originalCtx context.Context, cc *api.C
 			continue
 		}
 		allocation := &Allocation{NodeName: nodeName, Instance: instance, DeploymentID: deploymentID}
-		err = hpManager.Release(hostname.RawString(), allocation)
+		err = hpManager.Release(poolName, hostname.RawString(), allocation)
 		if err != nil {
 			errs = multierror.Append(errs, err)
 		}
-		return hpManager.UpdateResourcesLabels(hostname.RawString(), allocatedResources, add, updateResourcesLabels)
+		return hpManager.UpdateResourcesLabels(poolName, hostname.RawString(), allocatedResources, add, updateResourcesLabels)
 
 	}
 	return errors.Wrap(errs, "errors encountered during hosts pool node release. Some hosts maybe not properly released.")
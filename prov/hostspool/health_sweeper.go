@@ -0,0 +1,290 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/helper/metricsutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const (
+	// defaultHealthSweepInterval is the default delay between two health sweeps of hosts pools
+	defaultHealthSweepInterval = 5 * time.Minute
+	// defaultHealthSweepParallelism is the default number of hosts checked concurrently during a health sweep
+	defaultHealthSweepParallelism = 10
+)
+
+var defaultSweeper *healthSweeper
+
+// A healthSweeper periodically checks the connection to every host of hosts pools
+//
+// Unreachable free hosts are quarantined in error status and restored once they are reachable again.
+// Only the leader Yorc server runs health sweeps.
+type healthSweeper struct {
+	cm             *consulManager
+	interval       time.Duration
+	parallelism    int
+	metricsLabels  []string
+	serviceKey     string
+	chShutdown     chan struct{}
+	chStopSweeping chan struct{}
+	isActive       bool
+	isActiveLock   sync.Mutex
+}
+
+// StartHealthSweeper allows to instantiate a default health sweeper checking hosts connections when this server is the leader
+//
+// The delay between two sweeps is defined by the health_sweep_interval option of the hosts pool configuration
+// and the number of hosts checked concurrently by the health_sweep_parallelism option.
+// Hosts counts by label are published only for labels listed in the metrics_labels option.
+func StartHealthSweeper(cfg config.Configuration, cc *api.Client) {
+	hpCfg := cfg.Infrastructures[infrastructureName]
+	interval := hpCfg.GetDuration("health_sweep_interval")
+	if interval <= 0 {
+		interval = defaultHealthSweepInterval
+	}
+	parallelism := hpCfg.GetInt("health_sweep_parallelism")
+	if parallelism <= 0 {
+		parallelism = defaultHealthSweepParallelism
+	}
+	defaultSweeper = &healthSweeper{
+		cm:            NewManager(cc).(*consulManager),
+		interval:      interval,
+		parallelism:   parallelism,
+		metricsLabels: hpCfg.GetStringSlice("metrics_labels"),
+		serviceKey:    path.Join(consulutil.YorcServicePrefix, "/hostspool_health/leader"),
+		chShutdown:    make(chan struct{}),
+	}
+	// Watch leader election for health sweeper
+	go consulutil.WatchLeaderElection(cc, defaultSweeper.serviceKey, defaultSweeper.chShutdown, defaultSweeper.startSweeping, defaultSweeper.stopSweeping)
+}
+
+// StopHealthSweeper allows to stop checking hosts connections
+func StopHealthSweeper() {
+	defaultSweeper.stopSweeping()
+
+	// Stop watch leader election
+	close(defaultSweeper.chShutdown)
+}
+
+func handleSweepError(err error) {
+	err = errors.Wrap(err, "[WARN] Error during hosts pool health sweep")
+	log.Print(err)
+	log.Debugf("%+v", err)
+}
+
+func (s *healthSweeper) startSweeping() {
+	s.isActiveLock.Lock()
+	defer s.isActiveLock.Unlock()
+	if s.isActive {
+		log.Println("Hosts pool health sweeper is already running.")
+		return
+	}
+	log.Debugf("Hosts pool health sweeper is now running.")
+	s.isActive = true
+	s.chStopSweeping = make(chan struct{})
+	go s.run(s.chStopSweeping)
+}
+
+func (s *healthSweeper) stopSweeping() {
+	s.isActiveLock.Lock()
+	defer s.isActiveLock.Unlock()
+	if s.isActive {
+		log.Debugf("Hosts pool health sweeper is about to be stopped")
+		close(s.chStopSweeping)
+		s.isActive = false
+	}
+}
+
+func (s *healthSweeper) run(chStop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-chStop:
+			log.Debugf("Ending hosts pool health sweeper has been requested: stop it now.")
+			return
+		case <-s.chShutdown:
+			log.Debugf("Shutdown has been sent: stop hosts pool health sweeper now.")
+			return
+		case <-ticker.C:
+			if err := s.sweep(); err != nil {
+				handleSweepError(err)
+			}
+		}
+	}
+}
+
+// sweep checks the connection to all hosts of all pools and publishes hosts pools metrics
+func (s *healthSweeper) sweep() error {
+	pools, err := s.cm.ListPools()
+	if err != nil {
+		return err
+	}
+	for _, poolName := range pools {
+		hostnames, _, _, err := s.cm.List(poolName)
+		if err != nil {
+			return err
+		}
+
+		var waitGroup sync.WaitGroup
+		semaphore := make(chan struct{}, s.parallelism)
+		for _, hostname := range hostnames {
+			waitGroup.Add(1)
+			semaphore <- struct{}{}
+			go func(hostname string) {
+				defer func() {
+					<-semaphore
+					waitGroup.Done()
+				}()
+				if err := s.checkHost(poolName, hostname); err != nil {
+					handleSweepError(err)
+				}
+			}(hostname)
+		}
+		waitGroup.Wait()
+
+		hosts := make([]Host, 0, len(hostnames))
+		for _, hostname := range hostnames {
+			host, err := s.cm.GetHost(poolName, hostname)
+			if err != nil {
+				if IsHostNotFoundError(err) {
+					// Host removed during the sweep
+					continue
+				}
+				return err
+			}
+			hosts = append(hosts, host)
+		}
+		publishPoolMetrics(poolName, hosts, s.metricsLabels)
+	}
+	return nil
+}
+
+// checkHost quarantines a free host that is unreachable and restores the status of a host in error
+// that has been quarantined once it is reachable again
+//
+// The connection is checked without holding the pool lock, the host status is then read again
+// under lock as the host may have been allocated, released or removed in the meantime.
+func (s *healthSweeper) checkHost(poolName, hostname string) error {
+	status, err := s.cm.GetHostStatus(poolName, hostname)
+	if err != nil {
+		if IsHostNotFoundError(err) {
+			// No such host anymore
+			return nil
+		}
+		return err
+	}
+
+	connErr := s.cm.checkConnection(poolName, hostname)
+	if (connErr != nil && status != HostStatusFree) || (connErr == nil && status != HostStatusError) {
+		return nil
+	}
+
+	_, cleanupFn, err := s.cm.lockKey(poolName, hostname, "health sweep", maxWaitTimeSeconds*time.Second)
+	if err != nil {
+		return err
+	}
+	defer cleanupFn()
+
+	status, err = s.cm.GetHostStatus(poolName, hostname)
+	if err != nil {
+		if IsHostNotFoundError(err) {
+			// Host removed during the connection check
+			return nil
+		}
+		return err
+	}
+
+	if connErr != nil {
+		if status != HostStatusFree {
+			return nil
+		}
+		log.Printf("[WARN] Quarantining unreachable host %q of hosts pool %q: %v", hostname, poolName, connErr)
+		if err = s.cm.backupHostStatus(poolName, hostname); err != nil {
+			return err
+		}
+		return s.cm.setHostStatusWithMessage(poolName, hostname, HostStatusError, "quarantined by health sweep: failed to connect to host")
+	}
+
+	if status != HostStatusError {
+		return nil
+	}
+	if _, err = s.cm.getStatus(poolName, hostname, true); err != nil {
+		if IsHostNotFoundError(err) {
+			// Host in error for another reason than a connection failure
+			return nil
+		}
+		return err
+	}
+	log.Printf("Host %q of hosts pool %q is reachable again, restoring its status", hostname, poolName)
+	return s.cm.restoreHostStatus(poolName, hostname)
+}
+
+// hostsCount holds the number of hosts by status
+type hostsCount map[HostStatus]int
+
+// countHosts returns the number of hosts by status for the whole pool and for each value of the given labels
+func countHosts(hosts []Host, labels []string) (hostsCount, map[string]map[string]hostsCount) {
+	poolCount := make(hostsCount)
+	labelsCount := make(map[string]map[string]hostsCount)
+	for _, host := range hosts {
+		poolCount[host.Status]++
+		for _, name := range labels {
+			value, ok := host.Labels[name]
+			if !ok {
+				continue
+			}
+			if labelsCount[name] == nil {
+				labelsCount[name] = make(map[string]hostsCount)
+			}
+			if labelsCount[name][value] == nil {
+				labelsCount[name][value] = make(hostsCount)
+			}
+			labelsCount[name][value][host.Status]++
+		}
+	}
+	return poolCount, labelsCount
+}
+
+// publishPoolMetrics publishes gauges of the number of free, allocated and error hosts of a pool,
+// in total and by value of the given labels
+//
+// Labels are restricted to an explicit list as each label value creates new metric series.
+func publishPoolMetrics(poolName string, hosts []Host, labels []string) {
+	poolCount, labelsCount := countHosts(hosts, labels)
+	statuses := []HostStatus{HostStatusFree, HostStatusAllocated, HostStatusError}
+	for _, status := range statuses {
+		metrics.SetGauge(metricsutil.CleanupMetricKey([]string{"hostspool", poolName, "hosts", status.String()}), float32(poolCount[status]))
+	}
+	for name, values := range labelsCount {
+		for value, count := range values {
+			for _, status := range statuses {
+				metrics.SetGaugeWithLabels(metricsutil.CleanupMetricKey([]string{"hostspool", poolName, "labels", name, status.String()}),
+					float32(count[status]), []metrics.Label{{Name: "value", Value: value}})
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+func TestCountHosts(t *testing.T) {
+	hosts := []Host{
+		{Name: "host0", Status: HostStatusFree, Labels: map[string]string{"os": "linux", "gpu": "true"}},
+		{Name: "host1", Status: HostStatusAllocated, Labels: map[string]string{"os": "linux"}},
+		{Name: "host2", Status: HostStatusError, Labels: map[string]string{"os": "windows"}},
+		{Name: "host3", Status: HostStatusFree},
+	}
+	poolCount, labelsCount := countHosts(hosts, []string{"os", "gpu"})
+	assert.Equal(t, hostsCount{HostStatusFree: 2, HostStatusAllocated: 1, HostStatusError: 1}, poolCount)
+	assert.Equal(t, map[string]map[string]hostsCount{
+		"os": {
+			"linux":   {HostStatusFree: 1, HostStatusAllocated: 1},
+			"windows": {HostStatusError: 1},
+		},
+		"gpu": {
+			"true": {HostStatusFree: 1},
+		},
+	}, labelsCount)
+
+	// Only listed labels are counted
+	poolCount, labelsCount = countHosts(hosts, []string{"gpu"})
+	assert.Equal(t, hostsCount{HostStatusFree: 2, HostStatusAllocated: 1, HostStatusError: 1}, poolCount)
+	assert.Equal(t, map[string]map[string]hostsCount{
+		"gpu": {
+			"true": {HostStatusFree: 1},
+		},
+	}, labelsCount)
+	_, labelsCount = countHosts(hosts, nil)
+	assert.Len(t, labelsCount, 0)
+}
+
+func testHealthSweep(t *testing.T, cc *api.Client) {
+	cleanupHostsPool(t, cc)
+	cm := &consulManager{cc, mockSSHClientFactory}
+	var checkpoint uint64
+	require.NoError(t, cm.Apply(testPool, createHosts(3), &checkpoint))
+	allocatedHost, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "0", DeploymentID: "sweep"})
+	require.NoError(t, err)
+
+	setUser := func(hostname, user string) {
+		_, err := cc.KV().Put(&api.KVPair{Key: path.Join(consulutil.HostsPoolPrefix, testPool, hostname, "connection", "user"), Value: []byte(user)}, nil)
+		require.NoError(t, err)
+	}
+	hostnames, _, _, err := cm.List(testPool)
+	require.NoError(t, err)
+	for _, hostname := range hostnames {
+		setUser(hostname, "fail")
+	}
+
+	// Unreachable free hosts are quarantined, allocated ones are left untouched
+	sweeper := &healthSweeper{cm: cm, parallelism: 2}
+	require.NoError(t, sweeper.sweep())
+	for _, hostname := range hostnames {
+		host, err := cm.GetHost(testPool, hostname)
+		require.NoError(t, err)
+		if hostname == allocatedHost {
+			assert.Equal(t, HostStatusAllocated, host.Status)
+			continue
+		}
+		assert.Equal(t, HostStatusError, host.Status)
+		assert.Contains(t, host.Message, "quarantined")
+	}
+
+	// Quarantined hosts are restored once they are reachable again
+	for _, hostname := range hostnames {
+		setUser(hostname, "testuser")
+	}
+	require.NoError(t, sweeper.sweep())
+	for _, hostname := range hostnames {
+		host, err := cm.GetHost(testPool, hostname)
+		require.NoError(t, err)
+		if hostname == allocatedHost {
+			assert.Equal(t, HostStatusAllocated, host.Status)
+			continue
+		}
+		assert.Equal(t, HostStatusFree, host.Status)
+		assert.Equal(t, "", host.Message)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -39,24 +40,30 @@ const (
 	CheckpointError = "Checkpoint for Hosts Pool error"
 	// maxWaitTimeSeconds is the max time to wait for a lock on write operations
 	maxWaitTimeSeconds = 120
+	// DefaultPool is the name of the pool used when no pool is specified
+	DefaultPool = "default"
 	// maxNbTransactionOps is the maximum number of operations within a transaction
 	// supported by Consul (limit hard-coded in Consul implementation)
 	maxNbTransactionOps = 64
 )
 
-// A Manager is in charge of creating/updating/deleting hosts from the pool
+// A Manager is in charge of creating/updating/deleting hosts from the pools
+//
+// Hosts are grouped into named pools, each operation applies to a single pool.
 type Manager interface {
-	Add(hostname string, connection Connection, labels map[string]string) error
-	Apply(pool []Host, checkpoint *uint64) error
-	Remove(hostname string) error
-	UpdateResourcesLabels(hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) error
-	AddLabels(hostname string, labels map[string]string) error
-	RemoveLabels(hostname string, labels []string) error
-	UpdateConnection(hostname string, connection Connection) error
-	List(filters ...labelsutil.Filter) ([]string, []labelsutil.Warning, uint64, error)
-	GetHost(hostname string) (Host, error)
-	Allocate(allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error)
-	Release(hostname string, allocation *Allocation) error
+	Add(poolName, hostname string, connection Connection, labels map[string]string) error
+	Apply(poolName string, pool []Host, checkpoint *uint64) error
+	Remove(poolName, hostname string) error
+	UpdateResourcesLabels(poolName, hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) error
+	AddLabels(poolName, hostname string, labels map[string]string) error
+	RemoveLabels(poolName, hostname string, labels []string) error
+	UpdateConnection(poolName, hostname string, connection Connection) error
+	List(poolName string, filters ...labelsutil.Filter) ([]string, []labelsutil.Warning, uint64, error)
+	ListPools() ([]string, error)
+	GetHost(poolName, hostname string) (Host, error)
+	Allocate(poolName string, allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error)
+	Release(poolName, hostname string, allocation *Allocation) error
+	RenewLease(poolName, hostname, allocationID string, duration time.Duration) (Allocation, error)
 }
 
 // SSHClientFactory is a that could be called to customize the client used to check the connection.
@@ -67,10 +74,13 @@ type SSHClientFactory func(config *ssh.ClientConfig, conn Connection) sshutil.Cl
 // NewManager creates a Manager backed to Consul
 func NewManager(cc *api.Client) Manager {
 	return NewManagerWithSSHFactory(cc, func(config *ssh.ClientConfig, conn Connection) sshutil.Client {
+		// Jump hosts configurations were already validated when checking the connection
+		jumpHosts, _ := getSSHJumpHosts(conn)
 		return &sshutil.SSHClient{
-			Config: config,
-			Host:   conn.Host,
-			Port:   int(conn.Port),
+			Config:    config,
+			Host:      conn.Host,
+			Port:      int(conn.Port),
+			JumpHosts: jumpHosts,
 		}
 	})
 }
@@ -82,25 +92,30 @@ func NewManagerWithSSHFactory(cc *api.Client, sshClientFactory SSHClientFactory)
 	return &consulManager{cc: cc, getSSHClient: sshClientFactory}
 }
 
-// Lock key is not under HostsPoolPrefix so that taking the lock and releasing
+// Lock keys are not under HostsPoolPrefix so that taking the lock and releasing
 // without any change to the Hosts Pool will not update the last index of the
 // Hosts Pool list
-const kvLockKey = consulutil.YorcManagementPrefix + "/hosts_pool/lock"
+func kvLockKey(poolName string) string {
+	return path.Join(consulutil.YorcManagementPrefix, "hosts_pool", poolName, "lock")
+}
 
 type consulManager struct {
 	cc           *api.Client
 	getSSHClient SSHClientFactory
 }
 
-func (cm *consulManager) Add(hostname string, conn Connection, labels map[string]string) error {
-	return cm.addWait(hostname, conn, labels, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) Add(poolName, hostname string, conn Connection, labels map[string]string) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.addWait(poolName, hostname, conn, labels, maxWaitTimeSeconds*time.Second)
 }
-func (cm *consulManager) addWait(hostname string, conn Connection, labels map[string]string, maxWaitTime time.Duration) error {
-	ops, err := cm.getAddOperations(hostname, conn, labels, HostStatusFree, "", nil)
+func (cm *consulManager) addWait(poolName, hostname string, conn Connection, labels map[string]string, maxWaitTime time.Duration) error {
+	ops, err := cm.getAddOperations(poolName, hostname, conn, labels, HostStatusFree, "", nil)
 	if err != nil {
 		return err
 	}
-	_, cleanupFn, err := cm.lockKey(hostname, "creation", maxWaitTime)
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "creation", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -122,14 +137,15 @@ func (cm *consulManager) addWait(hostname string, conn Connection, labels map[st
 		return errors.Errorf("Failed to register host %q: %s", hostname, strings.Join(errs, ", "))
 	}
 
-	err = cm.checkConnection(hostname)
+	err = cm.checkConnection(poolName, hostname)
 	if err != nil {
-		cm.setHostStatusWithMessage(hostname, HostStatusError, "can't connect to host")
+		cm.setHostStatusWithMessage(poolName, hostname, HostStatusError, "can't connect to host")
 	}
 	return err
 }
 
 func (cm *consulManager) getAddOperations(
+	poolName string,
 	hostname string,
 	conn Connection,
 	labels map[string]string,
@@ -158,7 +174,7 @@ func (cm *consulManager) getAddOperations(
 		host = hostname
 	}
 
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	addOps := api.KVTxnOps{
 		&api.KVTxnOp{
 			Verb: api.KVCheckNotExists,
@@ -196,6 +212,12 @@ func (cm *consulManager) getAddOperations(
 		},
 	}
 
+	jumpHostsOps, err := getJumpHostsOperations(hostKVPrefix, conn.JumpHosts)
+	if err != nil {
+		return nil, err
+	}
+	addOps = append(addOps, jumpHostsOps...)
+
 	if message != "" {
 
 		addOps = append(addOps, &api.KVTxnOp{
@@ -206,14 +228,13 @@ func (cm *consulManager) getAddOperations(
 	}
 
 	var allocsOps api.KVTxnOps
-	var err error
-	if allocsOps, err = getAddAllocationsOperation(hostname, allocations); err != nil {
+	if allocsOps, err = getAddAllocationsOperation(poolName, hostname, allocations); err != nil {
 		return nil, err
 	} else if len(allocsOps) > 0 {
 		addOps = append(addOps, allocsOps...)
 	}
 
-	labelOps, err := cm.getAddUpdatedLabelsOperations(hostname, labels)
+	labelOps, err := cm.getAddUpdatedLabelsOperations(poolName, hostname, labels)
 	if err != nil {
 		return nil, err
 	}
@@ -221,17 +242,20 @@ func (cm *consulManager) getAddOperations(
 	return addOps, nil
 }
 
-func (cm *consulManager) Remove(hostname string) error {
-	return cm.removeWait(hostname, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) Remove(poolName, hostname string) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.removeWait(poolName, hostname, maxWaitTimeSeconds*time.Second)
 }
-func (cm *consulManager) removeWait(hostname string, maxWaitTime time.Duration) error {
+func (cm *consulManager) removeWait(poolName, hostname string, maxWaitTime time.Duration) error {
 
-	ops, err := cm.getRemoveOperations(hostname, true)
+	ops, err := cm.getRemoveOperations(poolName, hostname, true)
 	if err != nil {
 		return err
 	}
 
-	lockCh, cleanupFn, err := cm.lockKey(hostname, "deletion", maxWaitTime)
+	lockCh, cleanupFn, err := cm.lockKey(poolName, hostname, "deletion", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -259,15 +283,15 @@ func (cm *consulManager) removeWait(hostname string, maxWaitTime time.Duration)
 	return nil
 }
 
-func (cm *consulManager) getRemoveOperations(hostname string, checkStatus bool) (api.KVTxnOps, error) {
+func (cm *consulManager) getRemoveOperations(poolName, hostname string, checkStatus bool) (api.KVTxnOps, error) {
 	if hostname == "" {
 		return nil, errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 
 	if checkStatus {
-		status, err := cm.GetHostStatus(hostname)
+		status, err := cm.GetHostStatus(poolName, hostname)
 		if err != nil {
 			return nil, err
 		}
@@ -289,7 +313,7 @@ func (cm *consulManager) getRemoveOperations(hostname string, checkStatus bool)
 	return rmOps, nil
 }
 
-func (cm *consulManager) lockKey(hostname, opType string, lockWaitTime time.Duration) (lockCh <-chan struct{}, cleanupFn func(), err error) {
+func (cm *consulManager) lockKey(poolName string, hostname, opType string, lockWaitTime time.Duration) (lockCh <-chan struct{}, cleanupFn func(), err error) {
 	var sessionName string
 	if hostname != "" {
 		sessionName = fmt.Sprintf("%q %s", hostname, opType)
@@ -297,7 +321,7 @@ func (cm *consulManager) lockKey(hostname, opType string, lockWaitTime time.Dura
 		sessionName = opType
 	}
 	lock, err := cm.cc.LockOpts(&api.LockOptions{
-		Key:            kvLockKey,
+		Key:            kvLockKey(poolName),
 		Value:          []byte(fmt.Sprintf("locked for %s", sessionName)),
 		MonitorRetries: 2,
 		LockWaitTime:   lockWaitTime,
@@ -356,8 +380,11 @@ func (cm *consulManager) lockKey(hostname, opType string, lockWaitTime time.Dura
 	return
 }
 
-func (cm *consulManager) List(filters ...labelsutil.Filter) ([]string, []labelsutil.Warning, uint64, error) {
-	hosts, metadata, err := cm.cc.KV().Keys(consulutil.HostsPoolPrefix+"/", "/", nil)
+func (cm *consulManager) List(poolName string, filters ...labelsutil.Filter) ([]string, []labelsutil.Warning, uint64, error) {
+	if err := checkPoolName(poolName); err != nil {
+		return nil, nil, 0, err
+	}
+	hosts, metadata, err := cm.cc.KV().Keys(path.Join(consulutil.HostsPoolPrefix, poolName)+"/", "/", nil)
 	if err != nil {
 		return nil, nil, 0, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -365,7 +392,7 @@ func (cm *consulManager) List(filters ...labelsutil.Filter) ([]string, []labelsu
 	results := hosts[:0]
 	for _, host := range hosts {
 		host = path.Base(host)
-		labels, err := cm.GetHostLabels(host)
+		labels, err := cm.GetHostLabels(poolName, host)
 		if err != nil {
 			return nil, nil, 0, err
 		}
@@ -379,23 +406,49 @@ func (cm *consulManager) List(filters ...labelsutil.Filter) ([]string, []labelsu
 	return results, warnings, metadata.LastIndex, nil
 }
 
-func (cm *consulManager) backupHostStatus(hostname string) error {
-	status, err := cm.GetHostStatus(hostname)
+func (cm *consulManager) ListPools() ([]string, error) {
+	keys, _, err := cm.cc.KV().Keys(consulutil.HostsPoolPrefix+"/", "/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	pools := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			pools = append(pools, path.Base(key))
+		}
+	}
+	sort.Strings(pools)
+	return pools, nil
+}
+
+// checkPoolName checks that a pool name could be used as a Consul key segment
+func checkPoolName(poolName string) error {
+	if poolName == "" {
+		return errors.WithStack(badRequestError{`"pool" missing`})
+	}
+	if strings.Contains(poolName, "/") {
+		return errors.WithStack(badRequestError{fmt.Sprintf("invalid pool name %q: it should not contain '/'", poolName)})
+	}
+	return nil
+}
+
+func (cm *consulManager) backupHostStatus(poolName, hostname string) error {
+	status, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
-	message, err := cm.GetHostMessage(hostname)
+	message, err := cm.GetHostMessage(poolName, hostname)
 	if err != nil {
 		return err
 	}
-	hostPath := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostPath := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	_, errGrp, store := consulutil.WithContext(context.Background())
 	store.StoreConsulKeyAsString(path.Join(hostPath, ".statusBackup"), status.String())
 	store.StoreConsulKeyAsString(path.Join(hostPath, ".messageBackup"), message)
 	return errGrp.Wait()
 }
-func (cm *consulManager) restoreHostStatus(hostname string) error {
-	hostPath := path.Join(consulutil.HostsPoolPrefix, hostname)
+func (cm *consulManager) restoreHostStatus(poolName, hostname string) error {
+	hostPath := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	kvp, _, err := cm.cc.KV().Get(path.Join(hostPath, ".statusBackup"), nil)
 	if err != nil {
 		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
@@ -407,7 +460,7 @@ func (cm *consulManager) restoreHostStatus(hostname string) error {
 	if err != nil {
 		return errors.Wrapf(err, "invalid backup status for host %q", hostname)
 	}
-	err = cm.setHostStatus(hostname, status)
+	err = cm.setHostStatus(poolName, hostname, status)
 	if err != nil {
 		return err
 	}
@@ -423,7 +476,7 @@ func (cm *consulManager) restoreHostStatus(hostname string) error {
 	if kvp != nil {
 		msg = string(kvp.Value)
 	}
-	err = cm.setHostMessage(hostname, msg)
+	err = cm.setHostMessage(poolName, hostname, msg)
 	if err != nil {
 		return err
 	}
@@ -431,27 +484,27 @@ func (cm *consulManager) restoreHostStatus(hostname string) error {
 	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 }
 
-func (cm *consulManager) setHostStatus(hostname string, status HostStatus) error {
-	return cm.setHostStatusWithMessage(hostname, status, "")
+func (cm *consulManager) setHostStatus(poolName, hostname string, status HostStatus) error {
+	return cm.setHostStatusWithMessage(poolName, hostname, status, "")
 }
 
-func (cm *consulManager) setHostStatusWithMessage(hostname string, status HostStatus, message string) error {
-	_, err := cm.GetHostStatus(hostname)
+func (cm *consulManager) setHostStatusWithMessage(poolName, hostname string, status HostStatus, message string) error {
+	_, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
-	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.HostsPoolPrefix, hostname, "status"), status.String())
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "status"), status.String())
 	if err != nil {
 		return err
 	}
-	return cm.setHostMessage(hostname, message)
+	return cm.setHostMessage(poolName, hostname, message)
 }
 
-func (cm *consulManager) GetHostStatus(hostname string) (HostStatus, error) {
-	return cm.getStatus(hostname, false)
+func (cm *consulManager) GetHostStatus(poolName, hostname string) (HostStatus, error) {
+	return cm.getStatus(poolName, hostname, false)
 }
 
-func (cm *consulManager) getStatus(hostname string, backup bool) (HostStatus, error) {
+func (cm *consulManager) getStatus(poolName, hostname string, backup bool) (HostStatus, error) {
 	if hostname == "" {
 		return HostStatus(0), errors.WithStack(badRequestError{`"hostname" missing`})
 	}
@@ -460,7 +513,7 @@ func (cm *consulManager) getStatus(hostname string, backup bool) (HostStatus, er
 		keyname = ".statusBackup"
 	}
 
-	kvp, _, err := cm.cc.KV().Get(path.Join(consulutil.HostsPoolPrefix, hostname, keyname), nil)
+	kvp, _, err := cm.cc.KV().Get(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, keyname), nil)
 	if err != nil {
 		return HostStatus(0), errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -475,17 +528,17 @@ func (cm *consulManager) getStatus(hostname string, backup bool) (HostStatus, er
 	return status, nil
 }
 
-func (cm *consulManager) GetHostMessage(hostname string) (string, error) {
-	return cm.getMessage(hostname, false)
+func (cm *consulManager) GetHostMessage(poolName, hostname string) (string, error) {
+	return cm.getMessage(poolName, hostname, false)
 }
 
-func (cm *consulManager) getMessage(hostname string, backup bool) (string, error) {
+func (cm *consulManager) getMessage(poolName, hostname string, backup bool) (string, error) {
 	if hostname == "" {
 		return "", errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 
 	// check if host exists
-	_, err := cm.GetHostStatus(hostname)
+	_, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return "", err
 	}
@@ -495,7 +548,7 @@ func (cm *consulManager) getMessage(hostname string, backup bool) (string, error
 		keyname = ".messageBackup"
 	}
 
-	kvp, _, err := cm.cc.KV().Get(path.Join(consulutil.HostsPoolPrefix, hostname, keyname), nil)
+	kvp, _, err := cm.cc.KV().Get(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, keyname), nil)
 	if err != nil {
 		return "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -505,43 +558,46 @@ func (cm *consulManager) getMessage(hostname string, backup bool) (string, error
 	return string(kvp.Value), nil
 }
 
-func (cm *consulManager) setHostMessage(hostname, message string) error {
+func (cm *consulManager) setHostMessage(poolName string, hostname, message string) error {
 	if hostname == "" {
 		return errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 	// check if host exists
-	_, err := cm.GetHostStatus(hostname)
+	_, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
-	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.HostsPoolPrefix, hostname, "message"), message)
+	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "message"), message)
 }
 
-func (cm *consulManager) GetHost(hostname string) (Host, error) {
+func (cm *consulManager) GetHost(poolName, hostname string) (Host, error) {
+	if err := checkPoolName(poolName); err != nil {
+		return Host{}, err
+	}
 	host := Host{Name: hostname}
 	if hostname == "" {
 		return host, errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 	var err error
-	host.Status, err = cm.GetHostStatus(hostname)
+	host.Status, err = cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return host, err
 	}
-	host.Message, err = cm.GetHostMessage(hostname)
+	host.Message, err = cm.GetHostMessage(poolName, hostname)
 	if err != nil {
 		return host, err
 	}
 
-	host.Connection, err = cm.GetHostConnection(hostname)
+	host.Connection, err = cm.GetHostConnection(poolName, hostname)
 	if err != nil {
 		return host, err
 	}
-	host.Allocations, err = cm.GetAllocations(hostname)
+	host.Allocations, err = cm.GetAllocations(poolName, hostname)
 	if err != nil {
 		return host, err
 	}
 
-	host.Labels, err = cm.GetHostLabels(hostname)
+	host.Labels, err = cm.GetHostLabels(poolName, hostname)
 	return host, err
 }
 
@@ -565,6 +621,22 @@ func getSSHConfig(conn Connection) (*ssh.ClientConfig, error) {
 	return conf, nil
 }
 
+// getSSHJumpHosts returns the SSH configuration of each jump host of a connection
+func getSSHJumpHosts(conn Connection) ([]sshutil.JumpHost, error) {
+	if len(conn.JumpHosts) == 0 {
+		return nil, nil
+	}
+	jumpHosts := make([]sshutil.JumpHost, len(conn.JumpHosts))
+	for i, jh := range conn.JumpHosts {
+		conf, err := getSSHConfig(jh)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration for jump host %q", jh.Host)
+		}
+		jumpHosts[i] = sshutil.JumpHost{Config: conf, Host: jh.Host, Port: int(jh.Port)}
+	}
+	return jumpHosts, nil
+}
+
 // Apply a Hosts Pool configuration.
 // If checkpoint is not nil, it should point to a value returned by a previous
 // call to the List() function described above. A checkpoint verification will
@@ -574,11 +646,15 @@ func getSSHConfig(conn Connection) (*ssh.ClientConfig, error) {
 // value.
 // If checkpoint is nil, the Hosts Pool configuration will be applied without
 // checkpoint verification.
-func (cm *consulManager) Apply(pool []Host, checkpoint *uint64) error {
-	return cm.applyWait(pool, checkpoint, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) Apply(poolName string, pool []Host, checkpoint *uint64) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.applyWait(poolName, pool, checkpoint, maxWaitTimeSeconds*time.Second)
 }
 
 func (cm *consulManager) applyWait(
+	poolName string,
 	pool []Host,
 	checkpoint *uint64,
 	maxWaitTime time.Duration) error {
@@ -606,7 +682,7 @@ func (cm *consulManager) applyWait(
 
 	// Take the lock to have a consistent view while computing needed
 	// configuration changes
-	lockCh, cleanupFn, err := cm.lockKey("", "apply", maxWaitTime)
+	lockCh, cleanupFn, err := cm.lockKey(poolName, "", "apply", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -615,7 +691,7 @@ func (cm *consulManager) applyWait(
 	// Get all hosts currently registered to find which ones will have to be
 	// unregistered or updated.
 	// Attempting to unregister a host that is still allocated is illegal
-	registeredHosts, _, runtimeCheckpoint, err := cm.List()
+	registeredHosts, _, runtimeCheckpoint, err := cm.List(poolName)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to get list of registered hosts")
 	}
@@ -645,8 +721,8 @@ func (cm *consulManager) applyWait(
 		if found {
 
 			// Host already in pool, check if an update is needed
-			oldHost, _ := cm.GetHost(host.Name)
-			if oldHost.Connection == host.Connection &&
+			oldHost, _ := cm.GetHost(poolName, host.Name)
+			if reflect.DeepEqual(oldHost.Connection, host.Connection) &&
 				reflect.DeepEqual(oldHost.Labels, host.Labels) {
 
 				// No config change, no update needed, ignoring this host
@@ -662,16 +738,16 @@ func (cm *consulManager) applyWait(
 			// it will be recreated with the same status
 			hostsToUnregisterCheckAllocatedStatus[host.Name] = false
 
-			status, err := cm.GetHostStatus(host.Name)
+			status, err := cm.GetHostStatus(poolName, host.Name)
 			if err != nil {
 				return err
 			}
-			message, err := cm.GetHostMessage(host.Name)
+			message, err := cm.GetHostMessage(poolName, host.Name)
 			if err != nil {
 				return err
 			}
 
-			allocations, err := cm.GetAllocations(host.Name)
+			allocations, err := cm.GetAllocations(poolName, host.Name)
 			if err != nil {
 				return err
 			}
@@ -679,13 +755,13 @@ func (cm *consulManager) applyWait(
 			// Backup status and message if defined are restored at re-creation,
 			// the connection check will be performed afterwards
 			if status == HostStatusError {
-				backupStatus, err := cm.getStatus(host.Name, true)
+				backupStatus, err := cm.getStatus(poolName, host.Name, true)
 				if err == nil {
 					status = backupStatus
-					message, _ = cm.getMessage(host.Name, true)
+					message, _ = cm.getMessage(poolName, host.Name, true)
 				}
 			}
-			ops, err := cm.getAddOperations(host.Name, host.Connection, host.Labels,
+			ops, err := cm.getAddOperations(poolName, host.Name, host.Connection, host.Labels,
 				status, message, allocations)
 			if err != nil {
 				return err
@@ -694,7 +770,7 @@ func (cm *consulManager) applyWait(
 		} else {
 			// Host is new, creating it
 			hostChanged = append(hostChanged, host.Name)
-			ops, err := cm.getAddOperations(host.Name, host.Connection, host.Labels,
+			ops, err := cm.getAddOperations(poolName, host.Name, host.Connection, host.Labels,
 				HostStatusFree, "", nil)
 			if err != nil {
 				return err
@@ -706,7 +782,7 @@ func (cm *consulManager) applyWait(
 	// Now manage hosts to delete
 	var ops api.KVTxnOps
 	for host, checkStatus := range hostsToUnregisterCheckAllocatedStatus {
-		removeOps, err := cm.getRemoveOperations(host, checkStatus)
+		removeOps, err := cm.getRemoveOperations(poolName, host, checkStatus)
 		if err != nil {
 			return err
 		}
@@ -751,7 +827,7 @@ func (cm *consulManager) applyWait(
 	var waitGroup sync.WaitGroup
 	for _, name := range hostChanged {
 		waitGroup.Add(1)
-		go cm.updateConnectionStatus(name, &waitGroup)
+		go cm.updateConnectionStatus(poolName, name, &waitGroup)
 	}
 	waitGroup.Wait()
 
@@ -759,7 +835,7 @@ func (cm *consulManager) applyWait(
 	// Not using querymeta.LastIndex from KV().Txn() as it doesn't work the same
 	// way as in KV().Keys used in cm.List().
 	if checkpoint != nil {
-		_, _, newCheckpoint, errCkpt := cm.List()
+		_, _, newCheckpoint, errCkpt := cm.List(poolName)
 		if errCkpt != nil {
 			// If the apply didn't fail, return this error, else the apply error
 			// takes precedence
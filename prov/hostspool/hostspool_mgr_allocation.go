@@ -28,22 +28,30 @@ import (
 	"github.com/ystia/yorc/v3/helper/labelsutil"
 )
 
-func (cm *consulManager) Allocate(allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error) {
-	return cm.allocateWait(maxWaitTimeSeconds*time.Second, allocation, filters...)
+func (cm *consulManager) Allocate(poolName string, allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error) {
+	if err := checkPoolName(poolName); err != nil {
+		return "", nil, err
+	}
+	return cm.allocateWait(poolName, maxWaitTimeSeconds*time.Second, allocation, filters...)
 }
-func (cm *consulManager) allocateWait(maxWaitTime time.Duration, allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error) {
+
+func (cm *consulManager) allocateWait(poolName string, maxWaitTime time.Duration, allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error) {
 	// Build allocationID
 	if err := allocation.buildID(); err != nil {
 		return "", nil, err
 	}
+	placement, err := getPlacementStrategy(allocation.PlacementPolicy)
+	if err != nil {
+		return "", nil, err
+	}
 
-	lockCh, cleanupFn, err := cm.lockKey("", "allocation", maxWaitTime)
+	lockCh, cleanupFn, err := cm.lockKey(poolName, "", "allocation", maxWaitTime)
 	if err != nil {
 		return "", nil, err
 	}
 	defer cleanupFn()
 
-	hosts, warnings, _, err := cm.List(filters...)
+	hosts, warnings, _, err := cm.List(poolName, filters...)
 	if err != nil {
 		return "", warnings, err
 	}
@@ -56,19 +64,19 @@ func (cm *consulManager) allocateWait(maxWaitTime time.Duration, allocation *All
 			return "", warnings, errors.New("admin lock lost on hosts pool during host allocation")
 		default:
 		}
-		err := cm.checkConnection(h)
+		err := cm.checkConnection(poolName, h)
 		if err != nil {
 			lastErr = err
 			continue
 		}
-		hs, err := cm.GetHostStatus(h)
+		hs, err := cm.GetHostStatus(poolName, h)
 		if err != nil {
 			lastErr = err
 		} else {
 			if hs == HostStatusFree {
 				freeHosts = append(freeHosts, h)
 			} else if hs == HostStatusAllocated && allocation.Shareable {
-				allocations, err := cm.GetAllocations(h)
+				allocations, err := cm.GetAllocations(poolName, h)
 				if err != nil {
 					lastErr = err
 					continue
@@ -88,60 +96,88 @@ func (cm *consulManager) allocateWait(maxWaitTime time.Duration, allocation *All
 		}
 		return "", warnings, errors.WithStack(noMatchingHostFoundError{})
 	}
-	// Get the first host that match
-	hostname := freeHosts[0]
+	hostname, err := cm.selectHost(poolName, placement, allocation, freeHosts)
+	if err != nil {
+		return "", warnings, err
+	}
 	select {
 	case <-lockCh:
 		return "", warnings, errors.New("admin lock lost on hosts pool during host allocation")
 	default:
 	}
 
-	if err := cm.addAllocation(hostname, allocation); err != nil {
+	if allocation.LeaseDuration > 0 {
+		leaseExpiry := time.Now().Add(allocation.LeaseDuration)
+		allocation.LeaseExpiry = &leaseExpiry
+	}
+	if err := cm.addAllocation(poolName, hostname, allocation); err != nil {
 		return "", warnings, errors.Wrapf(err, "failed to add allocation for hostname:%q", hostname)
 	}
 
-	return hostname, warnings, cm.setHostStatus(hostname, HostStatusAllocated)
+	return hostname, warnings, cm.setHostStatus(poolName, hostname, HostStatusAllocated)
 }
-func (cm *consulManager) Release(hostname string, allocation *Allocation) error {
-	return cm.releaseWait(hostname, allocation, maxWaitTimeSeconds*time.Second)
+
+// selectHost applies a placement strategy to select the host of an allocation among the given hosts names
+func (cm *consulManager) selectHost(poolName string, placement PlacementStrategy, allocation *Allocation, hostnames []string) (string, error) {
+	if _, ok := placement.(defaultPlacement); ok {
+		// No need to retrieve hosts details to get the first one
+		return hostnames[0], nil
+	}
+	candidates := make([]Host, len(hostnames))
+	for i, hostname := range hostnames {
+		var err error
+		candidates[i], err = cm.GetHost(poolName, hostname)
+		if err != nil {
+			return "", err
+		}
+	}
+	hostname, err := placement.SelectHost(allocation, candidates)
+	return hostname, errors.Wrapf(err, "failed to select a host for allocation %q", allocation.ID)
 }
 
-func (cm *consulManager) releaseWait(hostname string, allocation *Allocation, maxWaitTime time.Duration) error {
+func (cm *consulManager) Release(poolName, hostname string, allocation *Allocation) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.releaseWait(poolName, hostname, allocation, maxWaitTimeSeconds*time.Second)
+}
+
+func (cm *consulManager) releaseWait(poolName, hostname string, allocation *Allocation, maxWaitTime time.Duration) error {
 	// Build allocationID
 	if err := allocation.buildID(); err != nil {
 		return err
 	}
-	_, cleanupFn, err := cm.lockKey(hostname, "release", maxWaitTime)
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "release", maxWaitTime)
 	if err != nil {
 		return err
 	}
 	defer cleanupFn()
 
-	if err := cm.removeAllocation(hostname, allocation); err != nil {
+	if err := cm.removeAllocation(poolName, hostname, allocation); err != nil {
 		return errors.Wrapf(err, "failed to remove allocation with ID:%q and hostname:%q", allocation.ID, hostname)
 	}
 
-	host, err := cm.GetHost(hostname)
+	host, err := cm.GetHost(poolName, hostname)
 	if err != nil {
 		return err
 	}
 	// Set the host status to free only for host with no allocations
 	if len(host.Allocations) == 0 {
-		if err = cm.setHostStatus(hostname, HostStatusFree); err != nil {
+		if err = cm.setHostStatus(poolName, hostname, HostStatusFree); err != nil {
 			return err
 		}
 	}
-	err = cm.checkConnection(hostname)
+	err = cm.checkConnection(poolName, hostname)
 	if err != nil {
-		cm.backupHostStatus(hostname)
-		cm.setHostStatusWithMessage(hostname, HostStatusError, "failed to connect to host")
+		cm.backupHostStatus(poolName, hostname)
+		cm.setHostStatusWithMessage(poolName, hostname, HostStatusError, "failed to connect to host")
 	}
 	return nil
 }
 
-func getAddAllocationsOperation(hostname string, allocations []Allocation) (api.KVTxnOps, error) {
+func getAddAllocationsOperation(poolName, hostname string, allocations []Allocation) (api.KVTxnOps, error) {
 	allocsOps := api.KVTxnOps{}
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	if allocations != nil {
 		for _, alloc := range allocations {
 			allocKVPrefix := path.Join(hostKVPrefix, "allocations", alloc.ID)
@@ -173,6 +209,10 @@ func getAddAllocationsOperation(hostname string, allocations []Allocation) (api.
 				},
 			}
 
+			if alloc.LeaseExpiry != nil {
+				allocOps = append(allocOps, getLeaseOperations(allocKVPrefix, alloc)...)
+			}
+
 			for k, v := range alloc.Resources {
 				k = url.PathEscape(k)
 				if k == "" {
@@ -191,10 +231,10 @@ func getAddAllocationsOperation(hostname string, allocations []Allocation) (api.
 	return allocsOps, nil
 }
 
-func (cm *consulManager) addAllocation(hostname string, allocation *Allocation) error {
+func (cm *consulManager) addAllocation(poolName, hostname string, allocation *Allocation) error {
 	var allocOps api.KVTxnOps
 	var err error
-	if allocOps, err = getAddAllocationsOperation(hostname, []Allocation{*allocation}); err != nil {
+	if allocOps, err = getAddAllocationsOperation(poolName, hostname, []Allocation{*allocation}); err != nil {
 		return errors.Wrapf(err, "failed to add allocation to host:%q", hostname)
 	}
 
@@ -213,8 +253,8 @@ func (cm *consulManager) addAllocation(hostname string, allocation *Allocation)
 	return nil
 }
 
-func (cm *consulManager) removeAllocation(hostname string, allocation *Allocation) error {
-	_, err := cm.cc.KV().DeleteTree(path.Join(consulutil.HostsPoolPrefix, hostname, "allocations", allocation.ID), nil)
+func (cm *consulManager) removeAllocation(poolName, hostname string, allocation *Allocation) error {
+	_, err := cm.cc.KV().DeleteTree(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "allocations", allocation.ID), nil)
 	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 }
 
@@ -227,12 +267,12 @@ func exist(allocations []Allocation, ID string) bool {
 	return false
 }
 
-func (cm *consulManager) GetAllocations(hostname string) ([]Allocation, error) {
+func (cm *consulManager) GetAllocations(poolName, hostname string) ([]Allocation, error) {
 	allocations := make([]Allocation, 0)
 	if hostname == "" {
 		return nil, errors.WithStack(badRequestError{`"hostname" missing`})
 	}
-	keys, _, err := cm.cc.KV().Keys(path.Join(consulutil.HostsPoolPrefix, hostname, "allocations")+"/", "/", nil)
+	keys, _, err := cm.cc.KV().Keys(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "allocations")+"/", "/", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -279,6 +319,10 @@ func (cm *consulManager) GetAllocations(hostname string) ([]Allocation, error) {
 			}
 		}
 
+		if err = cm.readLease(key, &alloc); err != nil {
+			return nil, err
+		}
+
 		kvps, _, err := cm.cc.KV().List(path.Join(key, "resources"), nil)
 		if err != nil {
 			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
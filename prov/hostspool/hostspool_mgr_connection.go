@@ -15,7 +15,9 @@
 package hostspool
 
 import (
+	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,22 +30,25 @@ import (
 	"github.com/ystia/yorc/v3/helper/consulutil"
 )
 
-func (cm *consulManager) UpdateConnection(hostname string, conn Connection) error {
-	return cm.updateConnectionWait(hostname, conn, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) UpdateConnection(poolName, hostname string, conn Connection) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.updateConnectionWait(poolName, hostname, conn, maxWaitTimeSeconds*time.Second)
 }
-func (cm *consulManager) updateConnectionWait(hostname string, conn Connection, maxWaitTime time.Duration) error {
+func (cm *consulManager) updateConnectionWait(poolName, hostname string, conn Connection, maxWaitTime time.Duration) error {
 	if hostname == "" {
 		return errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 
 	// check if host exists
-	status, err := cm.GetHostStatus(hostname)
+	status, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
 
 	ops := make(api.KVTxnOps, 0)
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	if conn.User != "" {
 		ops = append(ops, &api.KVTxnOp{
 			Verb:  api.KVSet,
@@ -67,7 +72,7 @@ func (cm *consulManager) updateConnectionWait(hostname string, conn Connection,
 	}
 	if conn.PrivateKey != "" {
 		if conn.PrivateKey == "-" {
-			ok, err := cm.DoesHostHasConnectionPassword(hostname)
+			ok, err := cm.DoesHostHasConnectionPassword(poolName, hostname)
 			if err != nil {
 				return err
 			}
@@ -84,7 +89,7 @@ func (cm *consulManager) updateConnectionWait(hostname string, conn Connection,
 	}
 	if conn.Password != "" {
 		if conn.Password == "-" {
-			ok, err := cm.DoesHostHasConnectionPrivateKey(hostname)
+			ok, err := cm.DoesHostHasConnectionPrivateKey(poolName, hostname)
 			if err != nil {
 				return err
 			}
@@ -99,8 +104,23 @@ func (cm *consulManager) updateConnectionWait(hostname string, conn Connection,
 			Value: []byte(conn.Password),
 		})
 	}
+	if len(conn.JumpHosts) > 0 {
+		// Jump hosts are always replaced as a whole
+		ops = append(ops, &api.KVTxnOp{
+			Verb: api.KVDeleteTree,
+			Key:  path.Join(hostKVPrefix, "connection", "jump_hosts") + "/",
+		})
+		// A single jump host named "-" means that jump hosts should be removed
+		if len(conn.JumpHosts) != 1 || conn.JumpHosts[0].Host != "-" {
+			jumpHostsOps, err := getJumpHostsOperations(hostKVPrefix, conn.JumpHosts)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, jumpHostsOps...)
+		}
+	}
 
-	_, cleanupFn, err := cm.lockKey(hostname, "update", maxWaitTime)
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "update", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -119,44 +139,73 @@ func (cm *consulManager) updateConnectionWait(hostname string, conn Connection,
 		return errors.Errorf("Failed to update host %q connection: %s", hostname, strings.Join(errs, ", "))
 	}
 
-	err = cm.checkConnection(hostname)
+	err = cm.checkConnection(poolName, hostname)
 	if err != nil {
 		if status != HostStatusError {
-			cm.backupHostStatus(hostname)
-			cm.setHostStatusWithMessage(hostname, HostStatusError, "failed to connect to host")
+			cm.backupHostStatus(poolName, hostname)
+			cm.setHostStatusWithMessage(poolName, hostname, HostStatusError, "failed to connect to host")
 		}
 		return err
 	}
 	if status == HostStatusError {
-		cm.restoreHostStatus(hostname)
+		cm.restoreHostStatus(poolName, hostname)
 	}
 	return nil
 }
 
-func (cm *consulManager) DoesHostHasConnectionPrivateKey(hostname string) (bool, error) {
-	c, err := cm.GetHostConnection(hostname)
+func (cm *consulManager) DoesHostHasConnectionPrivateKey(poolName, hostname string) (bool, error) {
+	c, err := cm.GetHostConnection(poolName, hostname)
 	if err != nil {
 		return false, err
 	}
 	return c.PrivateKey != "", nil
 }
 
-func (cm *consulManager) DoesHostHasConnectionPassword(hostname string) (bool, error) {
-	c, err := cm.GetHostConnection(hostname)
+func (cm *consulManager) DoesHostHasConnectionPassword(poolName, hostname string) (bool, error) {
+	c, err := cm.GetHostConnection(poolName, hostname)
 	if err != nil {
 		return false, err
 	}
 	return c.Password != "", nil
 }
 
-func (cm *consulManager) GetHostConnection(hostname string) (Connection, error) {
-	conn := Connection{}
+func (cm *consulManager) GetHostConnection(poolName, hostname string) (Connection, error) {
 	if hostname == "" {
-		return conn, errors.WithStack(badRequestError{`"hostname" missing`})
+		return Connection{}, errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 	kv := cm.cc.KV()
-	connKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname, "connection")
+	connKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "connection")
+	conn, err := readConnection(kv, connKVPrefix, hostname)
+	if err != nil {
+		return conn, err
+	}
+
+	jumpHostsKeys, _, err := kv.Keys(path.Join(connKVPrefix, "jump_hosts")+"/", "/", nil)
+	if err != nil {
+		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	indexes := make([]int, 0, len(jumpHostsKeys))
+	for _, key := range jumpHostsKeys {
+		index, err := strconv.Atoi(path.Base(key))
+		if err != nil {
+			return conn, errors.Wrapf(err, "unexpected jump host key %q for host %q", key, hostname)
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		jumpHost, err := readConnection(kv, path.Join(connKVPrefix, "jump_hosts", strconv.Itoa(index)), hostname)
+		if err != nil {
+			return conn, err
+		}
+		conn.JumpHosts = append(conn.JumpHosts, jumpHost)
+	}
+
+	return conn, nil
+}
 
+func readConnection(kv *api.KV, connKVPrefix, hostname string) (Connection, error) {
+	conn := Connection{}
 	kvp, _, err := kv.Get(path.Join(connKVPrefix, "host"), nil)
 	if err != nil {
 		return conn, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
@@ -195,21 +244,77 @@ func (cm *consulManager) GetHostConnection(hostname string) (Connection, error)
 			return conn, errors.Wrapf(err, "failed to retrieve connection port for host %q", hostname)
 		}
 	}
-
 	return conn, nil
 }
 
+// getJumpHostsOperations returns operations storing jump hosts of a host connection
+// applying the same defaults than for the host connection itself.
+func getJumpHostsOperations(hostKVPrefix string, jumpHosts []Connection) (api.KVTxnOps, error) {
+	ops := make(api.KVTxnOps, 0)
+	for i, jh := range jumpHosts {
+		if jh.Host == "" {
+			return nil, errors.WithStack(badRequestError{fmt.Sprintf(`"host" is required for jump host number %d`, i+1)})
+		}
+		if len(jh.JumpHosts) > 0 {
+			return nil, errors.WithStack(badRequestError{fmt.Sprintf(`jump host %q can't define jump hosts by itself`, jh.Host)})
+		}
+		if jh.Password == "" && jh.PrivateKey == "" {
+			return nil, errors.WithStack(badRequestError{fmt.Sprintf(`at least "password" or "private_key" is required for jump host %q`, jh.Host)})
+		}
+		user := jh.User
+		if user == "" {
+			user = "root"
+		}
+		port := jh.Port
+		if port == 0 {
+			port = 22
+		}
+		jhKVPrefix := path.Join(hostKVPrefix, "connection", "jump_hosts", strconv.Itoa(i))
+		ops = append(ops,
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(jhKVPrefix, "host"),
+				Value: []byte(jh.Host),
+			},
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(jhKVPrefix, "user"),
+				Value: []byte(user),
+			},
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(jhKVPrefix, "password"),
+				Value: []byte(jh.Password),
+			},
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(jhKVPrefix, "private_key"),
+				Value: []byte(jh.PrivateKey),
+			},
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(jhKVPrefix, "port"),
+				Value: []byte(strconv.FormatUint(port, 10)),
+			},
+		)
+	}
+	return ops, nil
+}
+
 func resolveTemplatesInConnection(conn *Connection) {
 	conn.User = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.User", conn.User).(string)
 	conn.Password = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.Password", conn.Password).(string)
 	conn.PrivateKey = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.PrivateKey", conn.PrivateKey).(string)
 	conn.Host = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("Connection.Host", conn.Host).(string)
+	for i := range conn.JumpHosts {
+		resolveTemplatesInConnection(&conn.JumpHosts[i])
+	}
 }
 
 // Check if we can log into an host given a connection
-func (cm *consulManager) checkConnection(hostname string) error {
+func (cm *consulManager) checkConnection(poolName, hostname string) error {
 
-	conn, err := cm.GetHostConnection(hostname)
+	conn, err := cm.GetHostConnection(poolName, hostname)
 	if err != nil {
 		return errors.Wrapf(err, "failed to connect to host %q", hostname)
 	}
@@ -218,6 +323,10 @@ func (cm *consulManager) checkConnection(hostname string) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to connect to host %q", hostname)
 	}
+	_, err = getSSHJumpHosts(conn)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to host %q", hostname)
+	}
 
 	client := cm.getSSHClient(conf, conn)
 	_, err = client.RunCommand(`echo "Connected!"`)
@@ -225,25 +334,25 @@ func (cm *consulManager) checkConnection(hostname string) error {
 }
 
 // Go routine checking a Host connection and updating the Host status
-func (cm *consulManager) updateConnectionStatus(name string, waitGroup *sync.WaitGroup) {
+func (cm *consulManager) updateConnectionStatus(poolName, name string, waitGroup *sync.WaitGroup) {
 	defer waitGroup.Done()
-	status, err := cm.GetHostStatus(name)
+	status, err := cm.GetHostStatus(poolName, name)
 	if err != nil {
 		// No such host anymore
 		return
 	}
 
-	err = cm.checkConnection(name)
+	err = cm.checkConnection(poolName, name)
 	if err != nil {
 		if status != HostStatusError {
-			cm.backupHostStatus(name)
-			cm.setHostStatusWithMessage(name, HostStatusError, "failed to connect to host")
+			cm.backupHostStatus(poolName, name)
+			cm.setHostStatusWithMessage(poolName, name, HostStatusError, "failed to connect to host")
 		}
 		return
 	}
 	// Connection is up now. If it was previously down, restoring the status as
 	// it was before the failure (free, allocated)
 	if status == HostStatusError {
-		cm.restoreHostStatus(name)
+		cm.restoreHostStatus(poolName, name)
 	}
 }
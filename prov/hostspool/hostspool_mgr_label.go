@@ -27,15 +27,21 @@ import (
 	"github.com/ystia/yorc/v3/log"
 )
 
-func (cm *consulManager) AddLabels(hostname string, labels map[string]string) error {
-	return cm.addLabelsWait(hostname, labels, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) AddLabels(poolName, hostname string, labels map[string]string) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.addLabelsWait(poolName, hostname, labels, maxWaitTimeSeconds*time.Second)
 }
 
-func (cm *consulManager) RemoveLabels(hostname string, labels []string) error {
-	return cm.removeLabelsWait(hostname, labels, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) RemoveLabels(poolName, hostname string, labels []string) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.removeLabelsWait(poolName, hostname, labels, maxWaitTimeSeconds*time.Second)
 }
 
-func (cm *consulManager) addLabelsWait(hostname string, labels map[string]string, maxWaitTime time.Duration) error {
+func (cm *consulManager) addLabelsWait(poolName, hostname string, labels map[string]string, maxWaitTime time.Duration) error {
 	if hostname == "" {
 		return errors.WithStack(badRequestError{`"hostname" missing`})
 	}
@@ -43,7 +49,7 @@ func (cm *consulManager) addLabelsWait(hostname string, labels map[string]string
 		return nil
 	}
 
-	_, cleanupFn, err := cm.lockKey(hostname, "labels addition", maxWaitTime)
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "labels addition", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -51,16 +57,16 @@ func (cm *consulManager) addLabelsWait(hostname string, labels map[string]string
 
 	// Checks host existence
 	// We don't care about host status for updating labels
-	_, err = cm.GetHostStatus(hostname)
+	_, err = cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
 
-	return cm.addLabels(hostname, labels)
+	return cm.addLabels(poolName, hostname, labels)
 }
 
-func (cm *consulManager) addLabels(hostname string, labels map[string]string) error {
-	ops, err := cm.getAddUpdatedLabelsOperations(hostname, labels)
+func (cm *consulManager) addLabels(poolName, hostname string, labels map[string]string) error {
+	ops, err := cm.getAddUpdatedLabelsOperations(poolName, hostname, labels)
 	if err != nil {
 		return err
 	}
@@ -79,15 +85,15 @@ func (cm *consulManager) addLabels(hostname string, labels map[string]string) er
 	return nil
 }
 
-func (cm *consulManager) getAddUpdatedLabelsOperations(hostname string, labels map[string]string) (api.KVTxnOps, error) {
+func (cm *consulManager) getAddUpdatedLabelsOperations(poolName, hostname string, labels map[string]string) (api.KVTxnOps, error) {
 	// Get labels operations
-	ops, err := cm.getAddLabelsOperations(hostname, labels)
+	ops, err := cm.getAddLabelsOperations(poolName, hostname, labels)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get updated labels operations
-	upLabelsOps, err := cm.getUpdateResourcesLabelsOperationsOnLabelsChange(hostname, labels)
+	upLabelsOps, err := cm.getUpdateResourcesLabelsOperationsOnLabelsChange(poolName, hostname, labels)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +102,7 @@ func (cm *consulManager) getAddUpdatedLabelsOperations(hostname string, labels m
 	return ops, nil
 }
 
-func (cm *consulManager) removeLabelsWait(hostname string, labels []string, maxWaitTime time.Duration) error {
+func (cm *consulManager) removeLabelsWait(poolName, hostname string, labels []string, maxWaitTime time.Duration) error {
 	if hostname == "" {
 		return errors.WithStack(badRequestError{`"hostname" missing`})
 	}
@@ -104,7 +110,7 @@ func (cm *consulManager) removeLabelsWait(hostname string, labels []string, maxW
 		return nil
 	}
 
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	ops := make(api.KVTxnOps, 0)
 
 	for _, v := range labels {
@@ -118,14 +124,14 @@ func (cm *consulManager) removeLabelsWait(hostname string, labels []string, maxW
 		})
 	}
 
-	_, cleanupFn, err := cm.lockKey(hostname, "labels remove", maxWaitTime)
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "labels remove", maxWaitTime)
 	if err != nil {
 		return err
 	}
 	defer cleanupFn()
 
 	// Checks host existence
-	_, err = cm.GetHostStatus(hostname)
+	_, err = cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return err
 	}
@@ -148,17 +154,20 @@ func (cm *consulManager) removeLabelsWait(hostname string, labels []string, maxW
 	return nil
 }
 
-func (cm *consulManager) UpdateResourcesLabels(hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) error {
-	return cm.updateResourcesLabelsWait(hostname, diff, operation, update, maxWaitTimeSeconds*time.Second)
+func (cm *consulManager) UpdateResourcesLabels(poolName, hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) error {
+	if err := checkPoolName(poolName); err != nil {
+		return err
+	}
+	return cm.updateResourcesLabelsWait(poolName, hostname, diff, operation, update, maxWaitTimeSeconds*time.Second)
 }
 
 // Labels must be read and write in the same transaction to avoid concurrency issues
-func (cm *consulManager) updateResourcesLabelsWait(hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error), maxWaitTime time.Duration) error {
+func (cm *consulManager) updateResourcesLabelsWait(poolName, hostname string, diff map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error), maxWaitTime time.Duration) error {
 	if hostname == "" {
 		return errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 
-	lockCh, cleanupFn, err := cm.lockKey(hostname, "updateLabels", maxWaitTime)
+	lockCh, cleanupFn, err := cm.lockKey(poolName, hostname, "updateLabels", maxWaitTime)
 	if err != nil {
 		return err
 	}
@@ -170,7 +179,7 @@ func (cm *consulManager) updateResourcesLabelsWait(hostname string, diff map[str
 	default:
 	}
 
-	labels, err := cm.GetHostLabels(hostname)
+	labels, err := cm.GetHostLabels(poolName, hostname)
 
 	upLabels, err := update(labels, diff, operation)
 	if err != nil {
@@ -182,7 +191,7 @@ func (cm *consulManager) updateResourcesLabelsWait(hostname string, diff map[str
 	}
 
 	log.Debugf("Updating labels:%+v", upLabels)
-	ops, err := cm.getAddLabelsOperations(hostname, upLabels)
+	ops, err := cm.getAddLabelsOperations(poolName, hostname, upLabels)
 	if err != nil {
 		return err
 	}
@@ -202,16 +211,16 @@ func (cm *consulManager) updateResourcesLabelsWait(hostname string, diff map[str
 	return nil
 }
 
-func (cm *consulManager) GetHostLabels(hostname string) (map[string]string, error) {
+func (cm *consulManager) GetHostLabels(poolName, hostname string) (map[string]string, error) {
 	if hostname == "" {
 		return nil, errors.WithStack(badRequestError{`"hostname" missing`})
 	}
 	// check if host exists
-	_, err := cm.GetHostStatus(hostname)
+	_, err := cm.GetHostStatus(poolName, hostname)
 	if err != nil {
 		return nil, err
 	}
-	kvps, _, err := cm.cc.KV().List(path.Join(consulutil.HostsPoolPrefix, hostname, "labels"), nil)
+	kvps, _, err := cm.cc.KV().List(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "labels"), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -222,8 +231,8 @@ func (cm *consulManager) GetHostLabels(hostname string) (map[string]string, erro
 	return labels, nil
 }
 
-func (cm *consulManager) getUpdateResourcesLabelsOperationsOnLabelsChange(hostname string, newLabels map[string]string) (api.KVTxnOps, error) {
-	allocs, err := cm.GetAllocations(hostname)
+func (cm *consulManager) getUpdateResourcesLabelsOperationsOnLabelsChange(poolName, hostname string, newLabels map[string]string) (api.KVTxnOps, error) {
+	allocs, err := cm.GetAllocations(poolName, hostname)
 	if err != nil {
 		return nil, err
 	}
@@ -236,10 +245,10 @@ func (cm *consulManager) getUpdateResourcesLabelsOperationsOnLabelsChange(hostna
 			return nil, err
 		}
 	}
-	return cm.getAddLabelsOperations(hostname, upLabels)
+	return cm.getAddLabelsOperations(poolName, hostname, upLabels)
 }
 
-func (cm *consulManager) getUpdateResourcesLabelsOperations(hostname string, diff map[string]string, new map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) (api.KVTxnOps, error) {
+func (cm *consulManager) getUpdateResourcesLabelsOperations(poolName, hostname string, diff map[string]string, new map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) (api.KVTxnOps, error) {
 	upLabels, err := cm.calculateLabels(diff, new, operation, update)
 	if err != nil {
 		return nil, err
@@ -247,7 +256,7 @@ func (cm *consulManager) getUpdateResourcesLabelsOperations(hostname string, dif
 	if upLabels == nil || len(upLabels) == 0 {
 		return nil, nil
 	}
-	return cm.getAddLabelsOperations(hostname, upLabels)
+	return cm.getAddLabelsOperations(poolName, hostname, upLabels)
 }
 
 func (cm *consulManager) calculateLabels(diff map[string]string, new map[string]string, operation func(a int64, b int64) int64, update func(orig map[string]string, diff map[string]string, operation func(a int64, b int64) int64) (map[string]string, error)) (map[string]string, error) {
@@ -263,8 +272,8 @@ func (cm *consulManager) calculateLabels(diff map[string]string, new map[string]
 	return upLabels, nil
 }
 
-func (cm *consulManager) getAddLabelsOperations(hostname string, labels map[string]string) (api.KVTxnOps, error) {
-	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, hostname)
+func (cm *consulManager) getAddLabelsOperations(poolName, hostname string, labels map[string]string) (api.KVTxnOps, error) {
+	hostKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname)
 	ops := make(api.KVTxnOps, 0)
 	for k, v := range labels {
 		k = url.PathEscape(k)
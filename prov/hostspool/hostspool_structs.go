@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
 
 	"fmt"
 	"github.com/pkg/errors"
@@ -68,6 +69,9 @@ type Connection struct {
 	Host string `json:"host,omitempty" yaml:"host,omitempty"`
 	// The Port to connect to. Defaults to 22 if set to 0.
 	Port uint64 `json:"port,omitempty" yaml:"port,omitempty"`
+	// JumpHosts is an optional ordered chain of bastion hosts used to reach the Host.
+	// Jump hosts can't define jump hosts by themselves.
+	JumpHosts []Connection `json:"jump_hosts,omitempty" yaml:"jump_hosts,omitempty" mapstructure:"jump_hosts"`
 }
 
 // String allows to stringify a connection
@@ -80,7 +84,16 @@ func (conn Connection) String() string {
 		key = "private key: " + conn.PrivateKey + ", "
 	}
 
-	return "user: " + conn.User + ", " + pass + key + "host: " + conn.Host + ", " + "port: " + strconv.FormatUint(conn.Port, 10)
+	var jumpHosts string
+	if len(conn.JumpHosts) > 0 {
+		jhs := make([]string, len(conn.JumpHosts))
+		for i, jh := range conn.JumpHosts {
+			jhs[i] = jh.User + "@" + jh.Host + ":" + strconv.FormatUint(jh.Port, 10)
+		}
+		jumpHosts = ", jump hosts: " + strings.Join(jhs, " -> ")
+	}
+
+	return "user: " + conn.User + ", " + pass + key + "host: " + conn.Host + ", " + "port: " + strconv.FormatUint(conn.Port, 10) + jumpHosts
 }
 
 // An Host holds information on an Host as it is known by the hostspool
@@ -101,6 +114,15 @@ type Allocation struct {
 	DeploymentID string            `json:"deployment_id"`
 	Shareable    bool              `json:"shareable"`
 	Resources    map[string]string `json:"resource_labels,omitempty"`
+	// PlacementPolicy is the name of the placement strategy used to select the host of this allocation.
+	// It is only used at allocation time, the default strategy applies if empty.
+	PlacementPolicy string `json:"-"`
+	// LeaseDuration is the duration of the lease of this allocation, the allocation never expires if it is 0.
+	LeaseDuration time.Duration `json:"-"`
+	// LeaseExpiry is the time at which the lease of this allocation expires, it is nil if the allocation has no lease.
+	LeaseExpiry *time.Time `json:"lease_expiry,omitempty"`
+	// LeaseExpired is set by the leases reaper when the lease of this allocation expired.
+	LeaseExpired bool `json:"lease_expired,omitempty"`
 }
 
 func (alloc *Allocation) String() string {
@@ -110,6 +132,12 @@ func (alloc *Allocation) String() string {
 			allocStr += "," + k + ": " + v
 		}
 	}
+	if alloc.LeaseExpiry != nil {
+		allocStr += ",lease expiry: " + alloc.LeaseExpiry.Format(time.RFC3339)
+		if alloc.LeaseExpired {
+			allocStr += " (expired)"
+		}
+	}
 
 	return allocStr
 }
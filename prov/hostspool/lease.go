@@ -0,0 +1,167 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+func getLeaseOperations(allocKVPrefix string, alloc Allocation) api.KVTxnOps {
+	ops := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(allocKVPrefix, "lease_duration"),
+			Value: []byte(alloc.LeaseDuration.String()),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(allocKVPrefix, "lease_expiry"),
+			Value: []byte(alloc.LeaseExpiry.Format(time.RFC3339Nano)),
+		},
+	}
+	if alloc.LeaseExpired {
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(allocKVPrefix, "lease_expired"),
+			Value: []byte("true"),
+		})
+	} else {
+		ops = append(ops, &api.KVTxnOp{
+			Verb: api.KVDelete,
+			Key:  path.Join(allocKVPrefix, "lease_expired"),
+		})
+	}
+	return ops
+}
+
+// readLease reads the lease of an allocation stored under the given key
+func (cm *consulManager) readLease(allocKVPrefix string, alloc *Allocation) error {
+	kvp, _, err := cm.cc.KV().Get(path.Join(allocKVPrefix, "lease_expiry"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil
+	}
+	leaseExpiry, err := time.Parse(time.RFC3339Nano, string(kvp.Value))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse lease expiry from value:%q", string(kvp.Value))
+	}
+	alloc.LeaseExpiry = &leaseExpiry
+
+	kvp, _, err = cm.cc.KV().Get(path.Join(allocKVPrefix, "lease_duration"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		alloc.LeaseDuration, err = time.ParseDuration(string(kvp.Value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse lease duration from value:%q", string(kvp.Value))
+		}
+	}
+
+	kvp, _, err = cm.cc.KV().Get(path.Join(allocKVPrefix, "lease_expired"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		alloc.LeaseExpired, err = strconv.ParseBool(string(kvp.Value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse boolean from value:%q", string(kvp.Value))
+		}
+	}
+	return nil
+}
+
+func (cm *consulManager) RenewLease(poolName, hostname, allocationID string, duration time.Duration) (Allocation, error) {
+	if err := checkPoolName(poolName); err != nil {
+		return Allocation{}, err
+	}
+	return cm.renewLeaseWait(poolName, hostname, allocationID, duration, maxWaitTimeSeconds*time.Second)
+}
+
+// renewLeaseWait extends the lease of an allocation from now for the given duration
+//
+// If the given duration is 0, the lease duration of the allocation is used.
+func (cm *consulManager) renewLeaseWait(poolName, hostname, allocationID string, duration time.Duration, maxWaitTime time.Duration) (Allocation, error) {
+	if duration < 0 {
+		return Allocation{}, errors.WithStack(badRequestError{"lease duration should be positive"})
+	}
+	_, cleanupFn, err := cm.lockKey(poolName, hostname, "lease renewal", maxWaitTime)
+	if err != nil {
+		return Allocation{}, err
+	}
+	defer cleanupFn()
+
+	alloc, err := cm.getAllocation(poolName, hostname, allocationID)
+	if err != nil {
+		return alloc, err
+	}
+	if duration == 0 {
+		duration = alloc.LeaseDuration
+	}
+	if duration == 0 {
+		return alloc, errors.WithStack(badRequestError{fmt.Sprintf("allocation %q has no lease, a lease duration is required", allocationID)})
+	}
+	leaseExpiry := time.Now().Add(duration)
+	alloc.LeaseDuration = duration
+	alloc.LeaseExpiry = &leaseExpiry
+	alloc.LeaseExpired = false
+
+	allocKVPrefix := path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "allocations", allocationID)
+	ok, response, _, err := cm.cc.KV().Txn(getLeaseOperations(allocKVPrefix, alloc), nil)
+	if err != nil {
+		return alloc, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !ok {
+		errs := make([]string, 0)
+		for _, e := range response.Errors {
+			errs = append(errs, e.What)
+		}
+		return alloc, errors.Errorf("Failed to renew lease of allocation %q on host %q: %s", allocationID, hostname, strings.Join(errs, ", "))
+	}
+	return alloc, nil
+}
+
+// setLeaseExpired flags the lease of an allocation as expired
+func (cm *consulManager) setLeaseExpired(poolName, hostname, allocationID string) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.HostsPoolPrefix, poolName, hostname, "allocations", allocationID, "lease_expired"), "true")
+}
+
+func (cm *consulManager) getAllocation(poolName, hostname, allocationID string) (Allocation, error) {
+	// Check the host exists
+	if _, err := cm.GetHostStatus(poolName, hostname); err != nil {
+		return Allocation{}, err
+	}
+	allocations, err := cm.GetAllocations(poolName, hostname)
+	if err != nil {
+		return Allocation{}, err
+	}
+	for _, alloc := range allocations {
+		if alloc.ID == allocationID {
+			return alloc, nil
+		}
+	}
+	return Allocation{}, errors.WithStack(allocationNotFoundError{})
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tasks/collector"
+)
+
+// defaultLeaseReaperInterval is the default delay between two checks of expired leases
+const defaultLeaseReaperInterval = time.Minute
+
+var defaultReaper *leaseReaper
+
+// A leaseReaper flags allocations having an expired lease
+//
+// Only the leader Yorc server checks leases.
+type leaseReaper struct {
+	cm               *consulManager
+	collector        *collector.Collector
+	interval         time.Duration
+	undeployOnExpiry bool
+	serviceKey       string
+	chShutdown       chan struct{}
+	chStopReaping    chan struct{}
+	isActive         bool
+	isActiveLock     sync.Mutex
+}
+
+// StartLeaseReaper allows to instantiate a default leases reaper checking expired leases when this server is the leader
+//
+// The delay between two checks is defined by the lease_reaper_interval option of the hosts pool configuration.
+// If the undeploy_on_lease_expiry option is set, deployments owning an expired allocation are undeployed.
+func StartLeaseReaper(cfg config.Configuration, cc *api.Client) {
+	hpCfg := cfg.Infrastructures[infrastructureName]
+	interval := hpCfg.GetDuration("lease_reaper_interval")
+	if interval <= 0 {
+		interval = defaultLeaseReaperInterval
+	}
+	defaultReaper = &leaseReaper{
+		cm:               &consulManager{cc: cc},
+		collector:        collector.NewCollector(cc),
+		interval:         interval,
+		undeployOnExpiry: hpCfg.GetBool("undeploy_on_lease_expiry"),
+		serviceKey:       path.Join(consulutil.YorcServicePrefix, "/hostspool_leases/leader"),
+		chShutdown:       make(chan struct{}),
+	}
+	// Watch leader election for leases reaper
+	go consulutil.WatchLeaderElection(cc, defaultReaper.serviceKey, defaultReaper.chShutdown, defaultReaper.startReaping, defaultReaper.stopReaping)
+}
+
+// StopLeaseReaper allows to stop checking expired leases
+func StopLeaseReaper() {
+	defaultReaper.stopReaping()
+
+	// Stop watch leader election
+	close(defaultReaper.chShutdown)
+}
+
+func handleReaperError(err error) {
+	err = errors.Wrap(err, "[WARN] Error during hosts pool leases check")
+	log.Print(err)
+	log.Debugf("%+v", err)
+}
+
+func (r *leaseReaper) startReaping() {
+	r.isActiveLock.Lock()
+	defer r.isActiveLock.Unlock()
+	if r.isActive {
+		log.Println("Hosts pool leases reaper is already running.")
+		return
+	}
+	log.Debugf("Hosts pool leases reaper is now running.")
+	r.isActive = true
+	r.chStopReaping = make(chan struct{})
+	go r.run(r.chStopReaping)
+}
+
+func (r *leaseReaper) stopReaping() {
+	r.isActiveLock.Lock()
+	defer r.isActiveLock.Unlock()
+	if r.isActive {
+		log.Debugf("Hosts pool leases reaper is about to be stopped")
+		close(r.chStopReaping)
+		r.isActive = false
+	}
+}
+
+func (r *leaseReaper) run(chStop chan struct{}) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-chStop:
+			log.Debugf("Ending hosts pool leases reaper has been requested: stop it now.")
+			return
+		case <-r.chShutdown:
+			log.Debugf("Shutdown has been sent: stop hosts pool leases reaper now.")
+			return
+		case <-ticker.C:
+			if err := r.reap(time.Now()); err != nil {
+				handleReaperError(err)
+			}
+		}
+	}
+}
+
+// reap flags allocations of all pools having a lease expired at the given time
+//
+// An error on a pool, a host or an allocation does not prevent other ones from being checked,
+// errors are aggregated and returned once all pools were checked.
+func (r *leaseReaper) reap(now time.Time) error {
+	pools, err := r.cm.ListPools()
+	if err != nil {
+		return err
+	}
+	var errs error
+	undeployed := make(map[string]bool)
+	for _, poolName := range pools {
+		hosts, _, _, err := r.cm.List(poolName)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to list hosts of pool %q", poolName))
+			continue
+		}
+		for _, hostname := range hosts {
+			allocations, err := r.cm.GetAllocations(poolName, hostname)
+			if err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "failed to get allocations of host %q of pool %q", hostname, poolName))
+				continue
+			}
+			for _, alloc := range allocations {
+				if alloc.LeaseExpiry == nil || alloc.LeaseExpired || now.Before(*alloc.LeaseExpiry) {
+					continue
+				}
+				if err = r.expire(poolName, hostname, alloc, undeployed); err != nil {
+					errs = multierror.Append(errs, errors.Wrapf(err, "failed to expire allocation %q of host %q of pool %q", alloc.ID, hostname, poolName))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// expire undeploys the deployment owning an allocation if required and then flags the allocation lease as expired
+//
+// The lease is flagged only once the undeployment is registered, so that a failed registration is retried on next check.
+func (r *leaseReaper) expire(poolName, hostname string, alloc Allocation, undeployed map[string]bool) error {
+	ctx := events.AddLogOptionalFields(context.Background(), events.LogOptionalFields{events.NodeID: alloc.NodeName, events.InstanceID: alloc.Instance})
+	if r.undeployOnExpiry && !undeployed[alloc.DeploymentID] {
+		if err := r.undeploy(ctx, poolName, hostname, alloc); err != nil {
+			return err
+		}
+		undeployed[alloc.DeploymentID] = true
+	}
+
+	if err := r.cm.setLeaseExpired(poolName, hostname, alloc.ID); err != nil {
+		return err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, alloc.DeploymentID).Registerf(
+		"lease of host %q of hosts pool %q expired at %s", hostname, poolName, alloc.LeaseExpiry.Format(time.RFC3339))
+	return nil
+}
+
+// undeploy registers an undeployment task for the deployment owning an expired allocation
+// unless this deployment is already undeployed or being undeployed
+func (r *leaseReaper) undeploy(ctx context.Context, poolName, hostname string, alloc Allocation) error {
+	status, err := deployments.GetDeploymentStatus(r.cm.cc.KV(), alloc.DeploymentID)
+	if err != nil {
+		return err
+	}
+	if status == deployments.UNDEPLOYED || status == deployments.UNDEPLOYMENT_IN_PROGRESS {
+		return nil
+	}
+	taskID, err := r.collector.RegisterTaskWithData(alloc.DeploymentID, tasks.TaskTypeUnDeploy, map[string]string{"workflowName": "uninstall"})
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, alloc.DeploymentID).Registerf(
+			"failed to undeploy deployment after lease expiry of host %q of hosts pool %q, will retry: %v", hostname, poolName, err)
+		return err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, alloc.DeploymentID).Registerf(
+		"undeploying deployment after lease expiry of host %q of hosts pool %q (task %q)", hostname, poolName, taskID)
+	return nil
+}
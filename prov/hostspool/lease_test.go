@@ -0,0 +1,121 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+func testConsulManagerAllocateWithLease(t *testing.T, cc *api.Client) {
+	cleanupHostsPool(t, cc)
+	cm := &consulManager{cc, mockSSHClientFactory}
+	var checkpoint uint64
+	require.NoError(t, cm.Apply(testPool, createHosts(2), &checkpoint))
+
+	before := time.Now()
+	hostname, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "0", DeploymentID: "lease", LeaseDuration: time.Hour})
+	require.NoError(t, err)
+	allocations, err := cm.GetAllocations(testPool, hostname)
+	require.NoError(t, err)
+	require.Len(t, allocations, 1)
+	require.NotNil(t, allocations[0].LeaseExpiry)
+	assert.Equal(t, time.Hour, allocations[0].LeaseDuration)
+	assert.False(t, allocations[0].LeaseExpiry.Before(before.Add(time.Hour)))
+	assert.False(t, allocations[0].LeaseExpired)
+
+	// Allocations without lease never expire
+	otherHost, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "1", DeploymentID: "lease"})
+	require.NoError(t, err)
+	allocations, err = cm.GetAllocations(testPool, otherHost)
+	require.NoError(t, err)
+	require.Len(t, allocations, 1)
+	assert.Nil(t, allocations[0].LeaseExpiry)
+
+	// Flag expired leases
+	reaper := &leaseReaper{cm: cm}
+	require.NoError(t, reaper.reap(time.Now().Add(30*time.Minute)))
+	alloc, err := cm.getAllocation(testPool, hostname, "lease-node-0")
+	require.NoError(t, err)
+	assert.False(t, alloc.LeaseExpired)
+	require.NoError(t, reaper.reap(time.Now().Add(2*time.Hour)))
+	alloc, err = cm.getAllocation(testPool, hostname, "lease-node-0")
+	require.NoError(t, err)
+	assert.True(t, alloc.LeaseExpired)
+	alloc, err = cm.getAllocation(testPool, otherHost, "lease-node-1")
+	require.NoError(t, err)
+	assert.False(t, alloc.LeaseExpired)
+
+	// Leases are preserved on apply
+	hosts := createHosts(2)
+	hosts[0].Labels = map[string]string{"label": "value"}
+	require.NoError(t, cm.Apply(testPool, hosts, nil))
+	alloc, err = cm.getAllocation(testPool, hostname, "lease-node-0")
+	require.NoError(t, err)
+	require.NotNil(t, alloc.LeaseExpiry)
+	assert.True(t, alloc.LeaseExpired)
+
+	// Renewal extends the lease and clears the expired flag
+	alloc, err = cm.RenewLease(testPool, hostname, "lease-node-0", 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, alloc.LeaseDuration)
+	assert.False(t, alloc.LeaseExpired)
+	alloc, err = cm.RenewLease(testPool, hostname, "lease-node-0", 2*time.Hour)
+	require.NoError(t, err)
+	alloc, err = cm.getAllocation(testPool, hostname, "lease-node-0")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, alloc.LeaseDuration)
+	assert.False(t, alloc.LeaseExpired)
+	assert.False(t, alloc.LeaseExpiry.Before(before.Add(2*time.Hour)))
+
+	_, err = cm.RenewLease(testPool, otherHost, "lease-node-1", 0)
+	assert.True(t, IsBadRequestError(err), "unexpected error %v", err)
+	_, err = cm.RenewLease(testPool, hostname, "unknown", time.Hour)
+	assert.True(t, IsAllocationNotFoundError(err), "unexpected error %v", err)
+	_, err = cm.RenewLease(testPool, "unknown", "lease-node-0", time.Hour)
+	assert.True(t, IsHostNotFoundError(err), "unexpected error %v", err)
+}
+
+func testLeaseReaperUndeployOnExpiry(t *testing.T, cc *api.Client) {
+	cleanupHostsPool(t, cc)
+	cm := &consulManager{cc, mockSSHClientFactory}
+	var checkpoint uint64
+	require.NoError(t, cm.Apply(testPool, createHosts(2), &checkpoint))
+
+	_, err := cc.KV().Put(&api.KVPair{Key: path.Join(consulutil.DeploymentKVPrefix, "lease-undeployed", "status"), Value: []byte("UNDEPLOYED")}, nil)
+	require.NoError(t, err)
+	unknownHost, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "0", DeploymentID: "lease-unknown", LeaseDuration: time.Hour})
+	require.NoError(t, err)
+	undeployedHost, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "0", DeploymentID: "lease-undeployed", LeaseDuration: time.Hour})
+	require.NoError(t, err)
+
+	// The lease of an allocation which deployment can't be undeployed is not flagged
+	// and does not prevent other leases from being flagged
+	reaper := &leaseReaper{cm: cm, undeployOnExpiry: true}
+	require.Error(t, reaper.reap(time.Now().Add(2*time.Hour)))
+	alloc, err := cm.getAllocation(testPool, unknownHost, "lease-unknown-node-0")
+	require.NoError(t, err)
+	assert.False(t, alloc.LeaseExpired)
+	alloc, err = cm.getAllocation(testPool, undeployedHost, "lease-undeployed-node-0")
+	require.NoError(t, err)
+	assert.True(t, alloc.LeaseExpired)
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPlacement is the name of the placement strategy selecting the first matching host
+	DefaultPlacement = "default"
+	// BinPackingPlacement is the name of the placement strategy selecting the host that best fits the allocation resources,
+	// that is the host having the fewest resources left after the allocation
+	BinPackingPlacement = "bin-packing"
+	// SpreadPlacement is the name of the placement strategy selecting the host having the fewest allocations
+	SpreadPlacement = "spread"
+	// RandomPlacement is the name of the placement strategy selecting a random host
+	RandomPlacement = "random"
+)
+
+// resourcesLabels are the labels considered by the bin-packing strategy by order of precedence
+var resourcesLabels = []string{"host.num_cpus", "host.mem_size", "host.disk_size"}
+
+// A PlacementStrategy selects the host an allocation is placed on among the hosts matching its filters
+type PlacementStrategy interface {
+	// SelectHost returns the name of the selected host, candidates are never empty
+	SelectHost(allocation *Allocation, candidates []Host) (string, error)
+}
+
+var placementStrategiesLock sync.RWMutex
+var placementStrategies = map[string]PlacementStrategy{
+	DefaultPlacement:    defaultPlacement{},
+	BinPackingPlacement: binPackingPlacement{},
+	SpreadPlacement:     spreadPlacement{},
+	RandomPlacement:     &randomPlacement{rand: rand.New(rand.NewSource(time.Now().UnixNano()))},
+}
+
+// RegisterPlacementStrategy makes a placement strategy available under the given name, it overrides any strategy
+// previously registered with the same name
+func RegisterPlacementStrategy(name string, strategy PlacementStrategy) {
+	placementStrategiesLock.Lock()
+	defer placementStrategiesLock.Unlock()
+	placementStrategies[strings.ToLower(name)] = strategy
+}
+
+// getPlacementStrategy returns the placement strategy registered with the given name, an empty name
+// stands for the default strategy
+func getPlacementStrategy(name string) (PlacementStrategy, error) {
+	if name == "" {
+		name = DefaultPlacement
+	}
+	placementStrategiesLock.RLock()
+	defer placementStrategiesLock.RUnlock()
+	strategy, ok := placementStrategies[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.WithStack(badRequestError{"unknown placement strategy " + strconv.Quote(name)})
+	}
+	return strategy, nil
+}
+
+type defaultPlacement struct{}
+
+func (p defaultPlacement) SelectHost(allocation *Allocation, candidates []Host) (string, error) {
+	return candidates[0].Name, nil
+}
+
+type spreadPlacement struct{}
+
+func (p spreadPlacement) SelectHost(allocation *Allocation, candidates []Host) (string, error) {
+	selected := candidates[0]
+	for _, h := range candidates[1:] {
+		if len(h.Allocations) < len(selected.Allocations) {
+			selected = h
+		}
+	}
+	return selected.Name, nil
+}
+
+type randomPlacement struct {
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+func (p *randomPlacement) SelectHost(allocation *Allocation, candidates []Host) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return candidates[p.rand.Intn(len(candidates))].Name, nil
+}
+
+type binPackingPlacement struct{}
+
+// SelectHost selects the host having the fewest resources left once the allocation resources are taken into account.
+//
+// Resources are compared by order of precedence: number of CPUs, memory size then disk size. Hosts not defining
+// a resource label are considered as having more of this resource than any host defining it. Ties are broken
+// by selecting the host having the most allocations.
+func (p binPackingPlacement) SelectHost(allocation *Allocation, candidates []Host) (string, error) {
+	type candidate struct {
+		name        string
+		remaining   []*float64
+		allocations int
+	}
+	sorted := make([]candidate, len(candidates))
+	for i, h := range candidates {
+		c := candidate{name: h.Name, remaining: make([]*float64, len(resourcesLabels)), allocations: len(h.Allocations)}
+		for j, label := range resourcesLabels {
+			value, ok := h.Labels[label]
+			if !ok {
+				continue
+			}
+			available, err := parseResource(label, value)
+			if err != nil {
+				return "", errors.Wrapf(err, "invalid label %q for host %q", label, h.Name)
+			}
+			if requested, ok := allocation.Resources[label]; ok {
+				r, err := parseResource(label, requested)
+				if err != nil {
+					return "", errors.Wrapf(err, "invalid resource %q for allocation %q", label, allocation.ID)
+				}
+				available -= r
+			}
+			c.remaining[j] = &available
+		}
+		sorted[i] = c
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for k := range resourcesLabels {
+			ri, rj := sorted[i].remaining[k], sorted[j].remaining[k]
+			switch {
+			case ri == nil && rj == nil:
+				continue
+			case rj == nil:
+				return true
+			case ri == nil:
+				return false
+			case *ri != *rj:
+				return *ri < *rj
+			}
+		}
+		return sorted[i].allocations > sorted[j].allocations
+	})
+	return sorted[0].name, nil
+}
+
+func parseResource(label, value string) (float64, error) {
+	if label == "host.num_cpus" {
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	}
+	b, err := humanize.ParseBytes(value)
+	return float64(b), err
+}
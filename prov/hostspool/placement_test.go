@@ -0,0 +1,102 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementStrategies(t *testing.T) {
+	small := Host{Name: "small", Labels: map[string]string{"host.num_cpus": "4", "host.mem_size": "8 GB"}}
+	big := Host{Name: "big", Labels: map[string]string{"host.num_cpus": "32", "host.mem_size": "128 GB"},
+		Allocations: []Allocation{{ID: "a1"}}}
+	medium := Host{Name: "medium", Labels: map[string]string{"host.num_cpus": "4", "host.mem_size": "16 GB"},
+		Allocations: []Allocation{{ID: "a2"}, {ID: "a3"}}}
+	unlabeled := Host{Name: "unlabeled"}
+
+	tests := []struct {
+		name       string
+		strategy   string
+		allocation *Allocation
+		candidates []Host
+		want       string
+		wantErr    bool
+	}{
+		{"DefaultTakesFirst", DefaultPlacement, &Allocation{}, []Host{big, small}, "big", false},
+		{"EmptyNameIsDefault", "", &Allocation{}, []Host{big, small}, "big", false},
+		{"SpreadFewestAllocations", SpreadPlacement, &Allocation{}, []Host{medium, big, small}, "small", false},
+		{"SpreadKeepsOrderOnTies", SpreadPlacement, &Allocation{}, []Host{unlabeled, small}, "unlabeled", false},
+		{"BinPackingSmallestHost", BinPackingPlacement, &Allocation{}, []Host{big, unlabeled, medium, small}, "small", false},
+		{"BinPackingBestFit", BinPackingPlacement, &Allocation{Resources: map[string]string{"host.num_cpus": "2", "host.mem_size": "12 GB"}},
+			[]Host{big, medium}, "medium", false},
+		{"BinPackingMostAllocationsOnTies", BinPackingPlacement, &Allocation{}, []Host{unlabeled, {Name: "other", Allocations: []Allocation{{ID: "a4"}}}}, "other", false},
+		{"BinPackingInvalidLabel", BinPackingPlacement, &Allocation{}, []Host{{Name: "invalid", Labels: map[string]string{"host.num_cpus": "many"}}}, "", true},
+		{"CaseInsensitive", "Spread", &Allocation{}, []Host{medium, small}, "small", false},
+		{"Unknown", "first-come", &Allocation{}, []Host{small}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := getPlacementStrategy(tt.strategy)
+			if err == nil {
+				var hostname string
+				hostname, err = strategy.SelectHost(tt.allocation, tt.candidates)
+				assert.Equal(t, tt.want, hostname)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	random, err := getPlacementStrategy(RandomPlacement)
+	require.NoError(t, err)
+	hostname, err := random.SelectHost(&Allocation{}, []Host{small, big, medium})
+	require.NoError(t, err)
+	assert.Contains(t, []string{"small", "big", "medium"}, hostname)
+}
+
+func testConsulManagerAllocateWithPlacement(t *testing.T, cc *api.Client) {
+	cleanupHostsPool(t, cc)
+	cm := &consulManager{cc, mockSSHClientFactory}
+	hostpool := createHosts(2)
+	hostpool[0].Labels = map[string]string{"host.num_cpus": "16", "host.mem_size": "64 GB"}
+	hostpool[1].Labels = map[string]string{"host.num_cpus": "4", "host.mem_size": "8 GB"}
+	var checkpoint uint64
+	require.NoError(t, cm.Apply(testPool, hostpool, &checkpoint))
+
+	resources := map[string]string{"host.num_cpus": "2", "host.mem_size": "4 GB"}
+	hostname, _, err := cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "0", DeploymentID: "placement", Shareable: true, Resources: resources})
+	require.NoError(t, err)
+	assert.Equal(t, "host0", hostname, "default placement should select the first host")
+
+	hostname, _, err = cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "1", DeploymentID: "placement", Shareable: true, Resources: resources, PlacementPolicy: BinPackingPlacement})
+	require.NoError(t, err)
+	assert.Equal(t, "host1", hostname, "bin-packing placement should select the smallest host")
+
+	require.NoError(t, cm.Release(testPool, "host0", &Allocation{NodeName: "node", Instance: "0", DeploymentID: "placement"}))
+	hostname, _, err = cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "2", DeploymentID: "placement", Shareable: true, PlacementPolicy: SpreadPlacement})
+	require.NoError(t, err)
+	assert.Equal(t, "host0", hostname, "spread placement should select the host without allocations")
+
+	_, _, err = cm.Allocate(testPool, &Allocation{NodeName: "node", Instance: "3", DeploymentID: "placement", PlacementPolicy: "unknown"})
+	require.Error(t, err)
+	assert.True(t, IsBadRequestError(err))
+}
@@ -29,6 +29,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/ystia/yorc/v3/config"
@@ -99,15 +100,18 @@ func newExecution(kv *api.KV, cfg config.Configuration, taskID, deploymentID, no
 	}, nil
 }
 
-func (e *execution) execute(ctx context.Context, clientset kubernetes.Interface) error {
+func (e *execution) execute(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface) error {
 
 	if e.nodeType == "yorc.nodes.kubernetes.api.types.JobResource" {
 		return e.executeJobOperation(ctx, clientset)
 	}
+	if e.operation.ImplementationArtifact == kubernetesHelmChartImplementation {
+		return e.executeHelmOperation(ctx, clientset)
+	}
 
 	// TODO is there any reason for recreating a new generator for each execution?
 	generator := newGenerator(e.kv, e.cfg)
-	instances, err := tasks.GetInstances(e.kv, e.taskID, e.deploymentID, e.nodeName)
+	instances, err := tasks.GetInstancesWithContext(ctx, e.kv, e.taskID, e.deploymentID, e.nodeName)
 	nbInstances := int32(len(instances))
 
 	// Supporting both fully qualified and short standard operation names, ie.
@@ -118,7 +122,7 @@ func (e *execution) execute(ctx context.Context, clientset kubernetes.Interface)
 		"tosca.interfaces.node.lifecycle.")
 	switch operationName {
 	case "standard.create":
-		return e.manageKubernetesResource(ctx, clientset, generator, k8sCreateOperation)
+		return e.manageKubernetesResource(ctx, clientset, dynamicClient, generator, k8sCreateOperation)
 	case "standard.configure":
 		log.Printf("Voluntary bypassing operation %s", e.operation.Name)
 		return nil
@@ -141,16 +145,16 @@ func (e *execution) execute(ctx context.Context, clientset kubernetes.Interface)
 		}
 		return e.uninstallNode(ctx, clientset)
 	case "standard.delete":
-		return e.manageKubernetesResource(ctx, clientset, generator, k8sDeleteOperation)
+		return e.manageKubernetesResource(ctx, clientset, dynamicClient, generator, k8sDeleteOperation)
 	case "org.alien4cloud.management.clustercontrol.scale":
-		return e.manageKubernetesResource(ctx, clientset, generator, k8sScaleOperation)
+		return e.manageKubernetesResource(ctx, clientset, dynamicClient, generator, k8sScaleOperation)
 	default:
 		return errors.Errorf("Unsupported operation %q", e.operation.Name)
 	}
 
 }
 
-func (e *execution) manageKubernetesResource(ctx context.Context, clientset kubernetes.Interface, generator *k8sGenerator, op k8sResourceOperation) error {
+func (e *execution) manageKubernetesResource(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, generator *k8sGenerator, op k8sResourceOperation) error {
 	rSpec, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.nodeName, "resource_spec")
 	if err != nil {
 		return err
@@ -169,12 +173,14 @@ func (e *execution) manageKubernetesResource(ctx context.Context, clientset kube
 		if err != nil {
 			return err
 		}
-		switch rType.RawString() {
-		case "pvc":
+		var resourceType string
+		if rType != nil {
+			resourceType = rType.RawString()
+		}
+		if resourceType == "pvc" {
 			return e.manageSimpleResourcePVC(ctx, clientset, generator, op, rSpec.RawString())
-		default:
-			return errors.Errorf("Unsupported k8s SimpleResource type %q", e.nodeType)
 		}
+		return e.manageSimpleResource(ctx, clientset, dynamicClient, op, resourceType, rSpec.RawString())
 	default:
 		return errors.Errorf("Unsupported k8s resource type %q", e.nodeType)
 	}
@@ -575,7 +581,7 @@ func (e *execution) deployNode(ctx context.Context, clientset kubernetes.Interfa
 		return err
 	}
 
-	e.envInputs, _, err = operations.ResolveInputs(e.kv, e.deploymentID, e.nodeName, e.taskID, e.operation)
+	e.envInputs, _, err = operations.ResolveInputs(ctx, e.kv, e.deploymentID, e.nodeName, e.taskID, e.operation)
 	if err != nil {
 		return err
 	}
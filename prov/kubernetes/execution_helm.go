@@ -0,0 +1,352 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/executil"
+	"github.com/ystia/yorc/v3/helper/stringutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/prov/operations"
+)
+
+// helmReleaseNameMaxLength is the maximum length of a Helm release name
+const helmReleaseNameMaxLength = 53
+
+var helmReleaseNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// helmReleaseStatus is the part of the output of the helm status command used to set the node attributes
+type helmReleaseStatus struct {
+	Info struct {
+		Status string `json:"status"`
+		Notes  string `json:"notes"`
+	} `json:"info"`
+}
+
+// executeHelmOperation installs, upgrades or uninstalls the Helm release of a node
+// in the namespace of the deployment
+func (e *execution) executeHelmOperation(ctx context.Context, clientset kubernetes.Interface) error {
+	operationName := strings.TrimPrefix(strings.ToLower(e.operation.Name), "tosca.interfaces.node.lifecycle.")
+	switch operationName {
+	case "standard.create", "standard.configure", "standard.delete":
+	default:
+		log.Printf("Voluntary bypassing operation %s", e.operation.Name)
+		return nil
+	}
+
+	releaseName, err := e.getHelmReleaseName()
+	if err != nil {
+		return err
+	}
+	namespace, err := defaultNamespace(e.deploymentID)
+	if err != nil {
+		return err
+	}
+
+	if operationName == "standard.delete" {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Uninstalling Helm release %s", releaseName)
+		_, err = e.runHelm(ctx, "uninstall", releaseName, "--namespace", namespace)
+		if isHelmReleaseNotFound(err) {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, e.deploymentID).Registerf("Helm release %s does not exist", releaseName)
+			return nil
+		}
+		return err
+	}
+
+	err = createNamespaceIfMissing(e.deploymentID, namespace, clientset)
+	if err != nil {
+		return err
+	}
+
+	chartArgs, cleanupChart, err := e.getHelmChartArgs(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanupChart()
+	valuesFile, err := e.writeHelmValues(ctx)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(valuesFile)
+
+	var args []string
+	if operationName == "standard.create" {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Installing Helm release %s", releaseName)
+		args = []string{"install", releaseName}
+	} else {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Upgrading Helm release %s", releaseName)
+		args = []string{"upgrade", releaseName}
+	}
+	args = append(args, chartArgs...)
+	args = append(args, "--namespace", namespace, "--values", valuesFile, "--wait")
+	if operationName == "standard.configure" {
+		args = append(args, "--reuse-values")
+	}
+	if _, err = e.runHelm(ctx, args...); err != nil {
+		return err
+	}
+	return e.setHelmReleaseAttributes(ctx, releaseName, namespace)
+}
+
+// getHelmReleaseName returns the release_name property of the node if any or else
+// a name built from the deployment ID and the node name
+func (e *execution) getHelmReleaseName() (string, error) {
+	releaseName, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.nodeName, "release_name")
+	if err != nil {
+		return "", err
+	}
+	if releaseName != nil && releaseName.RawString() != "" {
+		return releaseName.RawString(), nil
+	}
+	return defaultHelmReleaseName(e.deploymentID, e.nodeName), nil
+}
+
+func defaultHelmReleaseName(deploymentID, nodeName string) string {
+	name := helmReleaseNameInvalidChars.ReplaceAllString(strings.ToLower(deploymentID+"-"+nodeName), "-")
+	if len(name) > helmReleaseNameMaxLength {
+		name = name[:helmReleaseNameMaxLength]
+	}
+	return strings.Trim(name, "-")
+}
+
+// getHelmChartArgs returns the helm arguments referencing the chart of the operation implementation artifact
+// and a function cleaning up files created to access the chart.
+//
+// The artifact is either a chart archive or directory in the CSAR or, if the artifact has a repository,
+// a chart name in this repository. Credentials of a repository are never passed on the command line:
+// the repository is added to a temporary repositories configuration with the password read from stdin.
+func (e *execution) getHelmChartArgs(ctx context.Context) ([]string, func(), error) {
+	noop := func() {}
+	repoName, err := deployments.GetOperationImplementationRepository(e.kv, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.nodeType, e.operation.Name)
+	if err != nil {
+		return nil, noop, err
+	}
+	if repoName == "" {
+		chartPath, err := deployments.GetOperationImplementationFileWithRelativePath(e.kv, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.nodeType, e.operation.Name)
+		if err != nil {
+			return nil, noop, err
+		}
+		chartPath, err = filepath.Abs(filepath.Join(e.cfg.WorkingDirectory, "deployments", e.deploymentID, "overlay", chartPath))
+		return []string{chartPath}, noop, errors.Wrap(err, "Failed to get Helm chart path")
+	}
+
+	chart, err := deployments.GetOperationImplementationFile(e.kv, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.nodeType, e.operation.Name)
+	if err != nil {
+		return nil, noop, err
+	}
+	repoURL, err := deployments.GetRepositoryURLFromName(e.kv, e.deploymentID, repoName)
+	if err != nil {
+		return nil, noop, err
+	}
+	if tokenType, _ := deployments.GetRepositoryTokenTypeFromName(e.kv, e.deploymentID, repoName); tokenType != "password" {
+		return []string{chart, "--repo", repoURL}, noop, nil
+	}
+	token, user, err := deployments.GetRepositoryTokenUserFromName(e.kv, e.deploymentID, repoName)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	// The temporary directory is only accessible to the Yorc user
+	repoDir, err := ioutil.TempDir("", "yorc-helm-repo-")
+	if err != nil {
+		return nil, noop, errors.Wrap(err, "Failed to create Helm repositories configuration")
+	}
+	cleanup := func() { os.RemoveAll(repoDir) }
+	repoArgs := []string{
+		"--repository-config", filepath.Join(repoDir, "repositories.yaml"),
+		"--repository-cache", filepath.Join(repoDir, "cache"),
+	}
+	repoAlias := strings.Trim(helmReleaseNameInvalidChars.ReplaceAllString(strings.ToLower(repoName), "-"), "-")
+	if repoAlias == "" {
+		repoAlias = "repo"
+	}
+	addArgs := append([]string{"repo", "add", repoAlias, repoURL, "--username", user, "--password-stdin"}, repoArgs...)
+	if _, err = e.runHelmWithInput(ctx, strings.NewReader(token), addArgs...); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	return append([]string{repoAlias + "/" + chart}, repoArgs...), cleanup, nil
+}
+
+// writeHelmValues writes the operation inputs as chart values in a temporary file and returns its path
+func (e *execution) writeHelmValues(ctx context.Context) (string, error) {
+	inputs, _, err := operations.ResolveInputs(ctx, e.kv, e.deploymentID, e.nodeName, e.taskID, e.operation)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(helmValues(inputs))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate Helm values")
+	}
+	f, err := ioutil.TempFile("", "yorc-helm-values-")
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate Helm values")
+	}
+	defer f.Close()
+	_, err = f.Write(b)
+	return f.Name(), errors.Wrap(err, "Failed to generate Helm values")
+}
+
+// helmValues maps operation inputs to chart values.
+//
+// Dots in input names define nested values ("image.tag").
+func helmValues(inputs []*operations.EnvInput) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, input := range inputs {
+		keys := strings.Split(input.Name, ".")
+		current := values
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := current[key].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[key] = next
+			}
+			current = next
+		}
+		lastKey := keys[len(keys)-1]
+		if _, ok := current[lastKey]; ok {
+			// Inputs are resolved for each instance, keep the first value
+			continue
+		}
+		current[lastKey] = helmValue(input.Value)
+	}
+	return values
+}
+
+// helmValue decodes JSON lists and maps, booleans and integers so that they keep their type.
+// Other values, like floating point numbers that are often versions, are kept as strings.
+func helmValue(value string) interface{} {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if b, err := strconv.ParseBool(value); err == nil && (value == "true" || value == "false") {
+		return b
+	}
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// setHelmReleaseAttributes exposes the release name, status and notes as node attributes
+func (e *execution) setHelmReleaseAttributes(ctx context.Context, releaseName, namespace string) error {
+	out, err := e.runHelm(ctx, "status", releaseName, "--namespace", namespace, "--output", "json")
+	if err != nil {
+		return err
+	}
+	var status helmReleaseStatus
+	if err = json.Unmarshal(out, &status); err != nil {
+		return errors.Wrapf(err, "Failed to parse status of Helm release %s", releaseName)
+	}
+	attributes := map[string]string{
+		"release_name":   releaseName,
+		"release_status": status.Info.Status,
+		"release_notes":  status.Info.Notes,
+	}
+	for name, value := range attributes {
+		err = deployments.SetAttributeForAllInstances(e.kv, e.deploymentID, e.nodeName, name, value)
+		if err != nil {
+			return errors.Wrap(err, "Failed to set attribute")
+		}
+	}
+	return nil
+}
+
+// helmCommandError is returned when a helm command fails, it holds the command error output
+type helmCommandError struct {
+	command string
+	stderr  string
+	err     error
+}
+
+func (e *helmCommandError) Error() string {
+	return fmt.Sprintf("Failed to run helm %s: %v: %s", e.command, e.err, e.stderr)
+}
+
+// isHelmReleaseNotFound returns true if err is the failure of a helm command on a release that doesn't exist
+func isHelmReleaseNotFound(err error) bool {
+	herr, ok := err.(*helmCommandError)
+	return ok && strings.Contains(herr.stderr, "release: not found")
+}
+
+// runHelm runs a helm command against the configured Kubernetes cluster and returns its standard output
+func (e *execution) runHelm(ctx context.Context, args ...string) ([]byte, error) {
+	return e.runHelmWithInput(ctx, nil, args...)
+}
+
+// runHelmWithInput runs a helm command reading the given input against the configured Kubernetes cluster
+// and returns its standard output
+func (e *execution) runHelmWithInput(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	helmCommand := "helm"
+	kubConf := e.cfg.Infrastructures["kubernetes"]
+	if kubConf != nil {
+		if kubConf.GetString("helm_command") != "" {
+			helmCommand = kubConf.GetString("helm_command")
+		}
+		if kubeConfigPathOrContent := kubConf.GetString("kubeconfig"); kubeConfigPathOrContent != "" {
+			kubeConfigPath, wasPath, err := stringutil.GetFilePath(kubeConfigPathOrContent)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to get Kubernetes config file")
+			}
+			if !wasPath {
+				defer os.Remove(kubeConfigPath)
+			}
+			args = append(args, "--kubeconfig", kubeConfigPath)
+		} else if kubeMasterIP := kubConf.GetString("master_url"); kubeMasterIP != "" {
+			args = append(args, "--kube-apiserver", kubeMasterIP)
+		}
+	}
+
+	cmd := executil.Command(ctx, helmCommand, args...)
+	errbuf := events.NewBufferedLogEntryWriter()
+	out := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdin = stdin
+	cmd.Stdout = out
+	cmd.Stderr = io.MultiWriter(errbuf, stderr)
+
+	quit := make(chan bool)
+	defer close(quit)
+
+	// Register log entries via stderr buffer
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RunBufferedRegistration(errbuf, quit)
+
+	if err := cmd.Run(); err != nil {
+		return nil, &helmCommandError{command: args[0], stderr: strings.TrimSpace(stderr.String()), err: err}
+	}
+	if args[0] != "status" && out.Len() > 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).RegisterAsString(out.String())
+	}
+	return out.Bytes(), nil
+}
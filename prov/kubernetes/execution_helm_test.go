@@ -0,0 +1,60 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ystia/yorc/v3/prov/operations"
+)
+
+func TestDefaultHelmReleaseName(t *testing.T) {
+	assert.Equal(t, "my-app-123-mysql-db", defaultHelmReleaseName("My_App-123", "MySQL.DB"))
+	name := defaultHelmReleaseName("a-very-long-deployment-identifier-generated-by-alien4cloud", "Node")
+	assert.Len(t, name, helmReleaseNameMaxLength)
+	assert.Equal(t, "abc", defaultHelmReleaseName("-abc", ""))
+}
+
+func TestIsHelmReleaseNotFound(t *testing.T) {
+	assert.True(t, isHelmReleaseNotFound(&helmCommandError{command: "uninstall", stderr: "Error: uninstall: Release not loaded: my-release: release: not found", err: errors.New("exit status 1")}))
+	assert.False(t, isHelmReleaseNotFound(&helmCommandError{command: "uninstall", stderr: "Error: Kubernetes cluster unreachable", err: errors.New("exit status 1")}))
+	assert.False(t, isHelmReleaseNotFound(errors.New("release: not found")))
+	assert.False(t, isHelmReleaseNotFound(nil))
+}
+
+func TestHelmValues(t *testing.T) {
+	inputs := []*operations.EnvInput{
+		{Name: "replicaCount", Value: "3", InstanceName: "node_0"},
+		{Name: "image.repository", Value: "nginx", InstanceName: "node_0"},
+		{Name: "image.tag", Value: "1.15", InstanceName: "node_0"},
+		{Name: "ingress.enabled", Value: "true", InstanceName: "node_0"},
+		{Name: "ingress.hosts", Value: `["a.example.com", "b.example.com"]`, InstanceName: "node_0"},
+		{Name: "replicaCount", Value: "5", InstanceName: "node_1"},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"replicaCount": int64(3),
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.15",
+		},
+		"ingress": map[string]interface{}{
+			"enabled": true,
+			"hosts":   []interface{}{"a.example.com", "b.example.com"},
+		},
+	}, helmValues(inputs))
+}
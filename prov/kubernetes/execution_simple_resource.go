@@ -0,0 +1,259 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/ystia/yorc/v3/events"
+)
+
+// defaultSimpleResourceTimeout is the default maximum duration to wait for a simple resource to be ready or deleted
+const defaultSimpleResourceTimeout = 5 * time.Minute
+
+// simpleResourceKinds maps SimpleResource resource types to the kind used when the
+// resource_spec doesn't define its apiVersion and kind
+var simpleResourceKinds = map[string]schema.GroupVersionKind{
+	"configmap":   {Version: "v1", Kind: "ConfigMap"},
+	"secret":      {Version: "v1", Kind: "Secret"},
+	"ingress":     {Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+	"statefulset": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"daemonset":   {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	"cronjob":     {Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+}
+
+// Manage kubernetes resources of any kind, including custom resources, using the dynamic client
+func (e *execution) manageSimpleResource(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, operationType k8sResourceOperation, resourceType, rSpec string) error {
+	if rSpec == "" {
+		return errors.Errorf("Missing mandatory resource_spec property for node %s", e.nodeName)
+	}
+	obj, err := parseSimpleResource(resourceType, rSpec)
+	if err != nil {
+		return err
+	}
+	mapping, err := getRESTMapping(clientset, obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+
+	kind := obj.GetKind()
+	name := obj.GetName()
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	var namespace string
+	var nsProvided bool
+	if namespaced {
+		namespace, nsProvided = getNamespace(e.deploymentID, metav1.ObjectMeta{Namespace: obj.GetNamespace()})
+		obj.SetNamespace(namespace)
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	switch operationType {
+	case k8sCreateOperation:
+		if namespaced && !nsProvided {
+			err = createNamespaceIfMissing(e.deploymentID, namespace, clientset)
+			if err != nil {
+				return err
+			}
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("k8s Namespace %s created", namespace)
+		}
+		existing, err := resourceClient.Get(name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			obj.SetResourceVersion(existing.GetResourceVersion())
+			if _, err = resourceClient.Update(obj); err != nil {
+				return errors.Wrapf(err, "Failed to update k8s %s %s", kind, name)
+			}
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("k8s %s %s updated", kind, name)
+		case k8serrors.IsNotFound(err):
+			if _, err = resourceClient.Create(obj); err != nil {
+				return errors.Wrapf(err, "Failed to create k8s %s %s", kind, name)
+			}
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("k8s %s %s created", kind, name)
+		default:
+			return errors.Wrapf(err, "Failed to get k8s %s %s", kind, name)
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, e.getSimpleResourceTimeout())
+		defer cancel()
+		err = waitForSimpleResourceReadiness(waitCtx, resourceClient, name)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to wait for k8s %s %s readiness", kind, name)
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("k8s %s %s is ready", kind, name)
+
+	case k8sDeleteOperation:
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Deleting k8s %s %s", kind, name)
+		deleteForeground := metav1.DeletePropagationForeground
+		err = resourceClient.Delete(name, &metav1.DeleteOptions{PropagationPolicy: &deleteForeground})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, e.deploymentID).Registerf("k8s %s %s does not exist", kind, name)
+				return nil
+			}
+			return errors.Wrapf(err, "Failed to delete k8s %s %s", kind, name)
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, e.getSimpleResourceTimeout())
+		defer cancel()
+		err = waitForSimpleResourceDeletion(waitCtx, resourceClient, name)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to wait for k8s %s %s deletion", kind, name)
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("k8s %s %s deleted!", kind, name)
+	default:
+		return errors.Errorf("Unsupported operation on k8s SimpleResource %s", kind)
+	}
+	return nil
+}
+
+// parseSimpleResource parses a resource_spec, its apiVersion and kind default to the ones
+// associated to the resource type if any
+func parseSimpleResource(resourceType, rSpec string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal([]byte(rSpec), &obj.Object); err != nil {
+		return nil, errors.Errorf("The resource-spec JSON unmarshaling failed: %s", err)
+	}
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		gvk, ok := simpleResourceKinds[strings.ToLower(resourceType)]
+		if !ok {
+			return nil, errors.Errorf("Missing apiVersion or kind in resource_spec of k8s SimpleResource type %q", resourceType)
+		}
+		if obj.GetAPIVersion() == "" {
+			obj.SetAPIVersion(gvk.GroupVersion().String())
+		}
+		if obj.GetKind() == "" {
+			obj.SetKind(gvk.Kind)
+		}
+	}
+	if obj.GetName() == "" {
+		return nil, errors.Errorf("Missing mandatory metadata name in resource_spec of k8s SimpleResource type %q", resourceType)
+	}
+	return obj, nil
+}
+
+// getRESTMapping returns the API resource serving a given kind
+func getRESTMapping(clientset kubernetes.Interface, gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(clientset.Discovery())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to discover k8s API resources")
+	}
+	mapping, err := restmapper.NewDiscoveryRESTMapper(groupResources).RESTMapping(gvk.GroupKind(), gvk.Version)
+	return mapping, errors.Wrapf(err, "Unsupported k8s resource kind %q", gvk.String())
+}
+
+// getSimpleResourceTimeout returns the maximum duration to wait for a simple resource
+// to be ready or deleted, defined by the simple_resource_timeout option of the kubernetes infrastructure
+func (e *execution) getSimpleResourceTimeout() time.Duration {
+	if kubConf := e.cfg.Infrastructures["kubernetes"]; kubConf != nil {
+		if timeout := kubConf.GetDuration("simple_resource_timeout"); timeout > 0 {
+			return timeout
+		}
+	}
+	return defaultSimpleResourceTimeout
+}
+
+// isSimpleResourceReady returns true if the status of a resource reports it as ready.
+// Resources without status are considered as ready.
+func isSimpleResourceReady(obj *unstructured.Unstructured) bool {
+	switch obj.GetKind() {
+	case "StatefulSet", "Deployment", "ReplicaSet":
+		if !isStatusUpToDate(obj, true) {
+			return false
+		}
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return readyReplicas >= replicas
+	case "DaemonSet":
+		if !isStatusUpToDate(obj, true) {
+			return false
+		}
+		desired, found, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		if !found {
+			return false
+		}
+		numberReady, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		return numberReady >= desired
+	}
+
+	if !isStatusUpToDate(obj, false) {
+		return false
+	}
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return true
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condType, _ := condition["type"].(string); condType == "Ready" || condType == "Available" {
+			status, _ := condition["status"].(string)
+			return status == "True"
+		}
+	}
+	return true
+}
+
+// isStatusUpToDate returns true if the status of a resource reflects its latest spec, that is if its
+// observedGeneration reaches the resource generation. Controllers of workloads always report the
+// observed generation so it is required for them, it is optional for other resources.
+func isStatusUpToDate(obj *unstructured.Unstructured, observedGenerationRequired bool) bool {
+	generation := obj.GetGeneration()
+	observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !found {
+		return !observedGenerationRequired || generation == 0
+	}
+	return observedGeneration >= generation
+}
+
+func waitForSimpleResourceReadiness(ctx context.Context, resourceClient dynamic.ResourceInterface, name string) error {
+	return wait.PollUntil(2*time.Second, func() (bool, error) {
+		obj, err := resourceClient.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return isSimpleResourceReady(obj), nil
+	}, ctx.Done())
+}
+
+func waitForSimpleResourceDeletion(ctx context.Context, resourceClient dynamic.ResourceInterface, name string) error {
+	return wait.PollUntil(2*time.Second, func() (bool, error) {
+		_, err := resourceClient.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return false, nil
+	}, ctx.Done())
+}
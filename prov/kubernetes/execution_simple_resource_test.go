@@ -0,0 +1,104 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseSimpleResource(t *testing.T) {
+	obj, err := parseSimpleResource("configmap", `{"metadata": {"name": "my-config"}, "data": {"key": "value"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", obj.GetAPIVersion())
+	assert.Equal(t, "ConfigMap", obj.GetKind())
+	assert.Equal(t, "my-config", obj.GetName())
+
+	obj, err = parseSimpleResource("StatefulSet", `{"metadata": {"name": "db", "namespace": "ns"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "apps/v1", obj.GetAPIVersion())
+	assert.Equal(t, "StatefulSet", obj.GetKind())
+	assert.Equal(t, "ns", obj.GetNamespace())
+
+	// Custom resources define their apiVersion and kind
+	obj, err = parseSimpleResource("crontab", `{"apiVersion": "stable.example.com/v1", "kind": "CronTab", "metadata": {"name": "my-crontab"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "stable.example.com", obj.GroupVersionKind().Group)
+	assert.Equal(t, "CronTab", obj.GetKind())
+
+	_, err = parseSimpleResource("crontab", `{"metadata": {"name": "my-crontab"}}`)
+	assert.Error(t, err, "missing kind of unknown resource type")
+	_, err = parseSimpleResource("secret", `{"metadata": {}}`)
+	assert.Error(t, err, "missing name")
+	_, err = parseSimpleResource("secret", `{"metadata": `)
+	assert.Error(t, err, "invalid JSON")
+}
+
+func TestIsSimpleResourceReady(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want bool
+	}{
+		{"ConfigMapWithoutStatus", map[string]interface{}{"kind": "ConfigMap"}, true},
+		{"StatefulSetNotReady", map[string]interface{}{"kind": "StatefulSet",
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(2)}}, false},
+		{"StatefulSetReady", map[string]interface{}{"kind": "StatefulSet",
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(3)}}, true},
+		{"DaemonSetNotScheduled", map[string]interface{}{"kind": "DaemonSet"}, false},
+		{"DaemonSetReady", map[string]interface{}{"kind": "DaemonSet",
+			"status": map[string]interface{}{"desiredNumberScheduled": int64(2), "numberReady": int64(2)}}, true},
+		{"CustomResourceNotReady", map[string]interface{}{"kind": "CronTab",
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"}}}}, false},
+		{"CustomResourceReady", map[string]interface{}{"kind": "CronTab",
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Synced", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"}}}}, true},
+		{"StatefulSetGenerationNotObserved", map[string]interface{}{"kind": "StatefulSet",
+			"metadata": map[string]interface{}{"generation": int64(2)},
+			"spec":     map[string]interface{}{"replicas": int64(3)},
+			"status":   map[string]interface{}{"readyReplicas": int64(3)}}, false},
+		{"StatefulSetOutdatedStatus", map[string]interface{}{"kind": "StatefulSet",
+			"metadata": map[string]interface{}{"generation": int64(2)},
+			"spec":     map[string]interface{}{"replicas": int64(3)},
+			"status":   map[string]interface{}{"readyReplicas": int64(3), "observedGeneration": int64(1)}}, false},
+		{"StatefulSetUpToDateStatus", map[string]interface{}{"kind": "StatefulSet",
+			"metadata": map[string]interface{}{"generation": int64(2)},
+			"spec":     map[string]interface{}{"replicas": int64(3)},
+			"status":   map[string]interface{}{"readyReplicas": int64(3), "observedGeneration": int64(2)}}, true},
+		{"DaemonSetOutdatedStatus", map[string]interface{}{"kind": "DaemonSet",
+			"metadata": map[string]interface{}{"generation": int64(3)},
+			"status":   map[string]interface{}{"desiredNumberScheduled": int64(2), "numberReady": int64(2), "observedGeneration": int64(2)}}, false},
+		{"CustomResourceOutdatedStatus", map[string]interface{}{"kind": "CronTab",
+			"metadata": map[string]interface{}{"generation": int64(2)},
+			"status": map[string]interface{}{"observedGeneration": int64(1), "conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"}}}}, false},
+		{"CustomResourceWithoutObservedGeneration", map[string]interface{}{"kind": "CronTab",
+			"metadata": map[string]interface{}{"generation": int64(2)},
+			"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isSimpleResourceReady(&unstructured.Unstructured{Object: tt.obj}))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -34,7 +35,8 @@ import (
 )
 
 type defaultExecutor struct {
-	clientset kubernetes.Interface
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
 }
 
 func getExecution(conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) (*execution, error) {
@@ -69,17 +71,23 @@ func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configu
 		return err
 	}
 
-	if e.clientset == nil {
-		e.clientset, err = initClientSet(conf)
+	if e.clientset == nil || e.dynamicClient == nil {
+		e.clientset, e.dynamicClient, err = initClients(conf)
 		if err != nil {
 			return err
 		}
 	}
 
-	return exec.execute(ctx, e.clientset)
+	return exec.execute(ctx, e.clientset, e.dynamicClient)
 }
 
 func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
+	clientset, _, err := initClients(cfg)
+	return clientset, err
+}
+
+// initClients returns a typed clientset and a dynamic client used to manage resources of arbitrary kinds
+func initClients(cfg config.Configuration) (*kubernetes.Clientset, dynamic.Interface, error) {
 	kubConf := cfg.Infrastructures["kubernetes"]
 
 	var conf *rest.Config
@@ -99,7 +107,7 @@ func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
 		log.Debugf("No Kubernetes cluster specified in configuration, attempting to authenticate inside the cluster")
 		conf, err = rest.InClusterConfig()
 		if err != nil {
-			return nil, errors.Wrap(err, "Failed to build kubernetes InClusterConfig")
+			return nil, nil, errors.Wrap(err, "Failed to build kubernetes InClusterConfig")
 		}
 	} else {
 
@@ -110,7 +118,7 @@ func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
 		var wasPath bool
 		if kubeConfigPathOrContent != "" {
 			if kubeConfigPath, wasPath, err = stringutil.GetFilePath(kubeConfigPathOrContent); err != nil {
-				return nil, errors.Wrap(err, "Failed to get Kubernetes config file")
+				return nil, nil, errors.Wrap(err, "Failed to get Kubernetes config file")
 			}
 			if !wasPath {
 				defer os.Remove(kubeConfigPath)
@@ -131,7 +139,7 @@ func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
 
 			applicationCredsPath, wasPath, err := stringutil.GetFilePath(applicationCredsPathOrContent)
 			if err != nil {
-				return nil, errors.Wrap(err, "Failed to get application credentials file")
+				return nil, nil, errors.Wrap(err, "Failed to get application credentials file")
 			}
 			if !wasPath {
 				defer os.Remove(applicationCredsPath)
@@ -143,7 +151,7 @@ func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
 
 		conf, err = clientcmd.BuildConfigFromFlags(kubeMasterIP, kubeConfigPath)
 		if err != nil {
-			return nil, errors.Wrap(err, "Failed to build kubernetes config")
+			return nil, nil, errors.Wrap(err, "Failed to build kubernetes config")
 		}
 
 		if kubeConfigPath == "" {
@@ -160,5 +168,9 @@ func initClientSet(cfg config.Configuration) (*kubernetes.Clientset, error) {
 	}
 
 	clientset, err := kubernetes.NewForConfig(conf)
-	return clientset, errors.Wrap(err, "Failed to create kubernetes clientset from config")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create kubernetes clientset from config")
+	}
+	dynamicClient, err := dynamic.NewForConfig(conf)
+	return clientset, dynamicClient, errors.Wrap(err, "Failed to create kubernetes dynamic client from config")
 }
@@ -21,6 +21,7 @@ import (
 const (
 	kubernetesArtifactImplementation           = "tosca.artifacts.Deployment.Image.Container.Docker.Kubernetes"
 	kubernetesDeploymentArtifactImplementation = "yorc.artifacts.Deployment.Kubernetes"
+	kubernetesHelmChartImplementation          = "yorc.artifacts.Deployment.Kubernetes.HelmChart"
 )
 
 // Default executor is registered to treat kubernetes artifacts deployment
@@ -30,6 +31,7 @@ func init() {
 		[]string{
 			kubernetesArtifactImplementation,
 			kubernetesDeploymentArtifactImplementation,
+			kubernetesHelmChartImplementation,
 		}, &defaultExecutor{}, registry.BuiltinOrigin)
 
 	reg.RegisterActionOperator([]string{"k8s-job-monitoring"}, &actionOperator{}, registry.BuiltinOrigin)
@@ -122,6 +122,7 @@ func (c *Check) run() {
 		case <-ticker.C:
 			status, mess := c.execution.execute(c.timeout)
 			c.updateStatus(status, mess)
+			c.heal(status)
 		}
 	}
 }
@@ -140,22 +141,58 @@ func (c *Check) exist() bool {
 }
 
 func (c *Check) updateStatus(status CheckStatus, message string) {
-	if c.Report.Status != status {
+	now := time.Now()
+	isStatusChange := c.lastResult != status
+	if isStatusChange {
 		// Be sure check isn't currently being removed before check has been stopped
 		if !c.exist() {
 			return
 		}
+		c.lastResult = status
+		c.addToHistory(CheckResult{Timestamp: now, Status: status, Message: message})
+	}
 
-		log.Debugf("Update check status from %q to %q", c.Report.Status.String(), status.String())
-		err := consulutil.StoreConsulKeyAsString(path.Join(consulutil.MonitoringKVPrefix, "reports", c.ID, "status"), status.String())
-		if err != nil {
-			log.Printf("[WARN] TCP check updating status failed for check ID:%q due to error:%+v", c.ID, err)
+	if c.isFlapping(now) {
+		if c.Report.Status != CheckStatusFLAPPING && (isStatusChange || c.exist()) {
+			log.Debugf("Check with ID:%q is flapping", c.ID)
+			c.storeStatus(CheckStatusFLAPPING, message, now)
+			c.notifyFlapping()
 		}
-		c.Report.Status = status
+		// Notifications are suppressed while the check is flapping
+		return
+	}
+
+	if c.Report.Status != status {
+		// A check leaving the flapping state has no status change
+		if !isStatusChange && !c.exist() {
+			return
+		}
+		log.Debugf("Update check status from %q to %q", c.Report.Status.String(), status.String())
+		c.storeStatus(status, message, now)
+		c.publishStatusChange(status, message)
 		c.notify(message)
 	}
 }
 
+func (c *Check) storeStatus(status CheckStatus, message string, now time.Time) {
+	checkReportPath := path.Join(consulutil.MonitoringKVPrefix, "reports", c.ID)
+	err := consulutil.StoreConsulKeyAsString(path.Join(checkReportPath, "status"), status.String())
+	if err != nil {
+		log.Printf("[WARN] TCP check updating status failed for check ID:%q due to error:%+v", c.ID, err)
+	}
+	err = consulutil.StoreConsulKeyAsString(path.Join(checkReportPath, "last_transition"), now.Format(time.RFC3339Nano))
+	if err != nil {
+		log.Printf("[WARN] check updating last transition time failed for check ID:%q due to error:%+v", c.ID, err)
+	}
+	err = consulutil.StoreConsulKeyAsString(path.Join(checkReportPath, "message"), message)
+	if err != nil {
+		log.Printf("[WARN] check updating last message failed for check ID:%q due to error:%+v", c.ID, err)
+	}
+	c.Report.Status = status
+	c.Report.LastTransition = now
+	c.Report.Message = message
+}
+
 func (c *Check) notify(additionalMessage string) {
 	var nodeState tosca.NodeState
 	var eventLevel events.LogLevel
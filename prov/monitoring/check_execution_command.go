@@ -0,0 +1,266 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/helper/sshutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const (
+	// commandLocationTarget means that the command is run over SSH on the host of the monitored instance
+	commandLocationTarget = "target"
+	// commandLocationOrchestrator means that the command is run locally on the Yorc server
+	commandLocationOrchestrator = "orchestrator"
+)
+
+// localCommandPath is the PATH of commands run locally, they do not inherit the Yorc server environment
+const localCommandPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+type commandCheckExecution struct {
+	command string
+	env     []string
+	// sshClient is nil when the command is run locally
+	sshClient *sshutil.SSHClient
+}
+
+func newCommandCheckExecution(command string, env []string, sshClient *sshutil.SSHClient) *commandCheckExecution {
+	return &commandCheckExecution{
+		command:   command,
+		env:       env,
+		sshClient: sshClient,
+	}
+}
+
+// exitCodeToCheckStatus maps a command exit code to a check status using the Nagios plugins convention.
+// Unknown exit codes are considered as critical.
+func exitCodeToCheckStatus(exitCode int) CheckStatus {
+	switch exitCode {
+	case 0:
+		return CheckStatusPASSING
+	case 1:
+		return CheckStatusWARNING
+	default:
+		return CheckStatusCRITICAL
+	}
+}
+
+func (ce *commandCheckExecution) execute(timeout time.Duration) (CheckStatus, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var output string
+	var exitCode int
+	var err error
+	if ce.sshClient != nil {
+		output, exitCode, err = ce.executeOnTarget(ctx)
+	} else {
+		output, exitCode, err = ce.executeLocally(ctx)
+	}
+	if err != nil {
+		log.Debugf("[WARN] check command execution failed for command:%q due to error:%v", ce.command, err)
+		return CheckStatusCRITICAL, fmt.Sprintf("[WARN] check command execution failed for command:%q due to error:%v", ce.command, err)
+	}
+
+	status := exitCodeToCheckStatus(exitCode)
+	if status != CheckStatusPASSING {
+		log.Debugf("[WARN] check command execution failed for command:%q with exit code:%d", ce.command, exitCode)
+		return status, fmt.Sprintf("[WARN] check command execution failed for command:%q with exit code:%d and output:%q", ce.command, exitCode, output)
+	}
+	return status, ""
+}
+
+func (ce *commandCheckExecution) executeLocally(ctx context.Context) (string, int, error) {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", ce.command)
+	cmd.Env = append([]string{localCommandPath}, ce.env...)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if ctx.Err() != nil {
+		return output, 0, errors.Wrap(ctx.Err(), "command did not complete in time")
+	}
+	if err != nil {
+		if exiterr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exiterr.Sys().(syscall.WaitStatus); ok {
+				return output, status.ExitStatus(), nil
+			}
+		}
+		return output, 0, err
+	}
+	return output, 0, nil
+}
+
+func (ce *commandCheckExecution) executeOnTarget(ctx context.Context) (string, int, error) {
+	sw, err := ce.sshClient.GetSessionWrapper()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(&stdout, sw.Stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(&stderr, sw.Stderr)
+	}()
+
+	err = sw.RunCommand(ctx, ce.remoteCommand())
+	wg.Wait()
+	output := strings.TrimSpace(stdout.String() + stderr.String())
+	if ctx.Err() != nil {
+		return output, 0, errors.Wrap(ctx.Err(), "command did not complete in time")
+	}
+	if err != nil {
+		if exitErr, ok := errors.Cause(err).(*ssh.ExitError); ok {
+			return output, exitErr.ExitStatus(), nil
+		}
+		return output, 0, err
+	}
+	return output, 0, nil
+}
+
+// remoteCommand returns the command to run over SSH prefixed by its environment variables
+// as SSH servers generally do not accept to set environment variables
+func (ce *commandCheckExecution) remoteCommand() string {
+	if len(ce.env) == 0 {
+		return ce.command
+	}
+	exports := make([]string, len(ce.env))
+	for i, e := range ce.env {
+		kv := strings.SplitN(e, "=", 2)
+		exports[i] = fmt.Sprintf("export %s='%s';", kv[0], strings.Replace(kv[1], "'", `'\''`, -1))
+	}
+	return strings.Join(exports, " ") + " " + ce.command
+}
+
+// newSSHClientForInstance returns an SSH client allowing to connect to the given instance of a node
+// using the credentials of its endpoint capability (including jump hosts if any)
+func newSSHClientForInstance(kv *api.KV, deploymentID, hostNode, instance string) (*sshutil.SSHClient, error) {
+	ipAddress, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, instance, "endpoint", "ip_address")
+	if err != nil {
+		return nil, err
+	}
+	if ipAddress == nil || ipAddress.RawString() == "" {
+		return nil, errors.Errorf("No endpoint ip_address has been found for node name:%q, instance:%q with deploymentID:%q", hostNode, instance, deploymentID)
+	}
+	client := &sshutil.SSHClient{
+		Host: config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.ip_address", ipAddress.RawString()).(string),
+		Port: 22,
+	}
+
+	port, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, instance, "endpoint", "port")
+	if err != nil {
+		return nil, err
+	}
+	if port != nil && port.RawString() != "" {
+		client.Port, err = strconv.Atoi(port.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to convert port value:%q to int", port)
+		}
+	}
+
+	client.Config, err = getSSHConfig(kv, deploymentID, hostNode, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	jumpHosts, err := deployments.GetInstanceEndpointJumpHosts(kv, deploymentID, hostNode, instance, client.Config.User)
+	if err != nil {
+		return nil, err
+	}
+	for _, jh := range jumpHosts {
+		jhConfig := &ssh.ClientConfig{
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			User:            jh.User,
+		}
+		if jh.PrivateKey != "" {
+			keyAuth, err := sshutil.ReadPrivateKey(jh.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			jhConfig.Auth = append(jhConfig.Auth, keyAuth)
+		}
+		if jh.Password != "" {
+			jhConfig.Auth = append(jhConfig.Auth, ssh.Password(jh.Password))
+		}
+		if len(jhConfig.Auth) == 0 {
+			return nil, errors.Errorf("No private key or password found for jump host %q in endpoint credentials of node name:%q, instance:%q with deploymentID:%q", jh.Host, hostNode, instance, deploymentID)
+		}
+		client.JumpHosts = append(client.JumpHosts, sshutil.JumpHost{Config: jhConfig, Host: jh.Host, Port: jh.Port})
+	}
+	return client, nil
+}
+
+// getSSHConfig returns an SSH client configuration built from the credentials of the endpoint
+// capability of a node instance
+func getSSHConfig(kv *api.KV, deploymentID, hostNode, instance string) (*ssh.ClientConfig, error) {
+	conf := &ssh.ClientConfig{
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+	user, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, instance, "endpoint", "credentials", "user")
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && user.RawString() != "" {
+		conf.User = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.user", user.RawString()).(string)
+	}
+	if conf.User == "" {
+		conf.User = "root"
+	}
+
+	privateKey, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, instance, "endpoint", "credentials", "keys", "0")
+	if err != nil {
+		return nil, err
+	}
+	if privateKey != nil && privateKey.RawString() != "" {
+		keyAuth, err := sshutil.ReadPrivateKey(config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.privateKey", privateKey.RawString()).(string))
+		if err != nil {
+			return nil, err
+		}
+		conf.Auth = append(conf.Auth, keyAuth)
+	}
+
+	password, err := deployments.GetInstanceCapabilityAttributeValue(kv, deploymentID, hostNode, instance, "endpoint", "credentials", "token")
+	if err != nil {
+		return nil, err
+	}
+	if password != nil && password.RawString() != "" {
+		conf.Auth = append(conf.Auth, ssh.Password(config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.password", password.RawString()).(string)))
+	}
+	if len(conf.Auth) == 0 {
+		return nil, errors.Errorf("No private key or password found in endpoint credentials of node name:%q, instance:%q with deploymentID:%q", hostNode, instance, deploymentID)
+	}
+	return conf, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandCheckExecutionLocally(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		command string
+		timeout time.Duration
+		want    CheckStatus
+	}{
+		{"Passing", "exit 0", 5 * time.Second, CheckStatusPASSING},
+		{"Warning", "echo 'degraded' && exit 1", 5 * time.Second, CheckStatusWARNING},
+		{"Critical", "exit 2", 5 * time.Second, CheckStatusCRITICAL},
+		{"UnknownExitCode", "exit 3", 5 * time.Second, CheckStatusCRITICAL},
+		{"Timeout", "sleep 5", 500 * time.Millisecond, CheckStatusCRITICAL},
+		{"Environment", `test "$NODE" = "Compute1" && test "$INSTANCE" = "0"`, 5 * time.Second, CheckStatusPASSING},
+		{"MinimalEnvironment", `test -z "$HOME" && test -n "$PATH"`, 5 * time.Second, CheckStatusPASSING},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ce := newCommandCheckExecution(tt.command, []string{"NODE=Compute1", "INSTANCE=0"}, nil)
+			status, mess := ce.execute(tt.timeout)
+			require.Equal(t, tt.want, status, "unexpected status with message %q", mess)
+			if tt.want == CheckStatusPASSING {
+				require.Empty(t, mess)
+			} else {
+				require.NotEmpty(t, mess)
+			}
+		})
+	}
+}
+
+func TestCommandCheckRemoteCommand(t *testing.T) {
+	t.Parallel()
+	ce := newCommandCheckExecution("/opt/app/bin/health", nil, nil)
+	require.Equal(t, "/opt/app/bin/health", ce.remoteCommand())
+
+	ce = newCommandCheckExecution("/opt/app/bin/health", []string{"NODE=Compute1", "IP_ADDRESS=1.2.3.4", "QUOTED=it's"}, nil)
+	require.Equal(t, `export NODE='Compute1'; export IP_ADDRESS='1.2.3.4'; export QUOTED='it'\''s'; /opt/app/bin/health`, ce.remoteCommand())
+}
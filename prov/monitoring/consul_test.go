@@ -29,6 +29,7 @@ func TestRunConsulMonitoringPackageTests(t *testing.T) {
 	cfg := config.Configuration{
 		HTTPAddress: "localhost",
 		ServerID:    "0",
+		Monitoring:  config.Monitoring{OrchestratorCommandChecksAllowed: true},
 	}
 
 	// Register the consul service
@@ -96,6 +97,39 @@ func TestRunConsulMonitoringPackageTests(t *testing.T) {
 		consulutil.DeploymentKVPrefix + "/monitoring5/topology/nodes/Compute1/type":                             []byte("yorc.nodes.openstack.Compute"),
 		consulutil.DeploymentKVPrefix + "/monitoring5/topology/instances/Compute1/0/attributes/ip_address":      []byte("1.2.3.4"),
 		consulutil.DeploymentKVPrefix + "/monitoring5/topology/instances/Compute1/0/attributes/state":           []byte("started"),
+
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/types/tosca.nodes.Root/name":                     []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/types/tosca.nodes.Compute/derived_from":          []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/types/yorc.nodes.Compute/derived_from":           []byte("tosca.nodes.Compute"),
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/types/yorc.nodes.openstack.Compute/derived_from": []byte("yorc.nodes.Compute"),
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/nodes/Compute1/type":                             []byte("yorc.nodes.openstack.Compute"),
+		consulutil.DeploymentKVPrefix + "/monitoring6/topology/instances/Compute1/0/attributes/state":           []byte("started"),
+
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/types/yorc.policies.Healing/derived_from":           []byte("tosca.policies.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/types/yorc.policies.Healing/targets":                []byte("tosca.nodes.Compute,tosca.nodes.SoftwareComponent"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/properties/operation":         []byte("custom.restart"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/properties/failure_threshold": []byte("2"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/properties/cooldown":          []byte("1h"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/properties/max_attempts":      []byte("1"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/targets":                      []byte("Compute1"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/policies/Healing/type":                         []byte("yorc.policies.Healing"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/types/tosca.nodes.Root/name":                   []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/types/tosca.nodes.Compute/derived_from":        []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/nodes/Compute1/type":                           []byte("tosca.nodes.Compute"),
+		consulutil.DeploymentKVPrefix + "/monitoring7/topology/instances/Compute1/0/attributes/state":         []byte("started"),
+
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/types/yorc.policies.monitoring.TCPMonitoring/derived_from": []byte("yorc.policies.Monitoring"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/types/yorc.policies.monitoring.TCPMonitoring/targets":      []byte("tosca.nodes.Compute,tosca.nodes.SoftwareComponent"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/properties/port":               []byte("22"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/properties/time_interval":      []byte("1s"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/properties/flapping_threshold": []byte("3"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/properties/flapping_window":    []byte("1h"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/targets":                       []byte("Compute1"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/policies/TCPMonitoring/type":                          []byte("yorc.policies.monitoring.TCPMonitoring"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/types/tosca.nodes.Root/name":                          []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/types/tosca.nodes.Compute/derived_from":               []byte("tosca.nodes.Root"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/nodes/Compute1/type":                                  []byte("tosca.nodes.Compute"),
+		consulutil.DeploymentKVPrefix + "/monitoring8/topology/instances/Compute1/0/attributes/state":                []byte("started"),
 	})
 
 	t.Run("groupMonitoring", func(t *testing.T) {
@@ -111,5 +145,14 @@ func TestRunConsulMonitoringPackageTests(t *testing.T) {
 		t.Run("testAddAndRemoveCheck", func(t *testing.T) {
 			testAddAndRemoveCheck(t, client)
 		})
+		t.Run("testAddAndRemoveCommandCheck", func(t *testing.T) {
+			testAddAndRemoveCommandCheck(t, client)
+		})
+		t.Run("testHealing", func(t *testing.T) {
+			testHealing(t, client)
+		})
+		t.Run("testCheckFlapping", func(t *testing.T) {
+			testCheckFlapping(t, client)
+		})
 	})
 }
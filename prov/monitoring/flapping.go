@@ -0,0 +1,155 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+)
+
+// checkHistorySize is the maximum number of results kept in a check history
+const checkHistorySize = 50
+
+// CheckResult represents a check result stored in the check history
+//
+// Only results changing the check status are recorded.
+type CheckResult struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Status    CheckStatus `json:"status"`
+	Message   string      `json:"message,omitempty"`
+}
+
+// flappingDetection defines when a check is considered as flapping:
+// the check status changed at least threshold times during window
+type flappingDetection struct {
+	threshold int
+	window    time.Duration
+}
+
+// getFlappingDetection returns the flapping detection settings of the monitoring policy applied to a node
+// or nil if flapping detection is disabled
+func getFlappingDetection(kv *api.KV, deploymentID, nodeName string) (*flappingDetection, error) {
+	isMonitorReq, policyName, err := checkExistingMonitoringPolicy(kv, deploymentID, nodeName)
+	if err != nil || !isMonitorReq {
+		return nil, err
+	}
+
+	fd := &flappingDetection{threshold: 5, window: 10 * time.Minute}
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "flapping_threshold")
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.RawString() != "" {
+		fd.threshold, err = strconv.Atoi(value.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve flapping_threshold as correct integer for monitoring policy:%q", policyName)
+		}
+	}
+	if fd.threshold <= 0 {
+		return nil, nil
+	}
+	if fd.threshold > checkHistorySize {
+		return nil, errors.Errorf("flapping_threshold of monitoring policy:%q can't exceed %d", policyName, checkHistorySize)
+	}
+
+	value, err = deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "flapping_window")
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.RawString() != "" {
+		fd.window, err = time.ParseDuration(value.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve flapping_window as correct duration for monitoring policy:%q", policyName)
+		}
+	}
+	return fd, nil
+}
+
+// ReadCheckHistory returns the stored history of a given check from the oldest to the most recent result
+func ReadCheckHistory(kv *api.KV, checkID string) ([]CheckResult, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.MonitoringKVPrefix, "reports", checkID, "history"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	history := make([]CheckResult, 0)
+	if kvp == nil || len(kvp.Value) == 0 {
+		return history, nil
+	}
+	err = json.Unmarshal(kvp.Value, &history)
+	return history, errors.Wrapf(err, "failed to read history of check ID:%q", checkID)
+}
+
+// addToHistory records a new result in the bounded check history
+func (c *Check) addToHistory(result CheckResult) {
+	c.history = append(c.history, result)
+	if len(c.history) > checkHistorySize {
+		c.history = c.history[len(c.history)-checkHistorySize:]
+	}
+
+	b, err := json.Marshal(c.history)
+	if err != nil {
+		log.Printf("[WARN] failed to marshal history for check ID:%q due to error:%+v", c.ID, err)
+	} else {
+		err = consulutil.StoreConsulKey(path.Join(consulutil.MonitoringKVPrefix, "reports", c.ID, "history"), b)
+		if err != nil {
+			log.Printf("[WARN] check updating history failed for check ID:%q due to error:%+v", c.ID, err)
+		}
+	}
+}
+
+// publishStatusChange publishes a check event for a status change which is not suppressed
+func (c *Check) publishStatusChange(status CheckStatus, message string) {
+	_, err := events.PublishCheckStatusChange(c.ctx, c.Report.DeploymentID, c.Report.NodeName, c.Report.Instance, status.String(), message)
+	if err != nil {
+		log.Printf("[WARN] failed to publish check event for check ID:%q due to error:%+v", c.ID, err)
+	}
+}
+
+// isFlapping returns true if the number of status changes recorded in the flapping window
+// reaches the flapping threshold
+func (c *Check) isFlapping(now time.Time) bool {
+	if c.flapping == nil {
+		return false
+	}
+	var transitions int
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if now.Sub(c.history[i].Timestamp) > c.flapping.window {
+			break
+		}
+		transitions++
+	}
+	return transitions >= c.flapping.threshold
+}
+
+// notifyFlapping notifies once that a check is flapping
+//
+// The instance state is left unchanged as long as the check is flapping.
+func (c *Check) notifyFlapping() {
+	events.WithContextOptionalFields(c.ctx).NewLogEntry(events.LogLevelWARN, c.Report.DeploymentID).Registerf(
+		"Monitoring Check is flapping for node (%s-%s): at least %d status changes in the last %s, further notifications are suppressed until it stabilizes",
+		c.Report.NodeName, c.Report.Instance, c.flapping.threshold, c.flapping.window)
+	c.publishStatusChange(CheckStatusFLAPPING, fmt.Sprintf("at least %d status changes in the last %s", c.flapping.threshold, c.flapping.window))
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+func testCheckFlapping(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	dep := "monitoring8"
+	node := "Compute1"
+	instance := "0"
+
+	fd, err := getFlappingDetection(kv, dep, node)
+	require.NoError(t, err)
+	require.NotNil(t, fd, "flapping detection is expected")
+	require.Equal(t, 3, fd.threshold)
+	require.Equal(t, time.Hour, fd.window)
+
+	check := NewCheck(dep, node, instance)
+	check.flapping = fd
+	check.ctx = context.Background()
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.MonitoringKVPrefix, "reports", check.ID, "status"), Value: []byte(CheckStatusINITIAL.String())}, nil)
+	require.NoError(t, err)
+
+	check.updateStatus(CheckStatusPASSING, "")
+	check.updateStatus(CheckStatusCRITICAL, "connection refused")
+	require.Equal(t, CheckStatusCRITICAL, check.Report.Status)
+	state, err := deployments.GetInstanceState(kv, dep, node, instance)
+	require.NoError(t, err)
+	require.Equal(t, tosca.NodeStateError, state)
+
+	// Third status change in the window: the check is flapping and the instance state is left unchanged
+	check.updateStatus(CheckStatusPASSING, "")
+	require.Equal(t, CheckStatusFLAPPING, check.Report.Status)
+	check.updateStatus(CheckStatusCRITICAL, "connection refused")
+	require.Equal(t, CheckStatusFLAPPING, check.Report.Status)
+	state, err = deployments.GetInstanceState(kv, dep, node, instance)
+	require.NoError(t, err)
+	require.Equal(t, tosca.NodeStateError, state)
+
+	history, err := ReadCheckHistory(kv, check.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	require.Equal(t, CheckStatusPASSING, history[0].Status)
+	require.Equal(t, CheckStatusCRITICAL, history[3].Status)
+	require.Equal(t, "connection refused", history[3].Message)
+
+	// Once results are out of the window, the check leaves the flapping state
+	for i := range check.history {
+		check.history[i].Timestamp = check.history[i].Timestamp.Add(-2 * time.Hour)
+	}
+	check.updateStatus(CheckStatusCRITICAL, "connection refused")
+	require.Equal(t, CheckStatusCRITICAL, check.Report.Status)
+
+	// Only transitions which are not suppressed and the flapping detection are published as check events
+	rawEvents, _, err := events.StatusEvents(kv, dep, 0, 0)
+	require.NoError(t, err)
+	var checkEvents []string
+	for _, rawEvent := range rawEvents {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal(rawEvent, &event))
+		if event[events.EType.String()] == events.StatusChangeTypeCheck.String() {
+			checkEvents = append(checkEvents, event[events.EStatus.String()].(string))
+		}
+	}
+	require.ElementsMatch(t, []string{"passing", "critical", "flapping", "critical"}, checkEvents)
+
+	// History is bounded
+	for i := 0; i < checkHistorySize; i++ {
+		check.addToHistory(CheckResult{Timestamp: time.Now(), Status: CheckStatusPASSING})
+	}
+	history, err = ReadCheckHistory(kv, check.ID)
+	require.NoError(t, err)
+	require.Len(t, history, checkHistorySize)
+}
@@ -0,0 +1,255 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tasks/collector"
+)
+
+const healingPolicyType = "yorc.policies.Healing"
+
+// healingPolicy defines the action to take when a monitored instance repeatedly fails its check
+type healingPolicy struct {
+	name string
+	// workflow is the name of a custom workflow to launch on the failed instance
+	workflow string
+	// interfaceName and operationName define a custom operation to launch on the failed instance
+	// they are used only if no workflow is defined
+	interfaceName    string
+	operationName    string
+	failureThreshold int
+	cooldown         time.Duration
+	maxAttempts      int
+}
+
+// getHealingPolicy returns the healing policy applied to a node or nil if there is none
+func getHealingPolicy(kv *api.KV, deploymentID, nodeName string) (*healingPolicy, error) {
+	policies, err := deployments.GetPoliciesForTypeAndNode(kv, deploymentID, healingPolicyType, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	if len(policies) > 1 {
+		return nil, errors.Errorf("Found more than one healing policy to apply to node name:%q. No healing policy will be applied", nodeName)
+	}
+
+	hp := &healingPolicy{name: policies[0]}
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, hp.name, "workflow")
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		hp.workflow = value.RawString()
+	}
+	value, err = deployments.GetPolicyPropertyValue(kv, deploymentID, hp.name, "operation")
+	if err != nil {
+		return nil, err
+	}
+	if hp.workflow == "" {
+		if value == nil || value.RawString() == "" {
+			return nil, errors.Errorf("One of workflow or operation is required for healing policy:%q", hp.name)
+		}
+		i := strings.LastIndex(value.RawString(), ".")
+		if i <= 0 || i == len(value.RawString())-1 {
+			return nil, errors.Errorf("Operation %q of healing policy:%q should be defined as <interface_name>.<operation_name>", value.RawString(), hp.name)
+		}
+		hp.interfaceName = strings.ToLower(value.RawString()[:i])
+		hp.operationName = value.RawString()[i+1:]
+	}
+
+	hp.failureThreshold, err = getHealingPolicyIntProperty(kv, deploymentID, hp.name, "failure_threshold", 3)
+	if err != nil {
+		return nil, err
+	}
+	hp.maxAttempts, err = getHealingPolicyIntProperty(kv, deploymentID, hp.name, "max_attempts", 3)
+	if err != nil {
+		return nil, err
+	}
+	hp.cooldown = 10 * time.Minute
+	value, err = deployments.GetPolicyPropertyValue(kv, deploymentID, hp.name, "cooldown")
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.RawString() != "" {
+		hp.cooldown, err = time.ParseDuration(value.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve cooldown as correct duration for healing policy:%q", hp.name)
+		}
+	}
+	return hp, nil
+}
+
+func getHealingPolicyIntProperty(kv *api.KV, deploymentID, policyName, propertyName string, defaultValue int) (int, error) {
+	value, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, propertyName)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil || value.RawString() == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value.RawString())
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to retrieve %s as correct integer for healing policy:%q", propertyName, policyName)
+	}
+	return i, nil
+}
+
+// heal tracks consecutive critical results of a check and launches the healing action
+// of the check healing policy when the failure threshold is reached.
+func (c *Check) heal(status CheckStatus) {
+	if c.healing == nil {
+		return
+	}
+	healingPath := path.Join(consulutil.MonitoringKVPrefix, "reports", c.ID, "healing")
+	kv := defaultMonManager.cc.KV()
+
+	if status != CheckStatusCRITICAL {
+		c.consecutiveCriticals = 0
+		if status == CheckStatusPASSING {
+			c.resetHealingAttempts(kv, healingPath)
+		}
+		return
+	}
+
+	c.consecutiveCriticals++
+	if c.consecutiveCriticals < c.healing.failureThreshold {
+		return
+	}
+
+	attempts, lastAttempt, err := getHealingState(kv, healingPath)
+	if err != nil {
+		log.Printf("[WARN] Failed to retrieve healing state for check ID:%q due to error:%+v", c.ID, err)
+		return
+	}
+	if attempts >= c.healing.maxAttempts {
+		if !c.healingExhausted {
+			c.healingExhausted = true
+			events.WithContextOptionalFields(c.ctx).NewLogEntry(events.LogLevelERROR, c.Report.DeploymentID).
+				Registerf("Healing policy %q reached its maximum of %d attempts for node (%s-%s), no more healing will be attempted", c.healing.name, c.healing.maxAttempts, c.Report.NodeName, c.Report.Instance)
+		}
+		return
+	}
+	if !lastAttempt.IsZero() && time.Since(lastAttempt) < c.healing.cooldown {
+		return
+	}
+
+	// Be sure check isn't currently being removed before launching an healing task
+	if !c.exist() {
+		return
+	}
+
+	taskID, err := c.launchHealingTask()
+	now := time.Now()
+	if err != nil {
+		// Do not retry before the cooldown to avoid flooding events
+		consulutil.StoreConsulKeyAsString(path.Join(healingPath, "last_attempt"), now.Format(time.RFC3339Nano))
+		c.consecutiveCriticals = 0
+		events.WithContextOptionalFields(c.ctx).NewLogEntry(events.LogLevelWARN, c.Report.DeploymentID).
+			Registerf("Healing policy %q failed to launch its healing action for node (%s-%s) due to: %v", c.healing.name, c.Report.NodeName, c.Report.Instance, err)
+		return
+	}
+
+	attempts++
+	err = consulutil.StoreConsulKeyAsString(path.Join(healingPath, "attempts"), strconv.Itoa(attempts))
+	if err == nil {
+		err = consulutil.StoreConsulKeyAsString(path.Join(healingPath, "last_attempt"), now.Format(time.RFC3339Nano))
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to store healing state for check ID:%q due to error:%+v", c.ID, err)
+	}
+	c.consecutiveCriticals = 0
+	events.WithContextOptionalFields(c.ctx).NewLogEntry(events.LogLevelWARN, c.Report.DeploymentID).
+		Registerf("Healing attempt %d/%d of policy %q for node (%s-%s) launched %s with task ID %q", attempts, c.healing.maxAttempts, c.healing.name, c.Report.NodeName, c.Report.Instance, c.healing.actionName(), taskID)
+}
+
+func (c *Check) launchHealingTask() (string, error) {
+	data := map[string]string{
+		path.Join("nodes", c.Report.NodeName): c.Report.Instance,
+	}
+	taskType := tasks.TaskTypeCustomWorkflow
+	if c.healing.workflow != "" {
+		data["workflowName"] = c.healing.workflow
+		data["continueOnError"] = strconv.FormatBool(false)
+	} else {
+		taskType = tasks.TaskTypeCustomCommand
+		data["interfaceName"] = c.healing.interfaceName
+		data["commandName"] = c.healing.operationName
+	}
+	return collector.NewCollector(defaultMonManager.cc).RegisterTaskWithData(c.Report.DeploymentID, taskType, data)
+}
+
+// resetHealingAttempts resets healing attempts once a check is back to normal
+func (c *Check) resetHealingAttempts(kv *api.KV, healingPath string) {
+	c.healingExhausted = false
+	attempts, _, err := getHealingState(kv, healingPath)
+	if err != nil || attempts == 0 {
+		return
+	}
+	_, err = kv.DeleteTree(healingPath, nil)
+	if err != nil {
+		log.Printf("[WARN] Failed to reset healing state for check ID:%q due to error:%+v", c.ID, err)
+		return
+	}
+	events.WithContextOptionalFields(c.ctx).NewLogEntry(events.LogLevelINFO, c.Report.DeploymentID).
+		Registerf("Node (%s-%s) is back to normal after %d healing attempt(s) of policy %q", c.Report.NodeName, c.Report.Instance, attempts, c.healing.name)
+}
+
+func getHealingState(kv *api.KV, healingPath string) (int, time.Time, error) {
+	var attempts int
+	var lastAttempt time.Time
+	kvp, _, err := kv.Get(path.Join(healingPath, "attempts"), nil)
+	if err != nil {
+		return 0, lastAttempt, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		attempts, err = strconv.Atoi(string(kvp.Value))
+		if err != nil {
+			return 0, lastAttempt, errors.Wrapf(err, "invalid healing attempts value %q", string(kvp.Value))
+		}
+	}
+	kvp, _, err = kv.Get(path.Join(healingPath, "last_attempt"), nil)
+	if err != nil {
+		return 0, lastAttempt, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		lastAttempt, err = time.Parse(time.RFC3339Nano, string(kvp.Value))
+		if err != nil {
+			return 0, lastAttempt, errors.Wrapf(err, "invalid healing last attempt value %q", string(kvp.Value))
+		}
+	}
+	return attempts, lastAttempt, nil
+}
+
+func (hp *healingPolicy) actionName() string {
+	if hp.workflow != "" {
+		return "workflow " + strconv.Quote(hp.workflow)
+	}
+	return "operation " + strconv.Quote(hp.interfaceName+"."+hp.operationName)
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/tasks"
+)
+
+func testHealing(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	dep := "monitoring7"
+	node := "Compute1"
+	instance := "0"
+
+	hp, err := getHealingPolicy(kv, dep, node)
+	require.NoError(t, err)
+	require.NotNil(t, hp, "a healing policy is expected")
+	require.Equal(t, "custom", hp.interfaceName)
+	require.Equal(t, "restart", hp.operationName)
+	require.Equal(t, 2, hp.failureThreshold)
+	require.Equal(t, 1, hp.maxAttempts)
+	require.Equal(t, time.Hour, hp.cooldown)
+
+	check := NewCheck(dep, node, instance)
+	check.healing = hp
+	check.ctx = context.Background()
+	healingPath := path.Join(consulutil.MonitoringKVPrefix, "reports", check.ID, "healing")
+	_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.MonitoringKVPrefix, "reports", check.ID, "status"), Value: []byte(CheckStatusCRITICAL.String())}, nil)
+	require.NoError(t, err)
+
+	// Failure threshold not reached
+	check.heal(CheckStatusCRITICAL)
+	attempts, _, err := getHealingState(kv, healingPath)
+	require.NoError(t, err)
+	require.Equal(t, 0, attempts)
+
+	// Failure threshold reached
+	check.heal(CheckStatusCRITICAL)
+	attempts, lastAttempt, err := getHealingState(kv, healingPath)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempts)
+	require.False(t, lastAttempt.IsZero())
+
+	tasksIDs, err := tasks.GetTasksIdsForTarget(kv, dep)
+	require.NoError(t, err)
+	require.Len(t, tasksIDs, 1, "an healing task is expected")
+	taskType, err := tasks.GetTaskType(kv, tasksIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskTypeCustomCommand, taskType)
+	instances, err := tasks.GetInstances(kv, tasksIDs[0], dep, node)
+	require.NoError(t, err)
+	require.Equal(t, []string{instance}, instances, "only the failed instance should be healed")
+
+	// Max attempts reached no more task
+	check.heal(CheckStatusCRITICAL)
+	check.heal(CheckStatusCRITICAL)
+	tasksIDs, err = tasks.GetTasksIdsForTarget(kv, dep)
+	require.NoError(t, err)
+	require.Len(t, tasksIDs, 1, "no more healing task is expected")
+
+	// Back to normal resets attempts
+	check.heal(CheckStatusPASSING)
+	attempts, lastAttempt, err = getHealingState(kv, healingPath)
+	require.NoError(t, err)
+	require.Equal(t, 0, attempts)
+	require.True(t, lastAttempt.IsZero())
+}
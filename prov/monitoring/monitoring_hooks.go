@@ -37,9 +37,10 @@ func init() {
 }
 
 const (
-	httpMonitoring = "yorc.policies.monitoring.HTTPMonitoring"
-	tcpMonitoring  = "yorc.policies.monitoring.TCPMonitoring"
-	baseMonitoring = "yorc.policies.Monitoring"
+	httpMonitoring    = "yorc.policies.monitoring.HTTPMonitoring"
+	tcpMonitoring     = "yorc.policies.monitoring.TCPMonitoring"
+	commandMonitoring = "yorc.policies.monitoring.CommandMonitoring"
+	baseMonitoring    = "yorc.policies.Monitoring"
 )
 
 func addMonitoringHook(ctx context.Context, cfg config.Configuration, taskID, deploymentID, target string, activity builder.Activity) {
@@ -62,7 +63,7 @@ func addMonitoringHook(ctx context.Context, cfg config.Configuration, taskID, de
 			return
 		}
 
-		err = addMonitoringPolicyForTarget(defaultMonManager.cc.KV(), taskID, deploymentID, target, policyName)
+		err = addMonitoringPolicyForTarget(ctx, defaultMonManager.cc.KV(), taskID, deploymentID, target, policyName)
 		if err != nil {
 			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).
 				Registerf("Failed to add monitoring policy for node name:%q due to: %v", target, err)
@@ -89,7 +90,7 @@ func removeMonitoringHook(ctx context.Context, cfg config.Configuration, taskID,
 			return
 		}
 
-		instances, err := tasks.GetInstances(defaultMonManager.cc.KV(), taskID, deploymentID, target)
+		instances, err := tasks.GetInstancesWithContext(ctx, defaultMonManager.cc.KV(), taskID, deploymentID, target)
 		if err != nil {
 			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).
 				Registerf("Failed to retrieve instances for node name:%q due to: %v", target, err)
@@ -121,7 +122,7 @@ func checkExistingMonitoringPolicy(kv *api.KV, deploymentID, target string) (boo
 	return true, policies[0], nil
 }
 
-func addMonitoringPolicyForTarget(kv *api.KV, taskID, deploymentID, target, policyName string) error {
+func addMonitoringPolicyForTarget(ctx context.Context, kv *api.KV, taskID, deploymentID, target, policyName string) error {
 	log.Debugf("Add monitoring policy:%q for deploymentID:%q, node name:%q", policyName, deploymentID, target)
 	policyType, err := deployments.GetPolicyType(kv, deploymentID, policyName)
 	if err != nil {
@@ -138,29 +139,43 @@ func addMonitoringPolicyForTarget(kv *api.KV, taskID, deploymentID, target, poli
 	if err != nil {
 		return errors.Errorf("Failed to retrieve time_interval as correct duration for monitoring policy:%q due to: %v", policyName, err)
 	}
-	portValue, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "port")
-	if err != nil || portValue == nil || portValue.RawString() == "" {
-		return errors.Errorf("Failed to retrieve port for monitoring policy:%q due to: %v", policyName, err)
-	}
-	port, err := strconv.Atoi(portValue.RawString())
-	if err != nil {
-		return errors.Errorf("Failed to retrieve port as correct integer for monitoring policy:%q due to: %v", policyName, err)
-	}
-	instances, err := tasks.GetInstances(defaultMonManager.cc.KV(), taskID, deploymentID, target)
+	instances, err := tasks.GetInstancesWithContext(ctx, defaultMonManager.cc.KV(), taskID, deploymentID, target)
 	if err != nil {
 		return err
 	}
 
 	switch policyType {
 	case httpMonitoring:
+		port, err := getPolicyPort(kv, deploymentID, policyName)
+		if err != nil {
+			return err
+		}
 		return applyHTTPMonitoringPolicy(kv, policyName, deploymentID, target, timeInterval, port, instances)
 	case tcpMonitoring:
+		port, err := getPolicyPort(kv, deploymentID, policyName)
+		if err != nil {
+			return err
+		}
 		return applyTCPMonitoringPolicy(deploymentID, target, timeInterval, port, instances)
+	case commandMonitoring:
+		return applyCommandMonitoringPolicy(kv, policyName, deploymentID, target, timeInterval, instances)
 	default:
 		return errors.Errorf("Unsupported policy type:%q for policy:%q", policyType, policyName)
 	}
 }
 
+func getPolicyPort(kv *api.KV, deploymentID, policyName string) (int, error) {
+	portValue, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "port")
+	if err != nil || portValue == nil || portValue.RawString() == "" {
+		return 0, errors.Errorf("Failed to retrieve port for monitoring policy:%q due to: %v", policyName, err)
+	}
+	port, err := strconv.Atoi(portValue.RawString())
+	if err != nil {
+		return 0, errors.Errorf("Failed to retrieve port as correct integer for monitoring policy:%q due to: %v", policyName, err)
+	}
+	return port, nil
+}
+
 func applyTCPMonitoringPolicy(deploymentID, target string, timeInterval time.Duration, port int, instances []string) error {
 	for _, instance := range instances {
 		ipAddress, err := retrieveIPAddress(deploymentID, target, instance)
@@ -224,6 +239,70 @@ func applyHTTPMonitoringPolicy(kv *api.KV, policyName, deploymentID, target stri
 	return nil
 }
 
+func applyCommandMonitoringPolicy(kv *api.KV, policyName, deploymentID, target string, timeInterval time.Duration, instances []string) error {
+	commandValue, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "command")
+	if err != nil || commandValue == nil || commandValue.RawString() == "" {
+		return errors.Errorf("Failed to retrieve command for monitoring policy:%q due to: %v", policyName, err)
+	}
+	location := commandLocationTarget
+	locationValue, err := deployments.GetPolicyPropertyValue(kv, deploymentID, policyName, "location")
+	if err != nil {
+		return errors.Errorf("Failed to retrieve location for monitoring policy:%q due to: %v", policyName, err)
+	}
+	if locationValue != nil && locationValue.RawString() != "" {
+		location = locationValue.RawString()
+	}
+
+	var hostNode string
+	switch location {
+	case commandLocationTarget:
+		hostNode, err = retrieveSSHHostNode(kv, deploymentID, target)
+		if err != nil {
+			return err
+		}
+	case commandLocationOrchestrator:
+		if !defaultMonManager.cfg.Monitoring.OrchestratorCommandChecksAllowed {
+			return errors.Errorf("Location:%q for monitoring policy:%q is not allowed by the Yorc server configuration", location, policyName)
+		}
+	default:
+		return errors.Errorf("Unsupported location:%q for monitoring policy:%q", location, policyName)
+	}
+
+	for _, instance := range instances {
+		// The IP address is only provided to the command as an environment variable
+		ipAddress, _ := retrieveIPAddress(deploymentID, target, instance)
+		if err := defaultMonManager.registerCommandCheck(deploymentID, target, instance, commandValue.RawString(), location, hostNode, ipAddress, timeInterval); err != nil {
+			return errors.Errorf("Failed to register command check for node name:%q due to: %v", target, err)
+		}
+	}
+	return nil
+}
+
+// retrieveSSHHostNode returns the first node in the hosted-on hierarchy of a node (starting from the node itself)
+// that exposes an endpoint with provisioning credentials allowing to connect to it over SSH
+func retrieveSSHHostNode(kv *api.KV, deploymentID, target string) (string, error) {
+	for host := target; host != ""; {
+		capType, err := deployments.GetNodeCapabilityType(kv, deploymentID, host, "endpoint")
+		if err != nil {
+			return "", err
+		}
+		if capType != "" {
+			hasEndpoint, err := deployments.IsTypeDerivedFrom(kv, deploymentID, capType, "yorc.capabilities.Endpoint.ProvisioningAdmin")
+			if err != nil {
+				return "", err
+			}
+			if hasEndpoint {
+				return host, nil
+			}
+		}
+		host, err = deployments.GetHostedOnNode(kv, deploymentID, host)
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.Errorf("Failed to find a host with provisioning credentials for node name:%q", target)
+}
+
 func retrieveTLSClientConfig(kv *api.KV, policyName, deploymentID string) (map[string]string, error) {
 	tlsClientConfig := make(map[string]string, 0)
 	props := []string{"ca_cert", "ca_path", "client_cert", "client_key", "skip_verify"}
@@ -12,11 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package monitoring is responsible for handling node monitoring (tcp and http checks) especially for tosca.nodes.Compute and tosca.nodes.SoftwareComponent node templates
+// Package monitoring is responsible for handling node monitoring (tcp, http and command checks) especially for tosca.nodes.Compute and tosca.nodes.SoftwareComponent node templates
 // Present limitation : only one monitoring check by node instance is allowed
 package monitoring
 
 import (
+	"context"
 	"path"
 	"strconv"
 	"strings"
@@ -27,6 +28,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/events"
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/log"
 )
@@ -219,6 +221,23 @@ func (mgr *monitoringMgr) startMonitoring() {
 						handleError(err)
 						continue
 					}
+				case CheckTypeCOMMAND:
+					check.execution, err = mgr.buildCommandExecution(key, address, check)
+					if err != nil {
+						handleError(err)
+						continue
+					}
+				}
+
+				check.healing, err = getHealingPolicy(mgr.cc.KV(), check.Report.DeploymentID, check.Report.NodeName)
+				if err != nil {
+					// The check is still started but without healing
+					lof := events.LogOptionalFields{
+						events.InstanceID: check.Report.Instance,
+						events.NodeID:     check.Report.NodeName,
+					}
+					events.WithContextOptionalFields(events.NewContext(context.Background(), lof)).NewLogEntry(events.LogLevelERROR, check.Report.DeploymentID).
+						Registerf("No healing will be applied on check %q as its healing policy is invalid: %v", id, err)
 				}
 
 				reportPath := path.Join(consulutil.MonitoringKVPrefix, "reports", id)
@@ -235,6 +254,21 @@ func (mgr *monitoringMgr) startMonitoring() {
 					}
 				}
 
+				check.flapping, err = getFlappingDetection(mgr.cc.KV(), check.Report.DeploymentID, check.Report.NodeName)
+				if err != nil {
+					handleError(err)
+					continue
+				}
+				check.history, err = ReadCheckHistory(mgr.cc.KV(), id)
+				if err != nil {
+					handleError(err)
+					continue
+				}
+				check.lastResult = check.Report.Status
+				if len(check.history) > 0 {
+					check.lastResult = check.history[len(check.history)-1].Status
+				}
+
 				// Store the check if not already present and start it
 				_, is := mgr.checks[id]
 				if !is {
@@ -288,6 +322,51 @@ func (mgr *monitoringMgr) buildHTTPExecution(key string, address string, port in
 	return newHTTPCheckExecution(address, port, scheme, urlPath, headersMap, tlsConf)
 }
 
+func (mgr *monitoringMgr) buildCommandExecution(key, address string, check *Check) (*commandCheckExecution, error) {
+	kvp, _, err := mgr.cc.KV().Get(path.Join(key, "command"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, errors.Errorf("Missing mandatory field \"command\" for check with key path:%q", key)
+	}
+	command := string(kvp.Value)
+
+	env := []string{
+		"DEPLOYMENT_ID=" + check.Report.DeploymentID,
+		"NODE=" + check.Report.NodeName,
+		"INSTANCE=" + check.Report.Instance,
+	}
+	if address != "" {
+		env = append(env, "IP_ADDRESS="+address)
+	}
+
+	kvp, _, err = mgr.cc.KV().Get(path.Join(key, "location"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if kvp != nil && string(kvp.Value) == commandLocationOrchestrator {
+		// Checks may have been registered before the location was disallowed
+		if !mgr.cfg.Monitoring.OrchestratorCommandChecksAllowed {
+			return nil, errors.Errorf("Running command checks on the orchestrator is not allowed by the Yorc server configuration for check with key path:%q", key)
+		}
+		return newCommandCheckExecution(command, env, nil), nil
+	}
+
+	kvp, _, err = mgr.cc.KV().Get(path.Join(key, "host_node"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, errors.Errorf("Missing mandatory field \"host_node\" for check with key path:%q", key)
+	}
+	sshClient, err := newSSHClientForInstance(mgr.cc.KV(), check.Report.DeploymentID, string(kvp.Value), check.Report.Instance)
+	if err != nil {
+		return nil, err
+	}
+	return newCommandCheckExecution(command, env, sshClient), nil
+}
+
 // registerTCPCheck allows to register a TCP check
 func (mgr *monitoringMgr) registerTCPCheck(deploymentID, nodeName, instance, ipAddress string, port int, interval time.Duration) error {
 	id := buildID(deploymentID, nodeName, instance)
@@ -434,6 +513,83 @@ func (mgr *monitoringMgr) registerHTTPCheck(deploymentID, nodeName, instance, ip
 	return nil
 }
 
+// registerCommandCheck allows to register a command check
+func (mgr *monitoringMgr) registerCommandCheck(deploymentID, nodeName, instance, command, location, hostNode, ipAddress string, interval time.Duration) error {
+	id := buildID(deploymentID, nodeName, instance)
+	log.Debugf("Register command check with id:%q, location:%q, interval:%d", id, location, interval)
+
+	// Check is registered in a transaction to ensure to be read in its wholeness
+	checkPath := path.Join(consulutil.MonitoringKVPrefix, "checks", id)
+	checkReportPath := path.Join(consulutil.MonitoringKVPrefix, "reports", id)
+
+	kvps, _, err := mgr.cc.KV().List(checkPath, nil)
+	if err != nil {
+		return err
+	}
+	if kvps != nil {
+		log.Debugf("Command check with id:%q is already registered: nothing to do", id)
+		return nil
+	}
+
+	checkOps := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "type"),
+			Value: []byte("command"),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "command"),
+			Value: []byte(command),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "location"),
+			Value: []byte(location),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "interval"),
+			Value: []byte(interval.String()),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkReportPath, "status"),
+			Value: []byte(CheckStatusINITIAL.String()),
+		},
+	}
+
+	if ipAddress != "" {
+		checkOps = append(checkOps, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "address"),
+			Value: []byte(ipAddress),
+		})
+	}
+
+	if hostNode != "" {
+		checkOps = append(checkOps, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(checkPath, "host_node"),
+			Value: []byte(hostNode),
+		})
+	}
+
+	ok, response, _, err := mgr.cc.KV().Txn(checkOps, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to add command check with id:%q", id)
+	}
+	if !ok {
+		// Check the response
+		errs := make([]string, 0)
+		for _, e := range response.Errors {
+			errs = append(errs, e.What)
+		}
+		return errors.Errorf("Failed to add command check with id:%q due to:%s", id, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // flagCheckForRemoval allows to remove a check report and flag a check in order to remove it
 func (mgr *monitoringMgr) flagCheckForRemoval(deploymentID, nodeName, instance string) error {
 	id := buildID(deploymentID, nodeName, instance)
@@ -486,8 +642,15 @@ type CheckFilterFunc func(CheckReport) bool
 
 // listCheckReports can return a filtered checks reports list if defined filter function. Otherwise, it returns the full check reports.
 func (mgr *monitoringMgr) listCheckReports(f CheckFilterFunc) ([]CheckReport, error) {
+	return ListCheckReports(mgr.cc.KV(), f)
+}
+
+// ListCheckReports returns the monitoring check reports matching the given filter function.
+//
+// If the filter function is nil, all check reports are returned.
+func ListCheckReports(kv *api.KV, f CheckFilterFunc) ([]CheckReport, error) {
 	log.Debugf("List check reports")
-	keys, _, err := mgr.cc.KV().Keys(path.Join(consulutil.MonitoringKVPrefix, "reports")+"/", "/", nil)
+	keys, _, err := kv.Keys(path.Join(consulutil.MonitoringKVPrefix, "reports")+"/", "/", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -498,22 +661,71 @@ func (mgr *monitoringMgr) listCheckReports(f CheckFilterFunc) ([]CheckReport, er
 		if err != nil {
 			return nil, err
 		}
-
-		kvp, _, err := mgr.cc.KV().Get(path.Join(key, "status"), nil)
+		err = readCheckReport(kv, key, &check.Report)
 		if err != nil {
-			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-		}
-		if kvp != nil && len(kvp.Value) > 0 {
-			check.Report.Status, err = ParseCheckStatus(string(kvp.Value))
-			if err != nil {
-				return nil, err
-			}
+			return nil, err
 		}
 		checkReports = append(checkReports, check.Report)
 	}
 	return filter(checkReports, f), nil
 }
 
+func readCheckReport(kv *api.KV, reportKey string, report *CheckReport) error {
+	kvp, _, err := kv.Get(path.Join(reportKey, "status"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		report.Status, err = ParseCheckStatus(string(kvp.Value))
+		if err != nil {
+			return err
+		}
+	}
+
+	kvp, _, err = kv.Get(path.Join(reportKey, "last_transition"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		report.LastTransition, err = time.Parse(time.RFC3339Nano, string(kvp.Value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse last transition time for check %q", path.Base(reportKey))
+		}
+	}
+
+	kvp, _, err = kv.Get(path.Join(reportKey, "message"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil {
+		report.Message = string(kvp.Value)
+	}
+
+	checkPath := path.Join(consulutil.MonitoringKVPrefix, "checks", path.Base(reportKey))
+	kvp, _, err = kv.Get(path.Join(checkPath, "type"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		report.CheckType, err = ParseCheckType(string(kvp.Value))
+		if err != nil {
+			return err
+		}
+	}
+
+	kvp, _, err = kv.Get(path.Join(checkPath, "interval"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && len(kvp.Value) > 0 {
+		report.TimeInterval, err = time.ParseDuration(string(kvp.Value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse interval for check %q", path.Base(reportKey))
+		}
+	}
+	return nil
+}
+
 func filter(tab []CheckReport, f CheckFilterFunc) []CheckReport {
 	if f == nil {
 		return tab
@@ -149,3 +149,37 @@ func testAddAndRemoveCheck(t *testing.T, client *api.Client) {
 	require.Len(t, checkReports, 0, "0 check is expected")
 	require.Len(t, defaultMonManager.checks, 0, "0 check is expected in work map")
 }
+
+func testAddAndRemoveCommandCheck(t *testing.T, client *api.Client) {
+	log.SetDebug(true)
+
+	dep := "monitoring6"
+	node := "Compute1"
+	instance := "0"
+
+	err := defaultMonManager.registerCommandCheck(dep, node, instance, "exit 1", commandLocationOrchestrator, "", "1.2.3.4", 1*time.Second)
+	require.Nil(t, err, "Unexpected error while adding check")
+
+	time.Sleep(2 * time.Second)
+	checkReports, err := defaultMonManager.listCheckReports(func(cr CheckReport) bool {
+		return cr.DeploymentID == dep
+	})
+	require.Nil(t, err, "Unexpected error while getting check reports list")
+	require.Len(t, checkReports, 1, "1 check is expected")
+	require.Equal(t, CheckStatusWARNING, checkReports[0].Status, "unexpected status")
+
+	// Check the instance state has been updated
+	state, err := deployments.GetInstanceState(client.KV(), dep, node, instance)
+	require.Nil(t, err, "Unexpected error while node state")
+	require.Equal(t, tosca.NodeStateError, state)
+
+	err = defaultMonManager.flagCheckForRemoval(dep, node, instance)
+	require.Nil(t, err, "Unexpected error while removing check")
+	time.Sleep(1 * time.Second)
+
+	checkReports, err = defaultMonManager.listCheckReports(func(cr CheckReport) bool {
+		return cr.DeploymentID == dep
+	})
+	require.Nil(t, err, "Unexpected error while getting check reports list")
+	require.Len(t, checkReports, 0, "0 check is expected")
+}
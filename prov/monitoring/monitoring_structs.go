@@ -27,12 +27,14 @@ import (
 // PASSING,
 // CRITICAL
 // WARNING
+// FLAPPING
 // )
 type CheckStatus int
 
 // CheckType x ENUM(
 // TCP,
-// HTTP
+// HTTP,
+// COMMAND
 // )
 type CheckType int
 
@@ -51,6 +53,14 @@ type Check struct {
 	timeout   time.Duration
 	ctx       context.Context
 	execution checkExecution
+
+	healing              *healingPolicy
+	consecutiveCriticals int
+	healingExhausted     bool
+
+	flapping   *flappingDetection
+	history    []CheckResult
+	lastResult CheckStatus
 }
 
 // CheckReport represents a node check report including its status
@@ -59,4 +69,10 @@ type CheckReport struct {
 	NodeName     string
 	Instance     string
 	Status       CheckStatus
+	CheckType    CheckType
+	TimeInterval time.Duration
+	// LastTransition is the time of the last status change. It is zero if the status never changed since the check registration.
+	LastTransition time.Time
+	// Message is the additional message provided by the check execution on the last status change
+	Message string
 }
@@ -31,15 +31,18 @@ const (
 	CheckStatusCRITICAL
 	// CheckStatusWARNING is a CheckStatus of type WARNING
 	CheckStatusWARNING
+	// CheckStatusFLAPPING is a CheckStatus of type FLAPPING
+	CheckStatusFLAPPING
 )
 
-const _CheckStatusName = "INITIALPASSINGCRITICALWARNING"
+const _CheckStatusName = "INITIALPASSINGCRITICALWARNINGFLAPPING"
 
 var _CheckStatusMap = map[CheckStatus]string{
 	0: _CheckStatusName[0:7],
 	1: _CheckStatusName[7:14],
 	2: _CheckStatusName[14:22],
 	3: _CheckStatusName[22:29],
+	4: _CheckStatusName[29:37],
 }
 
 func (i CheckStatus) String() string {
@@ -58,6 +61,8 @@ var _CheckStatusValue = map[string]CheckStatus{
 	strings.ToLower(_CheckStatusName[14:22]): 2,
 	_CheckStatusName[22:29]:                  3,
 	strings.ToLower(_CheckStatusName[22:29]): 3,
+	_CheckStatusName[29:37]:                  4,
+	strings.ToLower(_CheckStatusName[29:37]): 4,
 }
 
 // ParseCheckStatus attempts to convert a string to a CheckStatus
@@ -73,13 +78,16 @@ const (
 	CheckTypeTCP CheckType = iota
 	// CheckTypeHTTP is a CheckType of type HTTP
 	CheckTypeHTTP
+	// CheckTypeCOMMAND is a CheckType of type COMMAND
+	CheckTypeCOMMAND
 )
 
-const _CheckTypeName = "TCPHTTP"
+const _CheckTypeName = "TCPHTTPCOMMAND"
 
 var _CheckTypeMap = map[CheckType]string{
 	0: _CheckTypeName[0:3],
 	1: _CheckTypeName[3:7],
+	2: _CheckTypeName[7:14],
 }
 
 func (i CheckType) String() string {
@@ -90,10 +98,12 @@ func (i CheckType) String() string {
 }
 
 var _CheckTypeValue = map[string]CheckType{
-	_CheckTypeName[0:3]:                  0,
-	strings.ToLower(_CheckTypeName[0:3]): 0,
-	_CheckTypeName[3:7]:                  1,
-	strings.ToLower(_CheckTypeName[3:7]): 1,
+	_CheckTypeName[0:3]:                   0,
+	strings.ToLower(_CheckTypeName[0:3]):  0,
+	_CheckTypeName[3:7]:                   1,
+	strings.ToLower(_CheckTypeName[3:7]):  1,
+	_CheckTypeName[7:14]:                  2,
+	strings.ToLower(_CheckTypeName[7:14]): 2,
 }
 
 // ParseCheckType attempts to convert a string to a CheckType
@@ -48,15 +48,17 @@ func (ei EnvInput) String() string {
 }
 
 // ResolveInputs allows to resolve inputs for an operation
-func ResolveInputs(kv *api.KV, deploymentID, nodeName, taskID string, operation prov.Operation) ([]*EnvInput, []string, error) {
-	sourceInstances, err := tasks.GetInstances(kv, taskID, deploymentID, nodeName)
+//
+// Instances are retrieved using tasks.GetInstancesWithContext.
+func ResolveInputs(ctx context.Context, kv *api.KV, deploymentID, nodeName, taskID string, operation prov.Operation) ([]*EnvInput, []string, error) {
+	sourceInstances, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var targetInstances []string
 	if operation.RelOp.IsRelationshipOperation {
-		targetInstances, err = tasks.GetInstances(kv, taskID, deploymentID, operation.RelOp.TargetNodeName)
+		targetInstances, err = tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, operation.RelOp.TargetNodeName)
 		if err != nil {
 			return nil, nil, err
 		}
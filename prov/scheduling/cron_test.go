@@ -0,0 +1,85 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{"AllStars", "* * * * *", false},
+		{"Nightly", "30 2 * * *", false},
+		{"ListsRangesSteps", "0,30 8-18/2 1-15 */3 mon-fri", false},
+		{"Names", "0 0 * jan,jul sun", false},
+		{"Predefined", "@daily", false},
+		{"SundayAsSeven", "0 0 * * 7", false},
+		{"TooFewFields", "* * * *", true},
+		{"TooManyFields", "* * * * * *", true},
+		{"OutOfRange", "60 * * * *", true},
+		{"InvalidRange", "* 10-2 * * *", true},
+		{"InvalidStep", "*/0 * * * *", true},
+		{"InvalidValue", "* * * foo *", true},
+		{"UnknownMacro", "@every5m", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCronExpression(tt.expression)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	t.Parallel()
+	// Thursday
+	from := time.Date(2018, time.November, 15, 10, 42, 27, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"EveryMinute", "* * * * *", from, time.Date(2018, time.November, 15, 10, 43, 0, 0, time.UTC)},
+		{"EveryQuarter", "*/15 * * * *", from, time.Date(2018, time.November, 15, 10, 45, 0, 0, time.UTC)},
+		{"NightlyNextDay", "30 2 * * *", from, time.Date(2018, time.November, 16, 2, 30, 0, 0, time.UTC)},
+		{"StrictlyAfter", "42 10 * * *", time.Date(2018, time.November, 15, 10, 42, 0, 0, time.UTC), time.Date(2018, time.November, 16, 10, 42, 0, 0, time.UTC)},
+		{"Weekly", "@weekly", from, time.Date(2018, time.November, 18, 0, 0, 0, 0, time.UTC)},
+		{"Monday", "0 8 * * mon", from, time.Date(2018, time.November, 19, 8, 0, 0, 0, time.UTC)},
+		{"NextYear", "@yearly", from, time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"DayOfMonthOrDayOfWeek", "0 0 20 * fri", from, time.Date(2018, time.November, 16, 0, 0, 0, 0, time.UTC)},
+		{"LeapDay", "0 0 29 feb *", from, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"Never", "0 0 30 feb *", from, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCronExpression(tt.expression)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, c.Next(tt.from))
+		})
+	}
+}
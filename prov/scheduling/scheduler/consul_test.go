@@ -52,5 +52,11 @@ func TestRunConsulSchedulingPackageTests(t *testing.T) {
 		t.Run("testUnregisterAction", func(t *testing.T) {
 			testUnregisterAction(t, client)
 		})
+		t.Run("testRegisterCronAction", func(t *testing.T) {
+			testRegisterCronAction(t, client)
+		})
+		t.Run("testPausedScheduledAction", func(t *testing.T) {
+			testPausedScheduledAction(t, client)
+		})
 	})
 }
@@ -17,6 +17,7 @@ package scheduler
 import (
 	"context"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,12 +25,14 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v3/deployments"
 	"github.com/ystia/yorc/v3/events"
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/helper/metricsutil"
 	"github.com/ystia/yorc/v3/helper/stringutil"
 	"github.com/ystia/yorc/v3/log"
 	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/prov/scheduling"
 	"github.com/ystia/yorc/v3/tasks"
 )
 
@@ -38,6 +41,7 @@ type scheduledAction struct {
 	kv                   *api.KV
 	deploymentID         string
 	timeInterval         time.Duration
+	cronSchedule         *scheduling.CronSchedule
 	latestDataIndex      uint64
 	asyncOperationString string
 
@@ -68,6 +72,10 @@ func (sca *scheduledAction) stop() {
 
 func (sca *scheduledAction) schedule() {
 	log.Debugf("Scheduling action with ID:%q", sca.ID)
+	if sca.cronSchedule != nil {
+		sca.scheduleCron()
+		return
+	}
 	ticker := time.NewTicker(sca.timeInterval)
 	for {
 		select {
@@ -88,10 +96,43 @@ func (sca *scheduledAction) schedule() {
 	}
 }
 
+func (sca *scheduledAction) scheduleCron() {
+	for {
+		next := sca.cronSchedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Scheduled action with id:%q will never be triggered by its cron expression", sca.ID)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-sca.chStop:
+			log.Debugf("Stop scheduling action with id:%s", sca.ID)
+			timer.Stop()
+			return
+		case <-timer.C:
+			// Contrary to periodic actions an error doesn't prevent next triggers
+			// as they may be far away in the future
+			err := sca.proceed()
+			if err != nil {
+				log.Printf("Failed to schedule action:%+v due to err:%+v", sca, err)
+			}
+		}
+	}
+}
+
 func (sca *scheduledAction) proceed() error {
 	metrics.IncrCounter(metricsutil.CleanupMetricKey([]string{"scheduling", sca.ActionType, sca.ID, "ticks"}), 1)
+	actionPath := path.Join(consulutil.SchedulingKVPrefix, "actions", sca.ID)
+	kvp, _, err := sca.kv.Get(path.Join(actionPath, "paused"), nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil && strings.ToLower(string(kvp.Value)) == "true" {
+		log.Debugf("Scheduled action with id:%q is paused, skip this trigger", sca.ID)
+		return nil
+	}
 	// To fit with Task Manager, pass the id/actionType in data
-	err := sca.updateData()
+	err = sca.updateData()
 	if err != nil {
 		return err
 	}
@@ -127,11 +168,33 @@ func (sca *scheduledAction) proceed() error {
 		}
 	}
 
-	sca.latestTaskID, err = defaultScheduler.collector.RegisterTaskWithData(sca.deploymentID, tasks.TaskTypeAction, sca.Data)
+	taskType := tasks.TaskTypeAction
+	switch sca.ActionType {
+	case scheduling.WorkflowActionType:
+		taskType = tasks.TaskTypeCustomWorkflow
+	case scheduling.CustomCommandActionType:
+		taskType = tasks.TaskTypeCustomCommand
+		// Custom commands run on all the instances of the node existing when triggered
+		nodeName := sca.Data["nodeName"]
+		instances, err := deployments.GetNodeInstancesIds(sca.kv, sca.deploymentID, nodeName)
+		if err != nil {
+			return err
+		}
+		sca.Data[path.Join("nodes", nodeName)] = strings.Join(instances, ",")
+	}
+
+	taskID, err := defaultScheduler.collector.RegisterTaskWithData(sca.deploymentID, taskType, sca.Data)
 	if err != nil {
+		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+			events.SimpleLogEntry(events.LogLevelWARN, sca.deploymentID).Registerf("Scheduled action with id %q missed a trigger as another task is running on this deployment: %v", sca.ID, err)
+			metrics.IncrCounter(metricsutil.CleanupMetricKey([]string{"scheduling", sca.ActionType, sca.ID, "misses"}), 1)
+			return nil
+		}
 		return err
 	}
-	consulutil.StoreConsulKeyAsString(path.Join(consulutil.SchedulingKVPrefix, "actions", sca.ID, "latestTaskID"), sca.latestTaskID)
+	sca.latestTaskID = taskID
+	consulutil.StoreConsulKeyAsString(path.Join(actionPath, "latestTaskID"), sca.latestTaskID)
+	consulutil.StoreConsulKeyAsString(path.Join(actionPath, "lastRun"), time.Now().Format(time.RFC3339Nano))
 	log.Debugf("Proceed scheduled action with ID:%q with taskID:%q", sca.ID, sca.latestTaskID)
 	return nil
 }
@@ -149,7 +212,7 @@ func (sca *scheduledAction) updateData() error {
 			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 		}
 		for _, kvp := range kvps {
-			key := path.Base(kvp.Key)
+			key := strings.TrimPrefix(kvp.Key, dataPath+"/")
 			sca.Data[key] = string(kvp.Value)
 		}
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/ystia/yorc/v3/config"
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/prov/scheduling"
 	"github.com/ystia/yorc/v3/tasks/collector"
 )
 
@@ -195,18 +196,29 @@ func (sc *scheduler) buildScheduledAction(id string) (*scheduledAction, error) {
 	if kvp != nil {
 		sca.ActionType = string(kvp.Value)
 	}
-	kvp, _, err = sc.cc.KV().Get(path.Join(actionPrefix, "interval"), nil)
+	kvp, _, err = sc.cc.KV().Get(path.Join(actionPrefix, "cron"), nil)
 	if err != nil {
 		return nil, err
 	}
-	if kvp == nil || len(kvp.Value) == 0 {
-		return nil, errors.Errorf("Missing interval for action: %q", id)
-	}
-	d, err := time.ParseDuration(string(kvp.Value))
-	if err != nil {
-		return nil, err
+	if kvp != nil && len(kvp.Value) > 0 {
+		sca.cronSchedule, err = scheduling.ParseCronExpression(string(kvp.Value))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		kvp, _, err = sc.cc.KV().Get(path.Join(actionPrefix, "interval"), nil)
+		if err != nil {
+			return nil, err
+		}
+		if kvp == nil || len(kvp.Value) == 0 {
+			return nil, errors.Errorf("Missing interval for action: %q", id)
+		}
+		d, err := time.ParseDuration(string(kvp.Value))
+		if err != nil {
+			return nil, err
+		}
+		sca.timeInterval = d
 	}
-	sca.timeInterval = d
 	kvp, _, err = sc.cc.KV().Get(path.Join(actionPrefix, "async_op"), nil)
 	if err != nil {
 		return nil, err
@@ -226,14 +238,16 @@ func (sc *scheduler) buildScheduledAction(id string) (*scheduledAction, error) {
 		sca.latestTaskID = string(kvp.Value)
 	}
 
-	kvps, _, err := sc.cc.KV().List(path.Join(consulutil.SchedulingKVPrefix, "actions", id, "data"), nil)
+	dataPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id, "data")
+	kvps, _, err := sc.cc.KV().List(dataPath, nil)
 	if err != nil {
 		return nil, err
 	}
 	sca.Data = make(map[string]string, len(kvps))
 
 	for _, kvp := range kvps {
-		key := path.Base(kvp.Key)
+		// data keys may be hierarchical (like inputs/name)
+		key := strings.TrimPrefix(kvp.Key, dataPath+"/")
 		if len(kvp.Value) > 0 {
 			sca.Data[key] = string(kvp.Value)
 		}
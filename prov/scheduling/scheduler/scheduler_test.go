@@ -157,3 +157,67 @@ func testUnregisterAction(t *testing.T, client *api.Client) {
 	require.NotNil(t, kvp, "kvp is nil")
 	require.Equal(t, "true", string(kvp.Value), "unregisterFlag is not set to true")
 }
+
+func testRegisterCronAction(t *testing.T, client *api.Client) {
+	t.Parallel()
+	deploymentID := "dep-" + t.Name()
+	action := &prov.Action{ActionType: scheduling.WorkflowActionType, Data: map[string]string{"workflowName": "backup", "inputs/retention": "7"}}
+	_, err := scheduling.RegisterCronAction(client, deploymentID, "0 0 32 * *", action)
+	require.Error(t, err, "an invalid cron expression should be rejected")
+
+	id, err := scheduling.RegisterCronAction(client, deploymentID, "@daily", action)
+	require.NoError(t, err)
+
+	sca, err := defaultScheduler.buildScheduledAction(id)
+	require.NoError(t, err)
+	require.NotNil(t, sca.cronSchedule)
+	require.Equal(t, "7", sca.Data["inputs/retention"])
+
+	registered, err := scheduling.GetAction(client.KV(), id)
+	require.NoError(t, err)
+	require.NotNil(t, registered)
+	require.Equal(t, deploymentID, registered.DeploymentID)
+	require.Equal(t, scheduling.WorkflowActionType, registered.ActionType)
+	require.Equal(t, "@daily", registered.CronExpression)
+	require.Equal(t, action.Data, registered.Data)
+	require.False(t, registered.Paused)
+
+	actions, err := scheduling.GetDeploymentActions(client.KV(), deploymentID, scheduling.WorkflowActionType)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	actions, err = scheduling.GetDeploymentActions(client.KV(), deploymentID, scheduling.CustomCommandActionType)
+	require.NoError(t, err)
+	require.Len(t, actions, 0)
+
+	err = scheduling.UnregisterAction(client, id)
+	require.NoError(t, err)
+	registered, err = scheduling.GetAction(client.KV(), id)
+	require.NoError(t, err)
+	require.Nil(t, registered, "unregistered actions should not be returned")
+}
+
+func testPausedScheduledAction(t *testing.T, client *api.Client) {
+	t.Parallel()
+	deploymentID := "dep-" + t.Name()
+	action := &prov.Action{ActionType: "test-action", Data: map[string]string{"key1": "val1"}}
+	id, err := scheduling.RegisterAction(client, deploymentID, time.Hour, action)
+	require.NoError(t, err)
+
+	err = scheduling.PauseAction(client, id)
+	require.NoError(t, err)
+	registered, err := scheduling.GetAction(client.KV(), id)
+	require.NoError(t, err)
+	require.True(t, registered.Paused)
+
+	sca, err := defaultScheduler.buildScheduledAction(id)
+	require.NoError(t, err)
+	err = sca.proceed()
+	require.NoError(t, err)
+	require.Empty(t, sca.latestTaskID, "no task should be launched by a paused action")
+
+	err = scheduling.ResumeAction(client, id)
+	require.NoError(t, err)
+	registered, err = scheduling.GetAction(client.KV(), id)
+	require.NoError(t, err)
+	require.False(t, registered.Paused)
+}
@@ -24,14 +24,51 @@ import (
 	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 
+	"github.com/ystia/yorc/v3/helper/collections"
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/log"
 	"github.com/ystia/yorc/v3/prov"
 )
 
+const (
+	// WorkflowActionType is the type of scheduled actions running a custom workflow
+	WorkflowActionType = "workflow"
+	// CustomCommandActionType is the type of scheduled actions running a custom command
+	CustomCommandActionType = "custom-command"
+)
+
+// ScheduledAction is the representation of a registered scheduled action
+type ScheduledAction struct {
+	prov.Action
+	DeploymentID string
+	// Interval is set for actions triggered periodically
+	Interval time.Duration
+	// CronExpression is set for actions triggered according to a cron expression
+	CronExpression string
+	Paused         bool
+	LatestTaskID   string
+	// LastRun is the time of the latest task launched by this action
+	LastRun time.Time
+}
+
 // RegisterAction allows to register a scheduled action and to start scheduling it
 func RegisterAction(client *api.Client, deploymentID string, timeInterval time.Duration, action *prov.Action) (string, error) {
 	log.Debugf("Action with ID:%q has been requested to be registered for scheduling with [deploymentID:%q, timeInterval:%q]", action.ID, deploymentID, timeInterval.String())
+	return registerAction(client, deploymentID, "interval", timeInterval.String(), action)
+}
+
+// RegisterCronAction allows to register an action scheduled according to a cron expression and to start scheduling it
+//
+// See CronSchedule for the supported cron expressions.
+func RegisterCronAction(client *api.Client, deploymentID, cronExpression string, action *prov.Action) (string, error) {
+	log.Debugf("Action with ID:%q has been requested to be registered for scheduling with [deploymentID:%q, cron:%q]", action.ID, deploymentID, cronExpression)
+	if _, err := ParseCronExpression(cronExpression); err != nil {
+		return "", err
+	}
+	return registerAction(client, deploymentID, "cron", cronExpression, action)
+}
+
+func registerAction(client *api.Client, deploymentID, scheduleKey, scheduleValue string, action *prov.Action) (string, error) {
 	id := uuid.NewV4().String()
 
 	// Check mandatory parameters
@@ -59,8 +96,8 @@ func RegisterAction(client *api.Client, deploymentID string, timeInterval time.D
 		},
 		&api.KVTxnOp{
 			Verb:  api.KVSet,
-			Key:   path.Join(scaPath, "interval"),
-			Value: []byte(timeInterval.String()),
+			Key:   path.Join(scaPath, scheduleKey),
+			Value: []byte(scheduleValue),
 		},
 		&api.KVTxnOp{
 			Verb:  api.KVSet,
@@ -108,3 +145,117 @@ func UpdateActionData(client *api.Client, id, key, value string) error {
 	scaKeyPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id, "data", key)
 	return errors.Wrapf(consulutil.StoreConsulKeyAsString(scaKeyPath, value), "Failed to update data %q for action %q", key, id)
 }
+
+// PauseAction allows to suspend the scheduling of an action without unregistering it
+func PauseAction(client *api.Client, id string) error {
+	scaKeyPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id, "paused")
+	return errors.Wrapf(consulutil.StoreConsulKeyAsString(scaKeyPath, "true"), "Failed to pause action %q", id)
+}
+
+// ResumeAction allows to resume the scheduling of a paused action
+func ResumeAction(client *api.Client, id string) error {
+	scaKeyPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id, "paused")
+	return errors.Wrapf(consulutil.StoreConsulKeyAsString(scaKeyPath, "false"), "Failed to resume action %q", id)
+}
+
+// GetAction returns a registered scheduled action
+//
+// A nil action is returned if it doesn't exist or if it has been unregistered.
+func GetAction(kv *api.KV, id string) (*ScheduledAction, error) {
+	scaPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id)
+	kvps, _, err := kv.List(scaPath+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if len(kvps) == 0 {
+		return nil, nil
+	}
+	return readAction(id, kvps)
+}
+
+// readAction builds a scheduled action from the keys stored under its path
+//
+// A nil action is returned if it has been unregistered.
+func readAction(id string, kvps api.KVPairs) (*ScheduledAction, error) {
+	scaPath := path.Join(consulutil.SchedulingKVPrefix, "actions", id)
+	sca := &ScheduledAction{Action: prov.Action{ID: id, Data: make(map[string]string)}}
+	var err error
+	for _, kvp := range kvps {
+		key := strings.TrimPrefix(kvp.Key, scaPath+"/")
+		value := string(kvp.Value)
+		switch {
+		case key == ".unregisterFlag" && strings.ToLower(value) == "true":
+			return nil, nil
+		case key == "deploymentID":
+			sca.DeploymentID = value
+		case key == "type":
+			sca.ActionType = value
+		case key == "interval":
+			sca.Interval, err = time.ParseDuration(value)
+		case key == "cron":
+			sca.CronExpression = value
+		case key == "paused":
+			sca.Paused = strings.ToLower(value) == "true"
+		case key == "latestTaskID":
+			sca.LatestTaskID = value
+		case key == "lastRun":
+			sca.LastRun, err = time.Parse(time.RFC3339Nano, value)
+		case key == "async_op" && len(kvp.Value) > 0:
+			err = json.Unmarshal(kvp.Value, &sca.AsyncOperation)
+		case strings.HasPrefix(key, "data/"):
+			sca.Data[strings.TrimPrefix(key, "data/")] = value
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read %q for action %q", key, id)
+		}
+	}
+	return sca, nil
+}
+
+// GetDeploymentActions returns the registered scheduled actions of a given deployment
+//
+// If some action types are given only actions of these types are returned.
+func GetDeploymentActions(kv *api.KV, deploymentID string, actionTypes ...string) ([]*ScheduledAction, error) {
+	// Actions are read at once and filtered in memory
+	actionsPath := path.Join(consulutil.SchedulingKVPrefix, "actions") + "/"
+	kvps, _, err := kv.List(actionsPath, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	ids := make([]string, 0)
+	actionsKVs := make(map[string]api.KVPairs)
+	for _, kvp := range kvps {
+		id := strings.SplitN(strings.TrimPrefix(kvp.Key, actionsPath), "/", 2)[0]
+		if _, ok := actionsKVs[id]; !ok {
+			ids = append(ids, id)
+		}
+		actionsKVs[id] = append(actionsKVs[id], kvp)
+	}
+	actions := make([]*ScheduledAction, 0)
+	for _, id := range ids {
+		sca, err := readAction(id, actionsKVs[id])
+		if err != nil {
+			return nil, err
+		}
+		if sca == nil || sca.DeploymentID != deploymentID || (len(actionTypes) > 0 && !collections.ContainsString(actionTypes, sca.ActionType)) {
+			continue
+		}
+		actions = append(actions, sca)
+	}
+	return actions, nil
+}
+
+// UnregisterDeploymentActions allows to unregister all scheduled actions of a given deployment
+func UnregisterDeploymentActions(client *api.Client, deploymentID string) error {
+	actions, err := GetDeploymentActions(client.KV(), deploymentID)
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		err = UnregisterAction(client, action.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
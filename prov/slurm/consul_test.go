@@ -48,5 +48,11 @@ func TestRunConsulSlurmPackageTests(t *testing.T) {
 		t.Run("multipleSlurmNodeAllocation", func(t *testing.T) {
 			testMultipleSlurmNodeAllocation(t, kv, cfg)
 		})
+		t.Run("slurmJobDependencies", func(t *testing.T) {
+			testSlurmJobDependencies(t, kv, cfg)
+		})
+		t.Run("slurmResolveInstancesOfBatch", func(t *testing.T) {
+			testSlurmResolveInstancesOfBatch(t, kv, cfg)
+		})
 	})
 }
@@ -80,7 +80,7 @@ type executionCommon struct {
 	isSingularity  bool
 }
 
-func newExecution(kv *api.KV, cfg config.Configuration, taskID, deploymentID, nodeName, stepName string, operation prov.Operation) (execution, error) {
+func newExecution(ctx context.Context, kv *api.KV, cfg config.Configuration, taskID, deploymentID, nodeName, stepName string, operation prov.Operation) (execution, error) {
 	isSingularity, err := deployments.IsTypeDerivedFrom(kv, deploymentID, operation.ImplementationArtifact, artifactImageImplementation)
 	if err != nil {
 		return nil, err
@@ -96,7 +96,7 @@ func newExecution(kv *api.KV, cfg config.Configuration, taskID, deploymentID, no
 		stepName:       stepName,
 		isSingularity:  isSingularity,
 	}
-	if err := execCommon.resolveOperation(); err != nil {
+	if err := execCommon.resolveOperation(ctx); err != nil {
 		return nil, err
 	}
 	// Get user credentials from credentials node property
@@ -363,6 +363,18 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 		e.jobInfo.Reservation = res.RawString()
 	}
 
+	// Job array
+	if arr, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.NodeName, "slurm_options", "array"); err != nil {
+		return err
+	} else if arr != nil && arr.RawString() != "" {
+		e.jobInfo.Array = arr.RawString()
+	}
+
+	// Dependencies on other jobs
+	if e.jobInfo.Dependencies, err = e.getJobDependencies(); err != nil {
+		return err
+	}
+
 	// Execution options
 	if ea, err := deployments.GetNodePropertyValue(e.kv, e.deploymentID, e.NodeName, "execution_options", "args"); err != nil {
 		return err
@@ -397,6 +409,54 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 	return nil
 }
 
+// getJobDependencies returns the Slurm dependencies (ex: "afterok:1234") of the job
+// defined by its DependsOn relationships targeting other Slurm jobs
+func (e *executionCommon) getJobDependencies() ([]string, error) {
+	reqIndexes, err := deployments.GetRequirementsIndexes(e.kv, e.deploymentID, e.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := make([]string, 0)
+	for _, reqIndex := range reqIndexes {
+		relType, err := deployments.GetRelationshipForRequirement(e.kv, e.deploymentID, e.NodeName, reqIndex)
+		if err != nil {
+			return nil, err
+		}
+		if relType == "" {
+			continue
+		}
+		if isDependsOn, err := deployments.IsTypeDerivedFrom(e.kv, e.deploymentID, relType, "tosca.relationships.DependsOn"); err != nil {
+			return nil, err
+		} else if !isDependsOn {
+			continue
+		}
+		targetNode, err := deployments.GetTargetNodeForRequirement(e.kv, e.deploymentID, e.NodeName, reqIndex)
+		if err != nil {
+			return nil, err
+		}
+		if isJob, err := deployments.IsNodeDerivedFrom(e.kv, e.deploymentID, targetNode, "yorc.nodes.slurm.Job"); err != nil {
+			return nil, err
+		} else if !isJob {
+			continue
+		}
+		dependencyType := "afterok"
+		if dt, err := deployments.GetRelationshipPropertyValueFromRequirement(e.kv, e.deploymentID, e.NodeName, reqIndex, "dependency_type"); err != nil {
+			return nil, err
+		} else if dt != nil && dt.RawString() != "" {
+			dependencyType = dt.RawString()
+		}
+		id, err := deployments.GetInstanceAttributeValue(e.kv, e.deploymentID, targetNode, "0", "job_id")
+		if err != nil {
+			return nil, err
+		}
+		if id == nil || id.RawString() == "" {
+			return nil, errors.Errorf("job %q depends on job %q which has not been submitted yet", e.NodeName, targetNode)
+		}
+		dependencies = append(dependencies, fmt.Sprintf("%s:%s", dependencyType, id.RawString()))
+	}
+	return dependencies, nil
+}
+
 func (e *executionCommon) buildJobOpts() string {
 	var opts string
 	opts += fmt.Sprintf(" --job-name=%s", e.jobInfo.Name)
@@ -422,6 +482,12 @@ func (e *executionCommon) buildJobOpts() string {
 	if e.jobInfo.Account != "" {
 		opts += fmt.Sprintf(" --account=%s", e.jobInfo.Account)
 	}
+	if e.jobInfo.Array != "" {
+		opts += fmt.Sprintf(" --array=%s", e.jobInfo.Array)
+	}
+	if len(e.jobInfo.Dependencies) > 0 {
+		opts += fmt.Sprintf(" --dependency=%s", strings.Join(e.jobInfo.Dependencies, ","))
+	}
 	log.Debugf("opts=%q", opts)
 	return opts
 }
@@ -558,7 +624,7 @@ func (e *executionCommon) uploadArtifact(ctx context.Context, pathFile, artifact
 	return e.client.CopyFile(bytes.NewReader(source), remotePath, "0755")
 }
 
-func (e *executionCommon) resolveOperation() error {
+func (e *executionCommon) resolveOperation(ctx context.Context) error {
 	var err error
 	e.NodeType, err = deployments.GetNodeType(e.kv, e.deploymentID, e.NodeName)
 	if err != nil {
@@ -594,12 +660,12 @@ func (e *executionCommon) resolveOperation() error {
 	}
 
 	log.Debugf("primary implementation: %q", e.Primary)
-	return e.resolveInstances()
+	return e.resolveInstances(ctx)
 }
 
-func (e *executionCommon) resolveInstances() error {
+func (e *executionCommon) resolveInstances(ctx context.Context) error {
 	var err error
-	if e.nodeInstances, err = tasks.GetInstances(e.kv, e.taskID, e.deploymentID, e.NodeName); err != nil {
+	if e.nodeInstances, err = tasks.GetInstancesWithContext(ctx, e.kv, e.taskID, e.deploymentID, e.NodeName); err != nil {
 		return err
 	}
 	return nil
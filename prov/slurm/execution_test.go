@@ -0,0 +1,85 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/tasks"
+)
+
+func testSlurmJobDependencies(t *testing.T, kv *api.KV, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := loadTestYaml(t, kv)
+	e := &executionCommon{kv: kv, cfg: cfg, deploymentID: deploymentID, NodeName: "Compute"}
+
+	_, err := e.getJobDependencies()
+	require.Error(t, err, "expecting an error as jobs Compute depends on are not submitted")
+
+	err = deployments.SetInstanceAttribute(deploymentID, "Preprocess", "0", "job_id", "1234")
+	require.NoError(t, err)
+	err = deployments.SetInstanceAttribute(deploymentID, "Download", "0", "job_id", "1235")
+	require.NoError(t, err)
+
+	dependencies, err := e.getJobDependencies()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"afterok:1234", "afterany:1235"}, dependencies)
+
+	e.NodeName = "Preprocess"
+	dependencies, err = e.getJobDependencies()
+	require.NoError(t, err)
+	assert.Len(t, dependencies, 0)
+
+	array, err := deployments.GetNodePropertyValue(kv, deploymentID, "Compute", "slurm_options", "array")
+	require.NoError(t, err)
+	require.NotNil(t, array)
+	assert.Equal(t, "0-15%4", array.RawString())
+}
+
+func testSlurmResolveInstancesOfBatch(t *testing.T, kv *api.KV, cfg config.Configuration) {
+	t.Parallel()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/slurmJobDependencies.yaml")
+	require.NoError(t, err)
+	e := &executionCommon{kv: kv, cfg: cfg, deploymentID: deploymentID, taskID: "taskBatch", NodeName: "Compute"}
+
+	err = e.resolveInstances(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0"}, e.nodeInstances)
+
+	// A step running on a batch of instances restricts them in the context
+	ctx := tasks.WithNodeInstances(context.Background(), "Compute", []string{"2", "3"})
+	err = e.resolveInstances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, e.nodeInstances)
+}
+
+func TestBuildJobOpts(t *testing.T) {
+	t.Parallel()
+	e := &executionCommon{jobInfo: &jobInfo{Name: "myjob", Nodes: 1}}
+	assert.Equal(t, " --job-name=myjob --nodes=1", e.buildJobOpts())
+
+	e.jobInfo.Array = "0-15%4"
+	e.jobInfo.Dependencies = []string{"afterok:1234", "afterany:1235"}
+	assert.Equal(t, " --job-name=myjob --nodes=1 --array=0-15%4 --dependency=afterok:1234,afterany:1235", e.buildJobOpts())
+}
@@ -45,7 +45,7 @@ type allocationResponse struct {
 const reSallocPending = `^salloc: Pending job allocation (\d+)`
 const reSallocGranted = `^salloc: Granted job allocation (\d+)`
 
-func getJobExecution(conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (execution, error) {
+func getJobExecution(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (execution, error) {
 	consulClient, err := conf.GetConsulClient()
 	if err != nil {
 		return nil, err
@@ -58,13 +58,13 @@ func getJobExecution(conf config.Configuration, taskID, deploymentID, nodeName s
 	if !isJob {
 		return nil, errors.Errorf("operation %q supported only for nodes derived from %q", operation.Name, "yorc.nodes.slurm.Job")
 	}
-	return newExecution(kv, conf, taskID, deploymentID, nodeName, stepName, operation)
+	return newExecution(ctx, kv, conf, taskID, deploymentID, nodeName, stepName, operation)
 }
 
 func (e *defaultExecutor) ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*prov.Action, time.Duration, error) {
 	log.Debugf("Slurm defaultExecutor: Execute the operation async: %+v", operation)
 
-	exec, err := getJobExecution(conf, taskID, deploymentID, nodeName, operation, stepName)
+	exec, err := getJobExecution(ctx, conf, taskID, deploymentID, nodeName, operation, stepName)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -74,7 +74,7 @@ func (e *defaultExecutor) ExecAsyncOperation(ctx context.Context, conf config.Co
 func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
 	log.Debugf("Slurm defaultExecutor: Execute the operation: %+v", operation)
 
-	exec, err := getJobExecution(conf, taskID, deploymentID, nodeName, operation, "")
+	exec, err := getJobExecution(ctx, conf, taskID, deploymentID, nodeName, operation, "")
 	if err != nil {
 		return err
 	}
@@ -88,7 +88,7 @@ func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configura
 	}
 	kv := consulClient.KV()
 
-	instances, err := tasks.GetInstances(kv, taskID, deploymentID, nodeName)
+	instances, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
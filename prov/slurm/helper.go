@@ -16,9 +16,11 @@ package slurm
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -338,6 +340,142 @@ func parseJobInfo(r io.Reader) (map[string]string, error) {
 	return data, nil
 }
 
+// parseJobsInfo parses the output of a scontrol show job command which contains
+// a record per job separated by empty lines (array jobs have a record per task)
+func parseJobsInfo(r io.Reader) ([]map[string]string, error) {
+	records := make([]map[string]string, 0)
+	var record bytes.Buffer
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) != "" {
+			record.WriteString(line + "\n")
+			continue
+		}
+		if record.Len() > 0 {
+			data, err := parseJobInfo(&record)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, data)
+			record.Reset()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "An error occurred scanning job information")
+	}
+	if record.Len() > 0 {
+		data, err := parseJobInfo(&record)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, data)
+	}
+	return records, nil
+}
+
+// jobDisplayID returns the ID of a job as displayed by Slurm, array tasks being identified as <array job id>_<task id>
+func jobDisplayID(info map[string]string) string {
+	taskID, ok := info["ArrayTaskId"]
+	if !ok || taskID == "" {
+		return info["JobId"]
+	}
+	if _, err := strconv.Atoi(taskID); err != nil {
+		// Pending tasks are grouped in a single record
+		taskID = "[" + taskID + "]"
+	}
+	return fmt.Sprintf("%s_%s", info["ArrayJobId"], taskID)
+}
+
+// countArrayTasks returns the number of tasks described by an array indexes specification like "1,3,5-11:2%4"
+func countArrayTasks(spec string) int {
+	spec = strings.SplitN(spec, "%", 2)[0]
+	var count int
+	for _, part := range strings.Split(spec, ",") {
+		step := 1
+		if stepParts := strings.SplitN(part, ":", 2); len(stepParts) == 2 {
+			s, err := strconv.Atoi(stepParts[1])
+			if err != nil || s <= 0 {
+				return 1
+			}
+			part, step = stepParts[0], s
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 1
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return 1
+			}
+		}
+		count += (last-first)/step + 1
+	}
+	return count
+}
+
+// aggregateJobsStates returns the global state of a job from the records of its tasks:
+// the job is running while at least one task is still active, otherwise it is completed
+// if all tasks completed or in the state of the first task that did not complete
+func aggregateJobsStates(jobsInfo []map[string]string) string {
+	var active, failed string
+	for _, info := range jobsInfo {
+		state := info["JobState"]
+		switch state {
+		case "COMPLETED":
+		case "RUNNING", "PENDING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
+			if active == "" || active == "PENDING" || state == "RUNNING" {
+				active = state
+			}
+		default:
+			if failed == "" {
+				failed = state
+			}
+		}
+	}
+	if active != "" {
+		return active
+	}
+	if failed != "" {
+		return failed
+	}
+	return "COMPLETED"
+}
+
+// summarizeJobsStates returns the number of tasks by state (ex: "COMPLETED:3 PENDING:2 RUNNING:1")
+func summarizeJobsStates(jobsInfo []map[string]string) string {
+	counts := make(map[string]int)
+	for _, info := range jobsInfo {
+		nb := 1
+		if taskID, ok := info["ArrayTaskId"]; ok && taskID != "" {
+			nb = countArrayTasks(taskID)
+		}
+		counts[info["JobState"]] += nb
+	}
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	summary := make([]string, len(states))
+	for i, state := range states {
+		summary[i] = fmt.Sprintf("%s:%d", state, counts[state])
+	}
+	return strings.Join(summary, " ")
+}
+
+// isDependencyNeverSatisfied returns true if a job is pending on dependencies that will never be satisfied
+func isDependencyNeverSatisfied(jobsInfo []map[string]string) bool {
+	for _, info := range jobsInfo {
+		if info["JobState"] == "PENDING" && info["Reason"] == "DependencyNeverSatisfied" {
+			return true
+		}
+	}
+	return false
+}
+
 func parseJobID(str string, regexp *regexp.Regexp) (string, error) {
 	subMatch := regexp.FindStringSubmatch(str)
 	if subMatch != nil && len(subMatch) == 2 {
@@ -376,7 +514,7 @@ func parseKeyValue(str string) (bool, string, string) {
 	return false, "", ""
 }
 
-func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error) {
+func getJobsInfo(client sshutil.Client, jobID string) ([]map[string]string, error) {
 	cmd := fmt.Sprintf("scontrol show job %s", jobID)
 	output, err := client.RunCommand(cmd)
 	if err != nil {
@@ -384,7 +522,7 @@ func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error)
 	}
 	out := strings.Trim(output, "\" \t\n\x00")
 	if out != "" {
-		return parseJobInfo(strings.NewReader(out))
+		return parseJobsInfo(strings.NewReader(out))
 	}
 	return nil, &noJobFound{msg: fmt.Sprintf("no information found for job with id:%q", jobID)}
 }
@@ -350,3 +350,70 @@ func TestParseJob(t *testing.T) {
 	require.Equal(t, "test-salloc-Environment", info["JobName"], "unexpected value for \"JobName\" key")
 	require.Equal(t, "2-19:42:53", info["RunTime"], "unexpected value for \"RunTime\" key")
 }
+
+func TestParseArrayJob(t *testing.T) {
+	t.Parallel()
+	data, err := os.Open("testdata/scontrol_array.txt")
+	require.Nil(t, err, "unexpected error while opening test file")
+	infos, err := parseJobsInfo(data)
+	require.Nil(t, err, "unexpected error while parsing jobs info")
+	require.Len(t, infos, 3)
+	require.Equal(t, "RUNNING", infos[0]["JobState"])
+	require.Equal(t, "6260_1", jobDisplayID(infos[0]))
+	require.Equal(t, "/home_nfs/john/slurm-6260_0.out", infos[1]["StdOut"])
+	require.Equal(t, "6260_0", jobDisplayID(infos[1]))
+	require.Equal(t, "6260_[2-9:2%2]", jobDisplayID(infos[2]))
+	require.Equal(t, "RUNNING", aggregateJobsStates(infos))
+	require.Equal(t, "COMPLETED:1 PENDING:4 RUNNING:1", summarizeJobsStates(infos))
+	require.False(t, isDependencyNeverSatisfied(infos))
+
+	data, err = os.Open("testdata/scontrol.txt")
+	require.Nil(t, err, "unexpected error while opening test file")
+	infos, err = parseJobsInfo(data)
+	require.Nil(t, err, "unexpected error while parsing jobs info")
+	require.Len(t, infos, 1)
+	require.Equal(t, "6260", jobDisplayID(infos[0]))
+}
+
+func TestAggregateJobsStates(t *testing.T) {
+	t.Parallel()
+	states := func(s ...string) []map[string]string {
+		infos := make([]map[string]string, len(s))
+		for i := range s {
+			infos[i] = map[string]string{"JobState": s[i]}
+		}
+		return infos
+	}
+	tests := []struct {
+		name   string
+		infos  []map[string]string
+		result string
+	}{
+		{"SingleJob", states("PENDING"), "PENDING"},
+		{"AllCompleted", states("COMPLETED", "COMPLETED"), "COMPLETED"},
+		{"RunningWinsOverPending", states("PENDING", "RUNNING", "COMPLETED"), "RUNNING"},
+		{"ActiveWinsOverFailed", states("FAILED", "PENDING"), "PENDING"},
+		{"FirstFailure", states("COMPLETED", "TIMEOUT", "FAILED"), "TIMEOUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.result, aggregateJobsStates(tt.infos))
+		})
+	}
+}
+
+func TestCountArrayTasks(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 1, countArrayTasks("3"))
+	assert.Equal(t, 16, countArrayTasks("0-15"))
+	assert.Equal(t, 16, countArrayTasks("0-15%4"))
+	assert.Equal(t, 5, countArrayTasks("1,3,5-7"))
+	assert.Equal(t, 4, countArrayTasks("1-7:2"))
+	assert.Equal(t, 1, countArrayTasks("malformed"))
+}
+
+func TestIsDependencyNeverSatisfied(t *testing.T) {
+	t.Parallel()
+	assert.False(t, isDependencyNeverSatisfied([]map[string]string{{"JobState": "PENDING", "Reason": "Dependency"}}))
+	assert.True(t, isDependencyNeverSatisfied([]map[string]string{{"JobState": "PENDING", "Reason": "DependencyNeverSatisfied"}}))
+}
@@ -97,61 +97,55 @@ func (o *actionOperator) monitorJob(ctx context.Context, cfg config.Configuratio
 		return true, err
 	}
 
-	info, err := getJobInfo(sshClient, actionData.jobID)
+	jobsInfo, err := getJobsInfo(sshClient, actionData.jobID)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to get job info with jobID:%q", actionData.jobID)
 	}
 
-	var mess string
-	if info["Reason"] != "None" {
-		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Reason:%s, Execution Time:%s", info["JobName"], info["JobId"], info["JobState"], info["Reason"], info["RunTime"])
-	} else {
-		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Execution Time:%s", info["JobName"], info["JobId"], info["JobState"], info["RunTime"])
+	// Array jobs have a record per task
+	for _, info := range jobsInfo {
+		o.logJobInfo(ctx, deploymentID, info, sshClient)
 	}
-	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(mess)
-
-	stdOut, existStdOut := info["StdOut"]
-	stdErr, existStdErr := info["StdErr"]
-	if existStdOut && existStdErr && stdOut == stdErr {
-		o.logFile(ctx, deploymentID, stdOut, "StdOut/StdErr", sshClient)
-	} else {
-		if existStdOut {
-			o.logFile(ctx, deploymentID, stdOut, "StdOut", sshClient)
-		}
-		if existStdErr {
-			o.logFile(ctx, deploymentID, stdErr, "StdErr", sshClient)
-		}
-	}
-
-	// See default output if nothing is specified here
-	if !existStdOut && !existStdErr {
-		o.logFile(ctx, deploymentID, fmt.Sprintf("slurm-%s.out", actionData.jobID), "StdOut/Stderr", sshClient)
+	jobState := aggregateJobsStates(jobsInfo)
+	if len(jobsInfo) > 1 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(
+			fmt.Sprintf("Job array ID:%s, State:%s, Tasks states:%s", actionData.jobID, jobState, summarizeJobsStates(jobsInfo)))
 	}
 
 	previousJobState, err := deployments.GetInstanceStateString(consulutil.GetKV(), deploymentID, action.Data["nodeName"], "0")
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to get instance state for job %q", actionData.jobID)
 	}
-	if previousJobState != info["JobState"] {
-		deployments.SetInstanceStateStringWithContextualLogs(ctx, consulutil.GetKV(), deploymentID, action.Data["nodeName"], "0", info["JobState"])
+	if previousJobState != jobState {
+		deployments.SetInstanceStateStringWithContextualLogs(ctx, consulutil.GetKV(), deploymentID, action.Data["nodeName"], "0", jobState)
 	}
 
 	// See if monitoring must be continued and set job state if terminated
-	switch info["JobState"] {
+	switch jobState {
 	case "COMPLETED":
 		// job has been done successfully : unregister monitoring
 		deregister = true
 	case "RUNNING", "PENDING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
 		// job's still running or its state is about to be set definitively: monitoring is keeping on (deregister stays false)
+		if isDependencyNeverSatisfied(jobsInfo) {
+			// A job this one depends on finished in a way that will never satisfy the dependency so the job would stay pending forever
+			deregister = true
+			if errCancel := cancelJobID(actionData.jobID, sshClient); errCancel != nil {
+				log.Printf("an error:%+v occurred during cancelling job %q", errCancel, actionData.jobID)
+			}
+			err = errors.Errorf("job with ID:%q has been cancelled as its dependencies can never be satisfied", actionData.jobID)
+		}
 	default:
 		// Other cases as FAILED, CANCELLED, STOPPED, SUSPENDED, TIMEOUT, etc : error is return with job state and job info is logged
-		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", info))
 		deregister = true
 		// Log event containing all the slurm information
-
-		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", info))
+		for _, info := range jobsInfo {
+			if info["JobState"] != "COMPLETED" {
+				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", info))
+			}
+		}
 		// Error to be returned
-		err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q", actionData.jobID, info["JobState"])
+		err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q", actionData.jobID, jobState)
 	}
 
 	// cleanup except if error occurred or explicitly specified in config
@@ -163,6 +157,35 @@ func (o *actionOperator) monitorJob(ctx context.Context, cfg config.Configuratio
 	return deregister, err
 }
 
+// logJobInfo logs the state and the output files of a job (or a task of an array job)
+func (o *actionOperator) logJobInfo(ctx context.Context, deploymentID string, info map[string]string, sshClient *sshutil.SSHClient) {
+	var mess string
+	if info["Reason"] != "None" {
+		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Reason:%s, Execution Time:%s", info["JobName"], jobDisplayID(info), info["JobState"], info["Reason"], info["RunTime"])
+	} else {
+		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Execution Time:%s", info["JobName"], jobDisplayID(info), info["JobState"], info["RunTime"])
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(mess)
+
+	stdOut, existStdOut := info["StdOut"]
+	stdErr, existStdErr := info["StdErr"]
+	if existStdOut && existStdErr && stdOut == stdErr {
+		o.logFile(ctx, deploymentID, stdOut, "StdOut/StdErr", sshClient)
+	} else {
+		if existStdOut {
+			o.logFile(ctx, deploymentID, stdOut, "StdOut", sshClient)
+		}
+		if existStdErr {
+			o.logFile(ctx, deploymentID, stdErr, "StdErr", sshClient)
+		}
+	}
+
+	// See default output if nothing is specified here
+	if !existStdOut && !existStdErr {
+		o.logFile(ctx, deploymentID, fmt.Sprintf("slurm-%s.out", jobDisplayID(info)), "StdOut/Stderr", sshClient)
+	}
+}
+
 func (o *actionOperator) removeArtifacts(actionData *actionData, sshClient *sshutil.SSHClient) {
 	for _, art := range actionData.artifacts {
 		if art != "" {
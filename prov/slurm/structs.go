@@ -59,4 +59,6 @@ type jobInfo struct {
 	Command                string            `json:"command,omitempty"`
 	WorkingDir             string            `json:"working_directory,omitempty"`
 	Artifacts              []string          `json:"artifacts,omitempty"`
+	Array                  string            `json:"array,omitempty"`
+	Dependencies           []string          `json:"dependencies,omitempty"`
 }
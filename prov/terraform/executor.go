@@ -55,7 +55,7 @@ func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configura
 	}
 	kv := consulClient.KV()
 
-	instances, err := tasks.GetInstances(kv, taskID, deploymentID, nodeName)
+	instances, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
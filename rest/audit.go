@@ -0,0 +1,140 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ystia/yorc/v3/audit"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const auditLookupKey contextKey = 3
+
+// ndjsonContentType is the content type used to export audit records as JSON lines
+const ndjsonContentType = "application/x-ndjson"
+
+// auditHandler records an audit trail of the requests modifying the state of Yorc (every method except GET, HEAD and OPTIONS)
+//
+// The record is made available to inner handlers through the request context, this allows the authHandler to fill
+// the authenticated principal.
+func (s *Server) auditHandler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		record := &audit.Record{
+			Timestamp: time.Now(),
+			ClientIP:  clientIP(r),
+			Method:    r.Method,
+			Route:     r.URL.Path,
+		}
+		writer := &statusRecorderResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r.WithContext(context.WithValue(r.Context(), auditLookupKey, record)))
+
+		record.Status = writer.status
+		if record.Status == 0 {
+			record.Status = http.StatusOK
+		}
+		record.Outcome = audit.OutcomeFromStatus(record.Status)
+		if params, ok := r.Context().Value(paramsLookupKey).(httprouter.Params); ok && strings.HasPrefix(r.URL.Path, "/deployments/") {
+			record.DeploymentID = params.ByName("id")
+			record.TaskID = params.ByName("taskId")
+		}
+		// Tasks created by a request (and deployments IDs generated on creation) are only known through the Location header
+		if deploymentID, taskID := parseTaskLocation(writer.Header().Get("Location")); taskID != "" {
+			record.DeploymentID = deploymentID
+			record.TaskID = taskID
+		}
+		if err := audit.Store(s.consulClient.KV(), *record); err != nil {
+			log.Printf("[WARN] Failed to store audit record of [%s] %q: %v", r.Method, r.URL.Path, err)
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
+// auditRecordFromContext returns the audit record stored in the given context if any
+func auditRecordFromContext(ctx context.Context) *audit.Record {
+	record, _ := ctx.Value(auditLookupKey).(*audit.Record)
+	return record
+}
+
+// parseTaskLocation extracts the deployment and the task IDs from a location of the form /deployments/<id>/tasks/<taskId>
+func parseTaskLocation(location string) (string, string) {
+	parts := strings.Split(strings.Trim(location, "/"), "/")
+	if len(parts) != 4 || parts[0] != "deployments" || parts[2] != "tasks" {
+		return "", ""
+	}
+	return parts[1], parts[3]
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (s *Server) listAuditRecordsHandler(w http.ResponseWriter, r *http.Request) {
+	accept := r.Header.Get("Accept")
+	if accept != "application/json" && accept != ndjsonContentType {
+		writeError(w, r, &Error{"not_acceptable", http.StatusNotAcceptable, "Not Acceptable", "Accept header must be set to 'application/json' or '" + ndjsonContentType + "'."})
+		return
+	}
+	var filter audit.Filter
+	var err error
+	query := r.URL.Query()
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			writeError(w, r, newBadRequestParameter("from", err))
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			writeError(w, r, newBadRequestParameter("to", err))
+			return
+		}
+	}
+	filter.DeploymentID = query.Get("deployment")
+
+	records, err := audit.Query(s.consulClient.KV(), filter)
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(records) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if accept == ndjsonContentType {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		jEnc := json.NewEncoder(w)
+		for _, record := range records {
+			jEnc.Encode(record)
+		}
+		return
+	}
+	encodeJSONResponse(w, r, AuditRecordsCollection{Records: records})
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/audit"
+)
+
+func TestParseTaskLocation(t *testing.T) {
+	tests := []struct {
+		location     string
+		deploymentID string
+		taskID       string
+	}{
+		{"/deployments/dep/tasks/t1", "dep", "t1"},
+		{"/deployments/dep/schedules/s1", "", ""},
+		{"/infra_usage/slurm/tasks/t1", "", ""},
+		{"/webhooks/w1", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		deploymentID, taskID := parseTaskLocation(tt.location)
+		assert.Equal(t, tt.deploymentID, deploymentID, "unexpected deployment for location %q", tt.location)
+		assert.Equal(t, tt.taskID, taskID, "unexpected task for location %q", tt.location)
+	}
+}
+
+func testAuditHandlers(t *testing.T, client *api.Client) {
+	s := &Server{
+		router:       newRouter(),
+		consulClient: client,
+		authenticator: &authenticator{tokens: map[string]Principal{
+			"viewer-token": {Name: "viewer", Role: RoleViewer},
+			"admin-token":  {Name: "admin", Role: RoleAdmin},
+		}},
+	}
+	s.registerHandlers()
+	do := func(method, url, token, accept string) *http.Response {
+		req := httptest.NewRequest(method, url, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	// Forbidden for a viewer
+	resp := do(http.MethodDelete, "/deployments/auditDep?purge", "viewer-token", "")
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	// Not found for an admin
+	resp = do(http.MethodDelete, "/deployments/auditDep", "admin-token", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	// Reads are not audited
+	resp = do(http.MethodGet, "/deployments/auditDep", "admin-token", "application/json")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = do(http.MethodGet, "/audit?deployment=auditDep", "viewer-token", "application/json")
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = do(http.MethodGet, "/audit?deployment=auditDep", "admin-token", "application/json")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var col AuditRecordsCollection
+	require.NoError(t, json.Unmarshal(body, &col))
+	require.Len(t, col.Records, 2)
+	assert.Equal(t, "viewer", col.Records[0].Principal)
+	assert.Equal(t, http.MethodDelete, col.Records[0].Method)
+	assert.Equal(t, "/deployments/auditDep", col.Records[0].Route)
+	assert.Equal(t, http.StatusForbidden, col.Records[0].Status)
+	assert.Equal(t, audit.OutcomeFailure, col.Records[0].Outcome)
+	assert.Equal(t, "192.0.2.1", col.Records[0].ClientIP)
+	assert.Equal(t, "admin", col.Records[1].Principal)
+	assert.Equal(t, http.StatusNotFound, col.Records[1].Status)
+
+	resp = do(http.MethodGet, "/audit?deployment=auditDep", "admin-token", ndjsonContentType)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ndjsonContentType, resp.Header.Get("Content-Type"))
+	scanner := bufio.NewScanner(resp.Body)
+	var lines int
+	for scanner.Scan() {
+		var record audit.Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "auditDep", record.DeploymentID)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+
+	resp = do(http.MethodGet, "/audit?deployment=auditDep&from=2100-01-01T00:00:00Z", "admin-token", "application/json")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = do(http.MethodGet, "/audit?from=yesterday", "admin-token", "application/json")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = do(http.MethodGet, "/audit", "admin-token", "text/plain")
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
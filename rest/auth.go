@@ -0,0 +1,160 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/config"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const principalLookupKey contextKey = 2
+
+// A Principal is an authenticated REST API client
+type Principal struct {
+	// Name identifies the client, this is the token name for static tokens or the subject for JWT
+	Name string `json:"name"`
+	// Role is the highest role granted to the client
+	Role Role `json:"role"`
+}
+
+type authenticator struct {
+	tokens     map[string]Principal
+	keys       *jwks
+	issuer     string
+	audience   string
+	rolesClaim string
+}
+
+// newAuthenticator creates an authenticator from the given configuration.
+//
+// It returns a nil authenticator if authentication is not enabled.
+func newAuthenticator(cfg config.Auth) (*authenticator, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	a := &authenticator{
+		tokens:     make(map[string]Principal, len(cfg.Tokens)),
+		issuer:     cfg.JWTIssuer,
+		audience:   cfg.JWTAudience,
+		rolesClaim: cfg.JWTRolesClaim,
+	}
+	if a.rolesClaim == "" {
+		a.rolesClaim = config.DefaultJWTRolesClaim
+	}
+	for i, t := range cfg.Tokens {
+		if t.Token == "" {
+			return nil, errors.Errorf("empty value for API token #%d (%q)", i, t.Name)
+		}
+		role, err := ParseRole(strings.ToLower(t.Role))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid role for API token #%d (%q)", i, t.Name)
+		}
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("token-%d", i)
+		}
+		a.tokens[t.Token] = Principal{Name: name, Role: role}
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = keys
+	}
+	return a, nil
+}
+
+// authenticate checks the bearer token of the given request and returns the corresponding Principal
+func (a *authenticator) authenticate(r *http.Request) (*Principal, error) {
+	authz := r.Header.Get("Authorization")
+	if authz == "" {
+		return nil, errors.New("missing Authorization header")
+	}
+	parts := strings.SplitN(authz, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, errors.New("unsupported Authorization scheme, expecting a Bearer token")
+	}
+	token := strings.TrimSpace(parts[1])
+	for t, p := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			principal := p
+			return &principal, nil
+		}
+	}
+	if a.keys != nil && strings.Count(token, ".") == 2 {
+		return a.authenticateJWT(token)
+	}
+	return nil, errors.New("invalid token")
+}
+
+func (a *authenticator) authenticateJWT(token string) (*Principal, error) {
+	claims, err := a.keys.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if err = claims.validate(a.issuer, a.audience); err != nil {
+		return nil, err
+	}
+	role, err := claims.highestRole(a.rolesClaim)
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Name: claims.subject(), Role: role}, nil
+}
+
+// authHandler returns a middleware that rejects requests that are not authenticated
+// or whose principal was not granted at least the given role.
+func (s *Server) authHandler(role Role) func(http.Handler) http.Handler {
+	m := func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if s.authenticator == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			p, err := s.authenticator.authenticate(r)
+			if record := auditRecordFromContext(r.Context()); record != nil && p != nil {
+				record.Principal = p.Name
+			}
+			if err != nil {
+				log.Debugf("[%s] %q rejected authentication: %v", r.Method, r.URL.String(), err)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="yorc"`)
+				writeError(w, r, errUnauthorized)
+				return
+			}
+			if p.Role < role {
+				writeError(w, r, newForbiddenError(fmt.Sprintf("Role %q is required for this operation, %q is granted only %q.", role, p.Name, p.Role)))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalLookupKey, p)))
+		}
+		return http.HandlerFunc(fn)
+	}
+	return m
+}
+
+// principalFromContext returns the authenticated Principal stored in the given context if any
+func principalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalLookupKey).(*Principal)
+	return p
+}
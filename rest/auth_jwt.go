@@ -0,0 +1,318 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	// Register hash functions used by JWT signing algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// jwtClockSkew is the tolerance applied when checking JWT time-based claims
+const jwtClockSkew = 30 * time.Second
+
+// jwk is a JSON Web Key as defined in RFC 7517 (only RSA and EC public keys are supported)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	keys map[string]crypto.PublicKey
+	// ordered list of keys used when a token has no kid
+	all []crypto.PublicKey
+}
+
+func loadJWKSFile(path string) (*jwks, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read JWKS file %q", path)
+	}
+	return parseJWKS(b)
+}
+
+func parseJWKS(b []byte) (*jwks, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS")
+	}
+	res := &jwks{keys: make(map[string]crypto.PublicKey)}
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key #%d (kid: %q) in JWKS", i, k.Kid)
+		}
+		if k.Kid != "" {
+			res.keys[k.Kid] = pub
+		}
+		res.all = append(res.all, pub)
+	}
+	if len(res.all) == 0 {
+		return nil, errors.New("no signing key found in JWKS")
+	}
+	return res, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported elliptic curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC x coordinate")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC y coordinate")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %q", k.Kty)
+}
+
+type jwtClaims map[string]interface{}
+
+// verify checks the signature of a compact serialized JWT and returns its claims
+func (ks *jwks) verify(token string) (jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "invalid JWT header")
+	}
+	hash, err := jwtHash(header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JWT signature encoding")
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	candidates := ks.all
+	if header.Kid != "" {
+		k, ok := ks.keys[header.Kid]
+		if !ok {
+			return nil, errors.Errorf("unknown JWT key id %q", header.Kid)
+		}
+		candidates = []crypto.PublicKey{k}
+	}
+	verified := false
+	for _, k := range candidates {
+		if verifyJWTSignature(header.Alg, hash, k, digest, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid JWT signature")
+	}
+	claims := make(jwtClaims)
+	if err = decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "invalid JWT payload")
+	}
+	return claims, nil
+}
+
+func decodeJWTSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func jwtHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	}
+	return 0, errors.Errorf("unsupported JWT signing algorithm %q", alg)
+}
+
+func verifyJWTSignature(alg string, hash crypto.Hash, key crypto.PublicKey, digest, sig []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+		case "PS":
+			return rsa.VerifyPSS(k, hash, digest, sig, nil) == nil
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			return false
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+func (c jwtClaims) numericDate(name string) (time.Time, bool) {
+	v, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+func (c jwtClaims) stringSlice(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(strings.Replace(v, ",", " ", -1))
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, i := range v {
+			if s, ok := i.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// validate checks the registered claims of a JWT
+func (c jwtClaims) validate(issuer, audience string) error {
+	now := time.Now()
+	exp, ok := c.numericDate("exp")
+	if !ok {
+		return errors.New("JWT has no expiration time")
+	}
+	if now.After(exp.Add(jwtClockSkew)) {
+		return errors.New("JWT is expired")
+	}
+	if nbf, ok := c.numericDate("nbf"); ok && now.Add(jwtClockSkew).Before(nbf) {
+		return errors.New("JWT is not valid yet")
+	}
+	if issuer != "" {
+		if iss, _ := c["iss"].(string); iss != issuer {
+			return errors.Errorf("unexpected JWT issuer %q", iss)
+		}
+	}
+	if audience != "" {
+		found := false
+		for _, aud := range c.stringSlice("aud") {
+			if aud == audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("JWT audience does not contain %q", audience)
+		}
+	}
+	return nil
+}
+
+// highestRole returns the highest known Role listed in the given claim
+func (c jwtClaims) highestRole(claim string) (Role, error) {
+	var res Role
+	found := false
+	for _, r := range c.stringSlice(claim) {
+		role, err := ParseRole(strings.ToLower(r))
+		if err != nil {
+			// Ignore roles not related to Yorc
+			continue
+		}
+		if !found || role > res {
+			res = role
+			found = true
+		}
+	}
+	if !found {
+		return res, errors.Errorf("no Yorc role found in JWT claim %q", claim)
+	}
+	return res, nil
+}
+
+func (c jwtClaims) subject() string {
+	for _, n := range []string{"preferred_username", "email", "sub"} {
+		if s, ok := c[n].(string); ok && s != "" {
+			return s
+		}
+	}
+	return "unknown"
+}
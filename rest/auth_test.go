@@ -0,0 +1,180 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/config"
+)
+
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	h, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func testJWKS(t *testing.T, key *rsa.PrivateKey, kid string) *jwks {
+	t.Helper()
+	set := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":%q,"use":"sig","n":%q,"e":%q}]}`,
+		kid,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	ks, err := parseJWKS([]byte(set))
+	require.NoError(t, err)
+	return ks
+}
+
+func TestAuthHandler(t *testing.T) {
+	t.Parallel()
+	a, err := newAuthenticator(config.Auth{Tokens: []config.AuthToken{
+		{Name: "dashboard", Token: "viewer-token", Role: "viewer"},
+		{Name: "ci", Token: "operator-token", Role: "Operator"},
+	}})
+	require.NoError(t, err)
+	s := &Server{authenticator: a}
+
+	tests := []struct {
+		name       string
+		authz      string
+		role       Role
+		wantStatus int
+	}{
+		{"NoToken", "", RoleViewer, http.StatusUnauthorized},
+		{"BadScheme", "Basic dXNlcjpwYXNz", RoleViewer, http.StatusUnauthorized},
+		{"UnknownToken", "Bearer nope", RoleViewer, http.StatusUnauthorized},
+		{"ViewerOnViewerRoute", "Bearer viewer-token", RoleViewer, http.StatusOK},
+		{"ViewerOnOperatorRoute", "Bearer viewer-token", RoleOperator, http.StatusForbidden},
+		{"OperatorOnOperatorRoute", "Bearer operator-token", RoleOperator, http.StatusOK},
+		{"OperatorOnAdminRoute", "Bearer operator-token", RoleAdmin, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := s.authHandler(tt.role)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NotNil(t, principalFromContext(r.Context()))
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest("GET", "/deployments", nil)
+			if tt.authz != "" {
+				req.Header.Set("Authorization", tt.authz)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthHandlerDisabled(t *testing.T) {
+	t.Parallel()
+	a, err := newAuthenticator(config.Auth{})
+	require.NoError(t, err)
+	require.Nil(t, a)
+	s := &Server{authenticator: a}
+	h := s.authHandler(RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "/deployments/dep", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNewAuthenticatorInvalidRole(t *testing.T) {
+	t.Parallel()
+	_, err := newAuthenticator(config.Auth{Tokens: []config.AuthToken{{Token: "t", Role: "superuser"}}})
+	require.Error(t, err)
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	a := &authenticator{
+		keys:       testJWKS(t, key, "k1"),
+		issuer:     "https://idp.example.com",
+		audience:   "yorc",
+		rolesClaim: config.DefaultJWTRolesClaim,
+	}
+	now := time.Now().Unix()
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":                "https://idp.example.com",
+			"aud":                []string{"account", "yorc"},
+			"sub":                "1234",
+			"preferred_username": "jdoe",
+			"exp":                now + 300,
+			"roles":              []string{"offline_access", "viewer", "operator"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		key      *rsa.PrivateKey
+		kid      string
+		mutate   func(map[string]interface{})
+		wantErr  bool
+		wantRole Role
+	}{
+		{"Valid", key, "k1", func(map[string]interface{}) {}, false, RoleOperator},
+		{"ValidNoKid", key, "", func(map[string]interface{}) {}, false, RoleOperator},
+		{"RolesAsString", key, "k1", func(c map[string]interface{}) { c["roles"] = "admin" }, false, RoleAdmin},
+		{"WrongKey", otherKey, "k1", func(map[string]interface{}) {}, true, RoleViewer},
+		{"UnknownKid", key, "k2", func(map[string]interface{}) {}, true, RoleViewer},
+		{"Expired", key, "k1", func(c map[string]interface{}) { c["exp"] = now - 3600 }, true, RoleViewer},
+		{"NoExpiration", key, "k1", func(c map[string]interface{}) { delete(c, "exp") }, true, RoleViewer},
+		{"WrongIssuer", key, "k1", func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, true, RoleViewer},
+		{"WrongAudience", key, "k1", func(c map[string]interface{}) { c["aud"] = "other" }, true, RoleViewer},
+		{"NoYorcRole", key, "k1", func(c map[string]interface{}) { c["roles"] = []string{"offline_access"} }, true, RoleViewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.mutate(claims)
+			req := httptest.NewRequest("GET", "/deployments", nil)
+			req.Header.Set("Authorization", "Bearer "+signTestJWT(t, tt.key, tt.kid, claims))
+			p, err := a.authenticate(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "jdoe", p.Name)
+			require.Equal(t, tt.wantRole, p.Role)
+		})
+	}
+}
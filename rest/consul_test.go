@@ -73,5 +73,11 @@ func TestRunConsulRestPackageTests(t *testing.T) {
 		t.Run("testSSLRest", func(t *testing.T) {
 			testSSLREST(t, client, srv)
 		})
+		t.Run("testAuditHandlers", func(t *testing.T) {
+			testAuditHandlers(t, client)
+		})
+		t.Run("testCheckReportsHandlers", func(t *testing.T) {
+			testCheckReportsHandlers(t, client)
+		})
 	})
 }
@@ -0,0 +1,111 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/prov/monitoring"
+)
+
+func (s *Server) listCheckReportsHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+	nodeName := params.ByName("nodeName")
+
+	if !s.deploymentExists(w, r, deploymentID) {
+		return
+	}
+
+	reports, err := monitoring.ListCheckReports(s.consulClient.KV(), func(cr monitoring.CheckReport) bool {
+		return cr.DeploymentID == deploymentID && (nodeName == "" || cr.NodeName == nodeName)
+	})
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(reports) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].NodeName != reports[j].NodeName {
+			return reports[i].NodeName < reports[j].NodeName
+		}
+		return reports[i].Instance < reports[j].Instance
+	})
+
+	reportsCol := CheckReportsCollection{Reports: make([]CheckReport, len(reports))}
+	for i, report := range reports {
+		reportsCol.Reports[i] = newCheckReport(report)
+	}
+	encodeJSONResponse(w, r, reportsCol)
+}
+
+func (s *Server) getCheckReportHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+	nodeName := params.ByName("nodeName")
+	instanceID := params.ByName("instanceId")
+
+	if !s.deploymentExists(w, r, deploymentID) {
+		return
+	}
+
+	reports, err := monitoring.ListCheckReports(s.consulClient.KV(), func(cr monitoring.CheckReport) bool {
+		return cr.DeploymentID == deploymentID && cr.NodeName == nodeName && cr.Instance == instanceID
+	})
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(reports) == 0 {
+		writeError(w, r, errNotFound)
+		return
+	}
+	encodeJSONResponse(w, r, newCheckReport(reports[0]))
+}
+
+func newCheckReport(report monitoring.CheckReport) CheckReport {
+	nodePath := path.Join("/deployments", report.DeploymentID, "nodes", report.NodeName)
+	cr := CheckReport{
+		NodeName: report.NodeName,
+		Instance: report.Instance,
+		Status:   strings.ToLower(report.Status.String()),
+		Type:     strings.ToLower(report.CheckType.String()),
+		Message:  report.Message,
+		Links: []AtomLink{
+			newAtomLink(LinkRelDeployment, path.Join("/deployments", report.DeploymentID)),
+			newAtomLink(LinkRelNode, nodePath),
+			newAtomLink(LinkRelInstance, path.Join(nodePath, "instances", report.Instance)),
+		},
+	}
+	if report.TimeInterval > 0 {
+		cr.Interval = report.TimeInterval.String()
+	}
+	if !report.LastTransition.IsZero() {
+		lastTransition := report.LastTransition
+		cr.LastTransition = &lastTransition
+	}
+	return cr
+}
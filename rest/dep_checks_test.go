@@ -0,0 +1,97 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+)
+
+func testCheckReportsHandlers(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	deploymentID := "checksDep"
+	lastTransition := time.Date(2019, 4, 2, 10, 30, 0, 0, time.UTC)
+	storeKV := func(key, value string) {
+		_, err := kv.Put(&api.KVPair{Key: key, Value: []byte(value)}, nil)
+		require.NoError(t, err)
+	}
+	storeKV(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "status"), "DEPLOYED")
+	storeKV(path.Join(consulutil.DeploymentKVPrefix, "checksOtherDep", "status"), "DEPLOYED")
+	for _, id := range []string{deploymentID + ":Compute:0", deploymentID + ":Compute:1", deploymentID + ":Web:0", "checksOtherDep:Compute:0"} {
+		storeKV(path.Join(consulutil.MonitoringKVPrefix, "checks", id, "type"), "tcp")
+		storeKV(path.Join(consulutil.MonitoringKVPrefix, "checks", id, "interval"), "5s")
+		storeKV(path.Join(consulutil.MonitoringKVPrefix, "reports", id, "status"), "passing")
+	}
+	storeKV(path.Join(consulutil.MonitoringKVPrefix, "reports", deploymentID+":Compute:1", "status"), "critical")
+	storeKV(path.Join(consulutil.MonitoringKVPrefix, "reports", deploymentID+":Compute:1", "last_transition"), lastTransition.Format(time.RFC3339Nano))
+	storeKV(path.Join(consulutil.MonitoringKVPrefix, "reports", deploymentID+":Compute:1", "message"), "connection refused")
+
+	get := func(url string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Add("Accept", "application/json")
+		return newTestHTTPRouter(client, req)
+	}
+
+	resp := get("/deployments/" + deploymentID + "/checks")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var col CheckReportsCollection
+	require.NoError(t, json.Unmarshal(body, &col))
+	require.Len(t, col.Reports, 3)
+	require.Equal(t, "Compute", col.Reports[0].NodeName)
+	require.Equal(t, "0", col.Reports[0].Instance)
+	require.Equal(t, "Web", col.Reports[2].NodeName)
+
+	resp = get("/deployments/" + deploymentID + "/checks/Compute")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	col = CheckReportsCollection{}
+	require.NoError(t, json.Unmarshal(body, &col))
+	require.Len(t, col.Reports, 2)
+
+	resp = get("/deployments/" + deploymentID + "/checks/Compute/1")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var report CheckReport
+	require.NoError(t, json.Unmarshal(body, &report))
+	require.Equal(t, "critical", report.Status)
+	require.Equal(t, "tcp", report.Type)
+	require.Equal(t, "5s", report.Interval)
+	require.Equal(t, "connection refused", report.Message)
+	require.NotNil(t, report.LastTransition)
+	require.True(t, lastTransition.Equal(*report.LastTransition))
+
+	resp = get("/deployments/" + deploymentID + "/checks/Unknown")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = get("/deployments/" + deploymentID + "/checks/Compute/2")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = get("/deployments/checksUnknownDep/checks")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tasks/workflow"
+)
+
+// isDryRunRequest checks the dry_run query parameter of a request
+func isDryRunRequest(r *http.Request) (bool, error) {
+	dryRun := r.URL.Query().Get("dry_run")
+	if dryRun == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(dryRun)
+}
+
+func (s *Server) planWorkflow(w http.ResponseWriter, r *http.Request, deploymentID, workflowName string) {
+	plan, err := workflow.BuildPlan(r.Context(), s.consulClient.KV(), deploymentID, workflowName)
+	if err != nil {
+		log.Panic(err)
+	}
+	encodeJSONResponse(w, r, plan)
+}
+
+// planDeployment computes the install plan of a deployment archive without deploying it.
+//
+// The definition is stored under a temporary deployment, marked as a dry-run one, that is purged once the plan is computed.
+// Such a deployment publishes no event nor log and is ignored when listing deployments.
+func (s *Server) planDeployment(w http.ResponseWriter, r *http.Request) {
+	uid := fmt.Sprint(uuid.NewV4())
+	defer s.purgeDryRunDeployment(uid)
+	if err := deployments.MarkDryRunDeployment(s.consulClient.KV(), uid); err != nil {
+		log.Panic(err)
+	}
+	log.Debugf("Analyzing deployment archive for dry-run under temporary id %s", uid)
+
+	yamlFile, archiveErr := unzipArchiveGetTopology(s.config.WorkingDirectory, uid, r, false)
+	if archiveErr != nil {
+		writeError(w, r, archiveErr)
+		return
+	}
+	if err := deployments.StoreDeploymentDefinition(r.Context(), s.consulClient.KV(), uid, yamlFile); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	s.planWorkflow(w, r, uid, "install")
+}
+
+func (s *Server) purgeDryRunDeployment(deploymentID string) {
+	kv := s.consulClient.KV()
+	if _, err := kv.DeleteTree(path.Join(consulutil.DeploymentKVPrefix, deploymentID), nil); err != nil {
+		log.Printf("Failed to cleanup dry-run deployment %q: %v", deploymentID, err)
+	}
+	if err := os.RemoveAll(filepath.Join(s.config.WorkingDirectory, "deployments", deploymentID)); err != nil {
+		log.Printf("Failed to cleanup dry-run deployment %q: %v", deploymentID, err)
+	}
+}
@@ -17,7 +17,6 @@ package rest
 import (
 	"fmt"
 	"net/http"
-	"path"
 	"strconv"
 
 	"github.com/julienschmidt/httprouter"
@@ -80,12 +79,18 @@ func (s *Server) scaleHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data := make(map[string]string)
+	if restErr := addBatchParameters(r, data); restErr != nil {
+		writeError(w, r, restErr)
+		return
+	}
+
 	log.Debugf("Scaling %d instances of node %q", instancesDelta, nodeName)
 	var taskID string
 	if instancesDelta > 0 {
-		taskID, err = s.scaleOut(id, nodeName, uint32(instancesDelta))
+		taskID, err = s.scaleOut(id, nodeName, uint32(instancesDelta), data)
 	} else {
-		taskID, err = s.scaleIn(id, nodeName, uint32(-instancesDelta))
+		taskID, err = s.scaleIn(id, nodeName, uint32(-instancesDelta), data)
 	}
 	if err != nil {
 		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
@@ -102,7 +107,7 @@ func (s *Server) scaleHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
-func (s *Server) scaleOut(id, nodeName string, instancesDelta uint32) (string, error) {
+func (s *Server) scaleOut(id, nodeName string, instancesDelta uint32, data map[string]string) (string, error) {
 	kv := s.consulClient.KV()
 	maxInstances, err := deployments.GetMaxNbInstancesForNode(kv, id, nodeName)
 	if err != nil {
@@ -121,15 +126,10 @@ func (s *Server) scaleOut(id, nodeName string, instancesDelta uint32) (string, e
 		}
 	}
 
-	// Add related workflow, nodeName and instances delta
-	data := make(map[string]string)
-	data["instancesDelta"] = strconv.Itoa(int(instancesDelta))
-	data["workflowName"] = "install"
-	data["nodeName"] = nodeName
-	return s.tasksCollector.RegisterTaskWithData(id, tasks.TaskTypeScaleOut, data)
+	return s.tasksCollector.RegisterScaleOutTask(id, nodeName, instancesDelta, data)
 }
 
-func (s *Server) scaleIn(id, nodeName string, instancesDelta uint32) (string, error) {
+func (s *Server) scaleIn(id, nodeName string, instancesDelta uint32, data map[string]string) (string, error) {
 	kv := s.consulClient.KV()
 
 	minInstances, err := deployments.GetMinNbInstancesForNode(kv, id, nodeName)
@@ -149,18 +149,5 @@ func (s *Server) scaleIn(id, nodeName string, instancesDelta uint32) (string, er
 		}
 	}
 
-	instancesByNodes, err := deployments.SelectNodeStackInstances(kv, id, nodeName, int(instancesDelta))
-	if err != nil {
-		return "", err
-	}
-
-	data := make(map[string]string)
-	for scalableNode, nodeInstances := range instancesByNodes {
-		data[path.Join("nodes", scalableNode)] = nodeInstances
-	}
-	// Add related workflow
-	data["workflowName"] = "uninstall"
-
-	return s.tasksCollector.RegisterTaskWithData(id, tasks.TaskTypeScaleIn, data)
-
+	return s.tasksCollector.RegisterScaleInTask(id, nodeName, instancesDelta, data)
 }
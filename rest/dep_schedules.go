@@ -0,0 +1,301 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/helper/collections"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/prov"
+	"github.com/ystia/yorc/v3/prov/scheduling"
+	"github.com/ystia/yorc/v3/tasks"
+)
+
+func (s *Server) newScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+
+	if !s.deploymentExists(w, r, deploymentID) {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var schRequest ScheduleRequest
+	if err = json.Unmarshal(body, &schRequest); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	action, restErr := s.buildScheduleAction(deploymentID, schRequest)
+	if restErr != nil {
+		writeError(w, r, restErr)
+		return
+	}
+
+	var id string
+	switch {
+	case schRequest.Cron != "" && schRequest.Interval != "":
+		writeError(w, r, newBadRequestMessage(`only one of "cron" and "interval" should be defined`))
+		return
+	case schRequest.Cron != "":
+		if _, err = scheduling.ParseCronExpression(schRequest.Cron); err != nil {
+			writeError(w, r, newBadRequestParameter("cron", err))
+			return
+		}
+		id, err = scheduling.RegisterCronAction(s.consulClient, deploymentID, schRequest.Cron, action)
+	case schRequest.Interval != "":
+		var interval time.Duration
+		interval, err = time.ParseDuration(schRequest.Interval)
+		if err == nil && interval <= 0 {
+			err = errors.Errorf("interval should be positive, got %q", schRequest.Interval)
+		}
+		if err != nil {
+			writeError(w, r, newBadRequestParameter("interval", err))
+			return
+		}
+		id, err = scheduling.RegisterAction(s.consulClient, deploymentID, interval, action)
+	default:
+		writeError(w, r, newBadRequestMessage(`one of "cron" or "interval" should be defined`))
+		return
+	}
+	if err != nil {
+		log.Panic(err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/schedules/%s", deploymentID, id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// buildScheduleAction checks the workflow or the custom command to schedule and returns the corresponding action
+func (s *Server) buildScheduleAction(deploymentID string, schRequest ScheduleRequest) (*prov.Action, *Error) {
+	kv := s.consulClient.KV()
+	data := make(map[string]string)
+	action := &prov.Action{Data: data}
+	switch {
+	case schRequest.WorkflowName != "" && schRequest.CustomCommand != nil:
+		return nil, newBadRequestMessage(`only one of "workflow" and "custom_command" should be defined`)
+	case schRequest.WorkflowName != "":
+		workflows, err := deployments.GetWorkflows(kv, deploymentID)
+		if err != nil {
+			log.Panic(err)
+		}
+		if !collections.ContainsString(workflows, schRequest.WorkflowName) {
+			return nil, newBadRequestMessage(fmt.Sprintf("unknown workflow %q", schRequest.WorkflowName))
+		}
+		action.ActionType = scheduling.WorkflowActionType
+		data["workflowName"] = schRequest.WorkflowName
+		data["continueOnError"] = strconv.FormatBool(schRequest.ContinueOnError)
+		for name, value := range schRequest.Inputs {
+			if value != nil {
+				data[path.Join("inputs", name)] = value.String()
+			}
+		}
+	case schRequest.CustomCommand != nil:
+		cc := schRequest.CustomCommand
+		cc.InterfaceName = strings.ToLower(cc.InterfaceName)
+		nodeExists, err := deployments.DoesNodeExist(kv, deploymentID, cc.NodeName)
+		if err != nil {
+			log.Panic(err)
+		}
+		if !nodeExists {
+			return nil, newBadRequestMessage(fmt.Sprintf("unknown node %q", cc.NodeName))
+		}
+		inputsName, err := s.getInputNameFromCustom(deploymentID, cc.NodeName, cc.InterfaceName, cc.CustomCommandName)
+		if err != nil {
+			return nil, newBadRequestError(err)
+		}
+		action.ActionType = scheduling.CustomCommandActionType
+		data["nodeName"] = cc.NodeName
+		data["commandName"] = cc.CustomCommandName
+		data["interfaceName"] = cc.InterfaceName
+		for _, name := range inputsName {
+			if value := cc.Inputs[name]; value != nil {
+				data[path.Join("inputs", name)] = value.String()
+			}
+		}
+	default:
+		return nil, newBadRequestMessage(`one of "workflow" or "custom_command" should be defined`)
+	}
+	return action, nil
+}
+
+func (s *Server) listSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+
+	if !s.deploymentExists(w, r, deploymentID) {
+		return
+	}
+
+	actions, err := scheduling.GetDeploymentActions(s.consulClient.KV(), deploymentID, scheduling.WorkflowActionType, scheduling.CustomCommandActionType)
+	if err != nil {
+		log.Panic(err)
+	}
+	schCol := SchedulesCollection{Schedules: make([]Schedule, len(actions))}
+	for i, action := range actions {
+		schCol.Schedules[i] = s.newSchedule(action)
+	}
+	encodeJSONResponse(w, r, schCol)
+}
+
+func (s *Server) getScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	action := s.schedulePreChecks(w, r)
+	if action == nil {
+		return
+	}
+	encodeJSONResponse(w, r, s.newSchedule(action))
+}
+
+func (s *Server) deleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	action := s.schedulePreChecks(w, r)
+	if action == nil {
+		return
+	}
+	if err := scheduling.UnregisterAction(s.consulClient, action.ID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) pauseScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	action := s.schedulePreChecks(w, r)
+	if action == nil {
+		return
+	}
+	if err := scheduling.PauseAction(s.consulClient, action.ID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) resumeScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	action := s.schedulePreChecks(w, r)
+	if action == nil {
+		return
+	}
+	if err := scheduling.ResumeAction(s.consulClient, action.ID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// schedulePreChecks returns the scheduled action referenced in the request
+//
+// A nil action is returned if the deployment or the schedule doesn't exist, in this case an error is already written.
+func (s *Server) schedulePreChecks(w http.ResponseWriter, r *http.Request) *scheduling.ScheduledAction {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+	scheduleID := params.ByName("scheduleId")
+
+	if !s.deploymentExists(w, r, deploymentID) {
+		return nil
+	}
+	action, err := scheduling.GetAction(s.consulClient.KV(), scheduleID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if action == nil || action.DeploymentID != deploymentID ||
+		(action.ActionType != scheduling.WorkflowActionType && action.ActionType != scheduling.CustomCommandActionType) {
+		writeError(w, r, errNotFound)
+		return nil
+	}
+	return action
+}
+
+func (s *Server) deploymentExists(w http.ResponseWriter, r *http.Request, deploymentID string) bool {
+	dExits, err := deployments.DoesDeploymentExists(s.consulClient.KV(), deploymentID)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+	}
+	return dExits
+}
+
+func (s *Server) newSchedule(action *scheduling.ScheduledAction) Schedule {
+	schedulePath := path.Join("/deployments", action.DeploymentID, "schedules", action.ID)
+	sch := Schedule{
+		ID:           action.ID,
+		Type:         action.ActionType,
+		Cron:         action.CronExpression,
+		WorkflowName: action.Data["workflowName"],
+		NodeName:     action.Data["nodeName"],
+		Paused:       action.Paused,
+		Links: []AtomLink{
+			newAtomLink(LinkRelSelf, schedulePath),
+			newAtomLink(LinkRelDeployment, path.Join("/deployments", action.DeploymentID)),
+		},
+	}
+	if action.CronExpression == "" {
+		sch.Interval = action.Interval.String()
+	}
+	sch.ContinueOnError, _ = strconv.ParseBool(action.Data["continueOnError"])
+	if action.ActionType == scheduling.CustomCommandActionType {
+		sch.CustomCommand = action.Data["commandName"]
+		if action.Data["interfaceName"] != "" {
+			sch.CustomCommand = action.Data["interfaceName"] + "." + sch.CustomCommand
+		}
+	}
+	for k, v := range action.Data {
+		if strings.HasPrefix(k, "inputs/") {
+			if sch.Inputs == nil {
+				sch.Inputs = make(map[string]string)
+			}
+			sch.Inputs[strings.TrimPrefix(k, "inputs/")] = v
+		}
+	}
+	if !action.LastRun.IsZero() {
+		sch.LastRun = &action.LastRun
+	}
+	if action.LatestTaskID != "" {
+		kv := s.consulClient.KV()
+		exists, err := tasks.TaskExists(kv, action.LatestTaskID)
+		if err != nil {
+			log.Panic(err)
+		}
+		// Tasks may have been purged
+		if exists {
+			status, err := tasks.GetTaskStatus(kv, action.LatestTaskID)
+			if err != nil {
+				log.Panic(err)
+			}
+			sch.LastTaskStatus = status.String()
+			sch.Links = append(sch.Links, newAtomLink(LinkRelTask, path.Join("/deployments", action.DeploymentID, "tasks", action.LatestTaskID)))
+		}
+	}
+	return sch
+}
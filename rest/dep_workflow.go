@@ -54,6 +54,16 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := isDryRunRequest(r)
+	if err != nil {
+		writeError(w, r, newBadRequestParameter("dry_run", err))
+		return
+	}
+	if dryRun {
+		s.planWorkflow(w, r, deploymentID, workflowName)
+		return
+	}
+
 	data := make(map[string]string)
 	data["workflowName"] = workflowName
 	if _, ok := r.URL.Query()["continueOnError"]; ok {
@@ -61,6 +71,10 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		data["continueOnError"] = strconv.FormatBool(false)
 	}
+	if restErr := addBatchParameters(r, data); restErr != nil {
+		writeError(w, r, restErr)
+		return
+	}
 
 	taskID, err := s.tasksCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeCustomWorkflow, data)
 	if err != nil {
@@ -136,3 +150,27 @@ func (s *Server) getWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 	wf := Workflow{Name: workflowName, Workflow: wfSteps}
 	encodeJSONResponse(w, r, wf)
 }
+
+// addBatchParameters checks the batch_size and pause_between_batches query parameters and adds them to the task data
+func addBatchParameters(r *http.Request, data map[string]string) *Error {
+	var batchSize int
+	var err error
+	if value := r.URL.Query().Get("batch_size"); value != "" {
+		batchSize, err = strconv.Atoi(value)
+		if err != nil {
+			return newBadRequestParameter("batch_size", err)
+		}
+	}
+	pause := r.URL.Query().Get("pause_between_batches")
+	policy, err := deployments.ParseBatchPolicy(batchSize, pause)
+	if err != nil {
+		return newBadRequestError(err)
+	}
+	if policy.BatchSize != 0 {
+		data["batchSize"] = strconv.Itoa(policy.BatchSize)
+	}
+	if pause != "" {
+		data["pauseBetweenBatches"] = policy.PauseBetweenBatches.String()
+	}
+	return nil
+}
@@ -56,24 +56,27 @@ func extractFile(f *zip.File, path string) {
 
 // unzipArchiveGetTopology unzips an archive and return the path to its topology
 // yaml file
+//
+// In case of a deployment update, the current deployment directory is kept as a backup
+// that should be either restored or removed once the new definition is stored.
 func unzipArchiveGetTopology(workingDir, deploymentID string, r *http.Request, deploymentUpdate bool) (string, *Error) {
 	var err error
 	var file *os.File
+	var extracted bool
 
 	uploadPath := filepath.Join(workingDir, "deployments", deploymentID)
-	backupPath := filepath.Join(workingDir, "deployments", "."+deploymentID)
 	if deploymentUpdate {
 		// This is a deployment update, renaming the current deployment directory
 		if _, err := os.Stat(uploadPath); !os.IsNotExist(err) {
 			// path/to/whatever exists
-			if err := os.Rename(uploadPath, backupPath); err != nil {
+			if err := os.Rename(uploadPath, getDeploymentBackupPath(workingDir, deploymentID)); err != nil {
 				return "", newInternalServerError(err)
 			}
 
 			defer func() {
 				// Restore backup in case of error
-				if _, err := os.Stat(backupPath); !os.IsNotExist(err) {
-					os.Rename(backupPath, uploadPath)
+				if !extracted {
+					restoreDeploymentBackup(workingDir, deploymentID)
 				}
 			}()
 		}
@@ -138,16 +141,54 @@ func unzipArchiveGetTopology(workingDir, deploymentID string, r *http.Request, d
 		return "", newBadRequestError(err)
 	}
 
-	// Cleanup
-	if deploymentUpdate {
-		os.RemoveAll(backupPath)
-	}
-
+	extracted = true
 	return yamlList[0], nil
 
 }
 
+func getDeploymentBackupPath(workingDir, deploymentID string) string {
+	return filepath.Join(workingDir, "deployments", "."+deploymentID)
+}
+
+// restoreDeploymentBackup puts back the deployment directory saved by unzipArchiveGetTopology
+// when a deployment update fails
+func restoreDeploymentBackup(workingDir, deploymentID string) {
+	backupPath := getDeploymentBackupPath(workingDir, deploymentID)
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		return
+	}
+	uploadPath := filepath.Join(workingDir, "deployments", deploymentID)
+	if err := os.RemoveAll(uploadPath); err != nil {
+		log.Printf("Failed to remove directory of rejected update of deployment %q: %v", deploymentID, err)
+		return
+	}
+	if err := os.Rename(backupPath, uploadPath); err != nil {
+		log.Printf("Failed to restore directory of deployment %q: %v", deploymentID, err)
+	}
+}
+
+// removeDeploymentBackup removes the deployment directory saved by unzipArchiveGetTopology
+// once a deployment update is stored
+func removeDeploymentBackup(workingDir, deploymentID string) {
+	if err := os.RemoveAll(getDeploymentBackupPath(workingDir, deploymentID)); err != nil {
+		log.Printf("Failed to remove previous directory of deployment %q: %v", deploymentID, err)
+	}
+}
+
 func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := isDryRunRequest(r)
+	if err != nil {
+		writeError(w, r, newBadRequestParameter("dry_run", err))
+		return
+	}
+	if dryRun {
+		if r.Method != http.MethodPost {
+			writeError(w, r, newBadRequestMessage("dry-run is only supported when creating a deployment with a generated id"))
+			return
+		}
+		s.planDeployment(w, r)
+		return
+	}
 
 	var uid string
 	if r.Method == http.MethodPut {
@@ -332,6 +373,14 @@ func (s *Server) listDeploymentsHandler(w http.ResponseWriter, r *http.Request)
 	depPrefix := consulutil.DeploymentKVPrefix + "/"
 	for _, depPath := range depPaths {
 		deploymentID := strings.TrimRight(strings.TrimPrefix(depPath, depPrefix), "/ ")
+		dryRun, err := deployments.IsDryRunDeployment(kv, deploymentID)
+		if err != nil {
+			log.Panic(err)
+		}
+		if dryRun {
+			// Temporary deployment used to compute a dry-run plan
+			continue
+		}
 		status, err := deployments.GetDeploymentStatus(kv, deploymentID)
 		if err != nil {
 			if deployments.IsDeploymentNotFoundError(err) {
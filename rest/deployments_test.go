@@ -0,0 +1,62 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentBackup(t *testing.T) {
+	workingDir, err := ioutil.TempDir("", "yorc-rest-")
+	require.NoError(t, err)
+	defer os.RemoveAll(workingDir)
+	deploymentID := "backupDep"
+	uploadPath := filepath.Join(workingDir, "deployments", deploymentID)
+	backupPath := getDeploymentBackupPath(workingDir, deploymentID)
+
+	// Rejected update: the new content is replaced by the backup
+	require.NoError(t, os.MkdirAll(backupPath, 0775))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(backupPath, "topology.yml"), []byte("v1"), 0664))
+	require.NoError(t, os.MkdirAll(uploadPath, 0775))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(uploadPath, "topology.yml"), []byte("v2"), 0664))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(uploadPath, "other.yml"), []byte("v2"), 0664))
+	restoreDeploymentBackup(workingDir, deploymentID)
+	content, err := ioutil.ReadFile(filepath.Join(uploadPath, "topology.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(content))
+	_, err = os.Stat(filepath.Join(uploadPath, "other.yml"))
+	assert.True(t, os.IsNotExist(err), "unexpected file of rejected update")
+	_, err = os.Stat(backupPath)
+	assert.True(t, os.IsNotExist(err), "backup should be restored")
+
+	// Nothing to restore without backup
+	restoreDeploymentBackup(workingDir, deploymentID)
+	_, err = os.Stat(filepath.Join(uploadPath, "topology.yml"))
+	assert.NoError(t, err)
+
+	// Accepted update: the backup is removed
+	require.NoError(t, os.MkdirAll(backupPath, 0775))
+	removeDeploymentBackup(workingDir, deploymentID)
+	_, err = os.Stat(backupPath)
+	assert.True(t, os.IsNotExist(err), "backup should be removed")
+	_, err = os.Stat(uploadPath)
+	assert.NoError(t, err)
+}
@@ -42,14 +42,19 @@ func writeError(w http.ResponseWriter, r *http.Request, err *Error) {
 }
 
 var (
-	errNotFound  = &Error{"not_found", 404, "Not Found", "Requested content not found."}
-	errForbidden = &Error{"forbidden", 401, "Forbidden", "This operation is forbidden."}
+	errNotFound     = &Error{"not_found", 404, "Not Found", "Requested content not found."}
+	errForbidden    = &Error{"forbidden", 401, "Forbidden", "This operation is forbidden."}
+	errUnauthorized = &Error{"unauthorized", http.StatusUnauthorized, "Unauthorized", "A valid bearer token is required to access this resource."}
 )
 
 func newContentNotFoundError(contentName string) *Error {
 	return &Error{"not_found", 404, "Not Found", fmt.Sprintf("%s not found.", contentName)}
 }
 
+func newForbiddenError(message string) *Error {
+	return &Error{"forbidden", http.StatusForbidden, "Forbidden", message}
+}
+
 func newInternalServerError(err interface{}) *Error {
 	return &Error{"internal_server_error", 500, "Internal Server Error", fmt.Sprintf("Something went wrong: %+v", err)}
 }
@@ -28,6 +28,11 @@ import (
 )
 
 func (s *Server) pollEvents(w http.ResponseWriter, r *http.Request) {
+	filter := newEntryFilter(r, events.ETaskID.String())
+	if isStreamRequest(r) {
+		s.streamEntries(w, r, "event", events.StatusEventsWithIndexes, filter)
+		return
+	}
 	var params httprouter.Params
 	ctx := r.Context()
 	kv := s.consulClient.KV()
@@ -69,12 +74,17 @@ func (s *Server) pollEvents(w http.ResponseWriter, r *http.Request) {
 		log.Panicf("Can't retrieve events: %v", err)
 	}
 
-	eventsCollection := EventsCollection{Events: evts, LastIndex: lastIdx}
+	eventsCollection := EventsCollection{Events: filter.filter(evts), LastIndex: lastIdx}
 	w.Header().Add(YorcIndexHeader, strconv.FormatUint(lastIdx, 10))
 	encodeJSONResponse(w, r, eventsCollection)
 }
 
 func (s *Server) pollLogs(w http.ResponseWriter, r *http.Request) {
+	filter := newEntryFilter(r, events.ExecutionID.String())
+	if isStreamRequest(r) {
+		s.streamEntries(w, r, "log", events.LogsEventsWithIndexes, filter)
+		return
+	}
 	var params httprouter.Params
 	ctx := r.Context()
 	kv := s.consulClient.KV()
@@ -119,7 +129,7 @@ func (s *Server) pollLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	lastIdx = idx
 
-	logCollection := LogsCollection{Logs: logs, LastIndex: lastIdx}
+	logCollection := LogsCollection{Logs: filter.filter(logs), LastIndex: lastIdx}
 	w.Header().Add(YorcIndexHeader, strconv.FormatUint(lastIdx, 10))
 	encodeJSONResponse(w, r, logCollection)
 }
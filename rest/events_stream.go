@@ -0,0 +1,312 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/events"
+	"github.com/ystia/yorc/v3/log"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+	// streamBlockingTime is the maximum duration of Consul blocking queries used to feed streams.
+	// A keep-alive is sent to the client each time a query returns without new entries.
+	streamBlockingTime = 30 * time.Second
+)
+
+type entriesFetcher func(kv *api.KV, deploymentID string, waitIndex uint64, timeout time.Duration) ([]events.IndexedEntry, uint64, error)
+
+// entrySender pushes entries to a streaming client
+type entrySender interface {
+	send(entry events.IndexedEntry) error
+	keepAlive() error
+}
+
+// entryFilter allows to select entries by node, task or log level
+type entryFilter struct {
+	node    string
+	task    string
+	taskKey string
+	levels  []string
+}
+
+func newEntryFilter(r *http.Request, taskKey string) entryFilter {
+	values := r.URL.Query()
+	f := entryFilter{
+		node:    values.Get("node"),
+		task:    values.Get("task"),
+		taskKey: taskKey,
+	}
+	if level := values.Get("level"); level != "" {
+		f.levels = strings.Split(strings.ToUpper(level), ",")
+	}
+	return f
+}
+
+func (f entryFilter) isEmpty() bool {
+	return f.node == "" && f.task == "" && len(f.levels) == 0
+}
+
+func (f entryFilter) match(entry json.RawMessage) bool {
+	if f.isEmpty() {
+		return true
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(entry, &data); err != nil {
+		return false
+	}
+	if f.node != "" && fmt.Sprint(data[events.ENodeID.String()]) != f.node {
+		return false
+	}
+	if f.task != "" && fmt.Sprint(data[f.taskKey]) != f.task {
+		return false
+	}
+	if len(f.levels) > 0 {
+		level := strings.ToUpper(fmt.Sprint(data["level"]))
+		for _, l := range f.levels {
+			if strings.TrimSpace(l) == level {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (f entryFilter) filter(entries []json.RawMessage) []json.RawMessage {
+	if f.isEmpty() {
+		return entries
+	}
+	res := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		if f.match(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func isStreamRequest(r *http.Request) bool {
+	return r.Header.Get("Accept") == eventStreamContentType || isWebSocketRequest(r)
+}
+
+func isWebSocketRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// streamAcceptHandler works as acceptHandler but also lets Server-Sent Events and WebSocket requests through
+func streamAcceptHandler(cType string) func(http.Handler) http.Handler {
+	m := func(next http.Handler) http.Handler {
+		accept := acceptHandler(cType)(next)
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if isStreamRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			accept.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+	return m
+}
+
+// streamStartIndex returns the index from which a stream should start.
+//
+// The Last-Event-ID header sent by SSE clients on reconnection takes precedence over the index query parameter.
+func streamStartIndex(r *http.Request) (uint64, error) {
+	idx := r.Header.Get("Last-Event-ID")
+	if idx == "" {
+		idx = r.URL.Query().Get("index")
+	}
+	if idx == "" {
+		return 1, nil
+	}
+	return strconv.ParseUint(idx, 10, 64)
+}
+
+// checkWebSocketOrigin prevents cross-site WebSocket hijacking by only accepting
+// the Yorc server own origin and the configured allowed origins.
+//
+// Requests without Origin header are accepted as they are not issued by browsers.
+func checkWebSocketOrigin(r *http.Request, allowedOrigins []string) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return errors.Wrapf(err, "invalid Origin header %q", origin)
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return nil
+		}
+	}
+	return errors.Errorf("origin %q is not allowed to open WebSockets", origin)
+}
+
+func (s *Server) streamEntries(w http.ResponseWriter, r *http.Request, eventName string, fetch entriesFetcher, filter entryFilter) {
+	ctx := r.Context()
+	kv := s.consulClient.KV()
+	params := ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	if id != "" {
+		if depExist, err := deployments.DoesDeploymentExists(kv, id); err != nil {
+			log.Panic(err)
+		} else if !depExist {
+			writeError(w, r, errNotFound)
+			return
+		}
+	}
+	index, err := streamStartIndex(r)
+	if err != nil {
+		writeError(w, r, newBadRequestParameter("index", err))
+		return
+	}
+
+	if isWebSocketRequest(r) {
+		wsServer := websocket.Server{
+			Handshake: func(_ *websocket.Config, r *http.Request) error {
+				return checkWebSocketOrigin(r, s.config.Auth.WebSocketAllowedOrigins)
+			},
+			Handler: func(ws *websocket.Conn) {
+				defer ws.Close()
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				go func() {
+					// Consume incoming frames to detect connection closure
+					var msg string
+					for websocket.Message.Receive(ws, &msg) == nil {
+					}
+					cancel()
+				}()
+				s.feedStream(ctx, kv, id, index, fetch, filter, &wsSender{conn: ws})
+			},
+		}
+		wsServer.ServeHTTP(w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, newInternalServerError("streaming is not supported by the underlying connection"))
+		return
+	}
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	s.feedStream(ctx, kv, id, index, fetch, filter, &sseSender{w: w, flusher: flusher, eventName: eventName})
+}
+
+func (s *Server) feedStream(ctx context.Context, kv *api.KV, deploymentID string, index uint64, fetch entriesFetcher, filter entryFilter, sender entrySender) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		entries, lastIndex, err := fetch(kv, deploymentID, index, streamBlockingTime)
+		if err != nil {
+			log.Printf("Failed to retrieve entries for streaming: %v", err)
+			return
+		}
+		sent := false
+		for _, e := range entries {
+			if !filter.match(e.Value) {
+				continue
+			}
+			if err = sender.send(e); err != nil {
+				log.Debugf("Stream closed: %v", err)
+				return
+			}
+			sent = true
+		}
+		if !sent {
+			if err = sender.keepAlive(); err != nil {
+				log.Debugf("Stream closed: %v", err)
+				return
+			}
+		}
+		if lastIndex > index {
+			index = lastIndex
+		}
+	}
+}
+
+type sseSender struct {
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	eventName string
+}
+
+func (s *sseSender) send(entry events.IndexedEntry) error {
+	_, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", entry.Index, s.eventName, entry.Value)
+	if err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseSender) keepAlive() error {
+	_, err := fmt.Fprint(s.w, ": keep-alive\n\n")
+	if err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+type wsSender struct {
+	conn *websocket.Conn
+}
+
+func (s *wsSender) send(entry events.IndexedEntry) error {
+	return websocket.JSON.Send(s.conn, entry)
+}
+
+func (s *wsSender) keepAlive() error {
+	// Send a ping frame, this keeps the connection open through proxies and allows to detect closed connections
+	fw, err := s.conn.NewFrameWriter(websocket.PingFrame)
+	if err != nil {
+		return err
+	}
+	if _, err = fw.Write(nil); err != nil {
+		fw.Close()
+		return err
+	}
+	return fw.Close()
+}
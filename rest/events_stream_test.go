@@ -0,0 +1,105 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/events"
+)
+
+func TestEntryFilter(t *testing.T) {
+	t.Parallel()
+	entries := []json.RawMessage{
+		json.RawMessage(`{"nodeId":"Compute","executionId":"t1","level":"INFO"}`),
+		json.RawMessage(`{"nodeId":"Compute","executionId":"t2","level":"ERROR"}`),
+		json.RawMessage(`{"nodeId":"Tomcat","executionId":"t1","level":"DEBUG"}`),
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"NoFilter", "", 3},
+		{"ByNode", "?node=Compute", 2},
+		{"ByTask", "?task=t1", 2},
+		{"ByNodeAndTask", "?node=Compute&task=t1", 1},
+		{"ByLevel", "?level=error", 1},
+		{"ByLevels", "?level=ERROR,DEBUG", 2},
+		{"NoMatch", "?node=Unknown", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEntryFilter(httptest.NewRequest("GET", "/logs"+tt.query, nil), events.ExecutionID.String())
+			require.Len(t, f.filter(entries), tt.want)
+		})
+	}
+}
+
+func TestStreamStartIndex(t *testing.T) {
+	t.Parallel()
+	r := httptest.NewRequest("GET", "/events", nil)
+	idx, err := streamStartIndex(r)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), idx)
+
+	r = httptest.NewRequest("GET", "/events?index=42", nil)
+	idx, err = streamStartIndex(r)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), idx)
+
+	r.Header.Set("Last-Event-ID", "51")
+	idx, err = streamStartIndex(r)
+	require.NoError(t, err)
+	require.Equal(t, uint64(51), idx)
+
+	r = httptest.NewRequest("GET", "/events?index=abc", nil)
+	_, err = streamStartIndex(r)
+	require.Error(t, err)
+}
+
+func TestCheckWebSocketOrigin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		origin  string
+		allowed []string
+		wantErr bool
+	}{
+		{"NoOrigin", "", nil, false},
+		{"SameOrigin", "http://yorc.example.com:8800", nil, false},
+		{"CrossOrigin", "http://evil.example.com", nil, true},
+		{"AllowedOrigin", "https://dashboard.example.com", []string{"https://dashboard.example.com/"}, false},
+		{"InvalidOrigin", "://bad", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://yorc.example.com:8800/events", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			err := checkWebSocketOrigin(r, tt.allowed)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
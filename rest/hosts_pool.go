@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"path"
 	"strconv"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
@@ -33,8 +35,9 @@ func (s *Server) deleteHostInPool(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
 	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
 	hostname := params.ByName("host")
-	err := s.hostsPoolMgr.Remove(hostname)
+	err := s.hostsPoolMgr.Remove(poolName, hostname)
 	if err != nil {
 		if hostspool.IsHostNotFoundError(err) {
 			writeError(w, r, errNotFound)
@@ -53,6 +56,7 @@ func (s *Server) newHostInPool(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
 	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
 	hostname := params.ByName("host")
 
 	body, err := ioutil.ReadAll(r.Body)
@@ -80,7 +84,7 @@ func (s *Server) newHostInPool(w http.ResponseWriter, r *http.Request) {
 		labels[entry.Name] = entry.Value
 	}
 
-	err = s.hostsPoolMgr.Add(hostname, *host.Connection, labels)
+	err = s.hostsPoolMgr.Add(poolName, hostname, *host.Connection, labels)
 	if err != nil {
 		if hostspool.IsHostAlreadyExistError(err) || hostspool.IsBadRequestError(err) {
 			writeError(w, r, newBadRequestError(err))
@@ -88,7 +92,7 @@ func (s *Server) newHostInPool(w http.ResponseWriter, r *http.Request) {
 		}
 		log.Panic(err)
 	}
-	w.Header().Set("Location", fmt.Sprintf("/hosts_pool/%s", hostname))
+	w.Header().Set("Location", path.Join("/hosts_pool", poolName, hostname))
 	w.WriteHeader(http.StatusCreated)
 }
 
@@ -96,6 +100,7 @@ func (s *Server) updateHostInPool(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
 	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
 	hostname := params.ByName("host")
 
 	body, err := ioutil.ReadAll(r.Body)
@@ -111,7 +116,7 @@ func (s *Server) updateHostInPool(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if host.Connection != nil {
-		err = s.hostsPoolMgr.UpdateConnection(hostname, *host.Connection)
+		err = s.hostsPoolMgr.UpdateConnection(poolName, hostname, *host.Connection)
 		if err != nil {
 			if hostspool.IsBadRequestError(err) {
 				writeError(w, r, newBadRequestError(err))
@@ -135,7 +140,7 @@ func (s *Server) updateHostInPool(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if len(labelsDelete) > 0 {
-		err = s.hostsPoolMgr.RemoveLabels(hostname, labelsDelete)
+		err = s.hostsPoolMgr.RemoveLabels(poolName, hostname, labelsDelete)
 		if err != nil {
 			if hostspool.IsBadRequestError(err) {
 				writeError(w, r, newBadRequestError(err))
@@ -149,7 +154,7 @@ func (s *Server) updateHostInPool(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if len(labelsAdd) > 0 {
-		err = s.hostsPoolMgr.AddLabels(hostname, labelsAdd)
+		err = s.hostsPoolMgr.AddLabels(poolName, hostname, labelsAdd)
 		if err != nil {
 			if hostspool.IsBadRequestError(err) {
 				writeError(w, r, newBadRequestError(err))
@@ -169,9 +174,10 @@ func (s *Server) getHostInPool(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
 	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
 	hostname := params.ByName("host")
 
-	host, err := s.hostsPoolMgr.GetHost(hostname)
+	host, err := s.hostsPoolMgr.GetHost(poolName, hostname)
 	if err != nil {
 		if hostspool.IsHostNotFoundError(err) {
 			writeError(w, r, errNotFound)
@@ -185,11 +191,32 @@ func (s *Server) getHostInPool(w http.ResponseWriter, r *http.Request) {
 	}
 
 	restHost := Host{Host: host, Links: make([]AtomLink, 1)}
-	restHost.Links[0] = newAtomLink(LinkRelSelf, fmt.Sprintf("/hosts_pool/%s", hostname))
+	restHost.Links[0] = newAtomLink(LinkRelSelf, path.Join("/hosts_pool", poolName, hostname))
 	encodeJSONResponse(w, r, restHost)
 }
 
+func (s *Server) listHostsPools(w http.ResponseWriter, r *http.Request) {
+	pools, err := s.hostsPoolMgr.ListPools()
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(pools) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	poolsCol := HostsPoolsCollection{Pools: make([]AtomLink, len(pools))}
+	for i, p := range pools {
+		poolsCol.Pools[i] = newAtomLink(LinkRelHostsPool, path.Join("/hosts_pool", p))
+	}
+	encodeJSONResponse(w, r, poolsCol)
+}
+
 func (s *Server) listHostsInPool(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
+
 	filtersString := r.URL.Query()["filter"]
 
 	filters := make([]labelsutil.Filter, len(filtersString))
@@ -203,8 +230,12 @@ func (s *Server) listHostsInPool(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	hostsNames, warnings, checkpoint, err := s.hostsPoolMgr.List(filters...)
+	hostsNames, warnings, checkpoint, err := s.hostsPoolMgr.List(poolName, filters...)
 	if err != nil {
+		if hostspool.IsBadRequestError(err) {
+			writeError(w, r, newBadRequestError(err))
+			return
+		}
 		log.Panic(err)
 	}
 
@@ -219,7 +250,7 @@ func (s *Server) listHostsInPool(w http.ResponseWriter, r *http.Request) {
 		hostsCol.Hosts = make([]AtomLink, len(hostsNames))
 	}
 	for i, h := range hostsNames {
-		hostsCol.Hosts[i] = newAtomLink(LinkRelHost, fmt.Sprintf("/hosts_pool/%s", h))
+		hostsCol.Hosts[i] = newAtomLink(LinkRelHost, path.Join("/hosts_pool", poolName, h))
 	}
 	if len(warnings) > 0 {
 		hostsCol.Warnings = make([]string, len(warnings))
@@ -232,6 +263,11 @@ func (s *Server) listHostsInPool(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) applyHostsPool(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
+
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Panic(err)
@@ -268,7 +304,7 @@ func (s *Server) applyHostsPool(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = s.hostsPoolMgr.Apply(pool, hostsPoolCheckpoint)
+	err = s.hostsPoolMgr.Apply(poolName, pool, hostsPoolCheckpoint)
 	if err != nil {
 		if hostspool.IsHostAlreadyExistError(err) || hostspool.IsBadRequestError(err) {
 			writeError(w, r, newBadRequestError(err))
@@ -283,3 +319,36 @@ func (s *Server) applyHostsPool(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+func (s *Server) renewHostAllocationLease(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	poolName := params.ByName("pool")
+	hostname := params.ByName("host")
+	allocationID := params.ByName("allocation")
+
+	var duration time.Duration
+	if d := r.URL.Query().Get("duration"); d != "" {
+		var err error
+		duration, err = time.ParseDuration(d)
+		if err != nil {
+			writeError(w, r, newBadRequestParameter("duration", err))
+			return
+		}
+	}
+
+	allocation, err := s.hostsPoolMgr.RenewLease(poolName, hostname, allocationID, duration)
+	if err != nil {
+		if hostspool.IsHostNotFoundError(err) || hostspool.IsAllocationNotFoundError(err) {
+			writeError(w, r, errNotFound)
+			return
+		}
+		if hostspool.IsBadRequestError(err) {
+			writeError(w, r, newBadRequestError(err))
+			return
+		}
+		log.Panic(err)
+	}
+	encodeJSONResponse(w, r, allocation)
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/testutil"
@@ -59,18 +60,21 @@ func testHostsPoolHandlers(t *testing.T, client *api.Client, srv *testutil.TestS
 	t.Run("testGetHostInPool", func(t *testing.T) {
 		testGetHostInPool(t, client, srv)
 	})
+	t.Run("testRenewHostAllocationLease", func(t *testing.T) {
+		testRenewHostAllocationLease(t, client, srv)
+	})
 }
 
 func testListHostsInPool(t *testing.T, client *api.Client, srv *testutil.TestServer) {
 	log.SetDebug(true)
 
 	srv.PopulateKV(t, map[string][]byte{
-		consulutil.HostsPoolPrefix + "/host21/status": []byte("free"),
-		consulutil.HostsPoolPrefix + "/host22/status": []byte("free"),
-		consulutil.HostsPoolPrefix + "/host23/status": []byte("free"),
+		consulutil.HostsPoolPrefix + "/default/host21/status": []byte("free"),
+		consulutil.HostsPoolPrefix + "/default/host22/status": []byte("free"),
+		consulutil.HostsPoolPrefix + "/default/host23/status": []byte("free"),
 	})
 
-	req := httptest.NewRequest("GET", "/hosts_pool", nil)
+	req := httptest.NewRequest("GET", "/hosts_pool/default", nil)
 	req.Header.Add("Accept", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	body, err := ioutil.ReadAll(resp.Body)
@@ -85,22 +89,36 @@ func testListHostsInPool(t *testing.T, client *api.Client, srv *testutil.TestSer
 	require.Nil(t, err, "unexpected error unmarshalling json body")
 	require.NotNil(t, collection, "unexpected nil hosts collection")
 	require.Equal(t, 3, len(collection.Hosts))
-	require.Equal(t, "/hosts_pool/host21", collection.Hosts[0].Href)
+	require.Equal(t, "/hosts_pool/default/host21", collection.Hosts[0].Href)
 	require.Equal(t, "host", collection.Hosts[0].Rel)
-	require.Equal(t, "/hosts_pool/host22", collection.Hosts[1].Href)
+	require.Equal(t, "/hosts_pool/default/host22", collection.Hosts[1].Href)
 	require.Equal(t, "host", collection.Hosts[1].Rel)
-	require.Equal(t, "/hosts_pool/host23", collection.Hosts[2].Href)
+	require.Equal(t, "/hosts_pool/default/host23", collection.Hosts[2].Href)
 	require.Equal(t, "host", collection.Hosts[2].Rel)
 
-	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/host21", nil)
-	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/host22", nil)
-	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/host23", nil)
+	req = httptest.NewRequest("GET", "/hosts_pool", nil)
+	req.Header.Add("Accept", "application/json")
+	resp = newTestHTTPRouter(client, req)
+	body, err = ioutil.ReadAll(resp.Body)
+	require.Nil(t, err, "unexpected error reading body response")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusOK)
+
+	var poolsCollection HostsPoolsCollection
+	err = json.Unmarshal(body, &poolsCollection)
+	require.Nil(t, err, "unexpected error unmarshalling json body")
+	require.Equal(t, 1, len(poolsCollection.Pools))
+	require.Equal(t, "/hosts_pool/default", poolsCollection.Pools[0].Href)
+	require.Equal(t, "hosts_pool", poolsCollection.Pools[0].Rel)
+
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host21", nil)
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host22", nil)
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host23", nil)
 }
 
 func testListNoHostsInPool(t *testing.T, client *api.Client, srv *testutil.TestServer) {
 	t.Parallel()
 
-	req := httptest.NewRequest("GET", "/hosts_pool", nil)
+	req := httptest.NewRequest("GET", "/hosts_pool/empty", nil)
 	req.Header.Add("Accept", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	_, err := ioutil.ReadAll(resp.Body)
@@ -114,7 +132,7 @@ func testListHostsInPoolWithBadFilter(t *testing.T, client *api.Client, srv *tes
 	t.Parallel()
 	log.SetDebug(true)
 
-	req := httptest.NewRequest("GET", "/hosts_pool", nil)
+	req := httptest.NewRequest("GET", "/hosts_pool/default", nil)
 	filters := []string{"bad++"}
 	q := req.URL.Query()
 	for i := range filters {
@@ -133,14 +151,14 @@ func testListHostsInPoolWithBadFilter(t *testing.T, client *api.Client, srv *tes
 func testDeleteHostInPool(t *testing.T, client *api.Client, srv *testutil.TestServer) {
 	t.Parallel()
 	srv.PopulateKV(t, map[string][]byte{
-		consulutil.HostsPoolPrefix + "/host13/status":                 []byte("free"),
-		consulutil.HostsPoolPrefix + "/host13/connection/host":        []byte("1.2.3.4"),
-		consulutil.HostsPoolPrefix + "/host13/connection/port":        []byte("22"),
-		consulutil.HostsPoolPrefix + "/host13/connection/private_key": []byte("test/cert1.pem"),
-		consulutil.HostsPoolPrefix + "/host13/connection/user":        []byte("user1"),
+		consulutil.HostsPoolPrefix + "/default/host13/status":                 []byte("free"),
+		consulutil.HostsPoolPrefix + "/default/host13/connection/host":        []byte("1.2.3.4"),
+		consulutil.HostsPoolPrefix + "/default/host13/connection/port":        []byte("22"),
+		consulutil.HostsPoolPrefix + "/default/host13/connection/private_key": []byte("test/cert1.pem"),
+		consulutil.HostsPoolPrefix + "/default/host13/connection/user":        []byte("user1"),
 	})
 
-	req := httptest.NewRequest("DELETE", "/hosts_pool/host13", nil)
+	req := httptest.NewRequest("DELETE", "/hosts_pool/default/host13", nil)
 	resp := newTestHTTPRouter(client, req)
 	_, err := ioutil.ReadAll(resp.Body)
 
@@ -152,7 +170,7 @@ func testDeleteHostInPool(t *testing.T, client *api.Client, srv *testutil.TestSe
 func testDeleteHostInPoolNotFound(t *testing.T, client *api.Client, srv *testutil.TestServer) {
 	t.Parallel()
 
-	req := httptest.NewRequest("DELETE", "/hosts_pool/hostNOTFOUND", nil)
+	req := httptest.NewRequest("DELETE", "/hosts_pool/default/hostNOTFOUND", nil)
 	resp := newTestHTTPRouter(client, req)
 	_, err := ioutil.ReadAll(resp.Body)
 
@@ -175,15 +193,15 @@ func testNewHostInPool(t *testing.T, client *api.Client, srv *testutil.TestServe
 
 	tmp, err := json.Marshal(hostRequest)
 	require.Nil(t, err, "unexpected error marshalling data to provide body request")
-	req := httptest.NewRequest("PUT", "/hosts_pool/host11", bytes.NewBuffer([]byte(string(tmp))))
+	req := httptest.NewRequest("PUT", "/hosts_pool/default/host11", bytes.NewBuffer([]byte(string(tmp))))
 	req.Header.Add("Content-Type", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	_, err = ioutil.ReadAll(resp.Body)
 	require.NotNil(t, resp, "unexpected nil response")
 	require.Equal(t, http.StatusCreated, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusCreated)
-	require.Equal(t, []string{"/hosts_pool/host11"}, resp.Header["Location"])
+	require.Equal(t, []string{"/hosts_pool/default/host11"}, resp.Header["Location"])
 
-	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/host11", nil)
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host11", nil)
 }
 
 func testNewHostInPoolWithoutConnectionInfo(t *testing.T, client *api.Client, srv *testutil.TestServer) {
@@ -194,7 +212,7 @@ func testNewHostInPoolWithoutConnectionInfo(t *testing.T, client *api.Client, sr
 
 	tmp, err := json.Marshal(hostRequest)
 	require.Nil(t, err, "unexpected error marshalling data to provide body request")
-	req := httptest.NewRequest("PUT", "/hosts_pool/host12", bytes.NewBuffer([]byte(string(tmp))))
+	req := httptest.NewRequest("PUT", "/hosts_pool/default/host12", bytes.NewBuffer([]byte(string(tmp))))
 	req.Header.Add("Content-Type", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	_, err = ioutil.ReadAll(resp.Body)
@@ -215,7 +233,7 @@ func testNewHostInPoolWithoutPrivateKeyOrPassword(t *testing.T, client *api.Clie
 
 	tmp, err := json.Marshal(hostRequest)
 	require.Nil(t, err, "unexpected error marshalling data to provide body request")
-	req := httptest.NewRequest("PUT", "/hosts_pool/host11", bytes.NewBuffer([]byte(string(tmp))))
+	req := httptest.NewRequest("PUT", "/hosts_pool/default/host11", bytes.NewBuffer([]byte(string(tmp))))
 	req.Header.Add("Content-Type", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	_, err = ioutil.ReadAll(resp.Body)
@@ -227,14 +245,14 @@ func testGetHostInPool(t *testing.T, client *api.Client, srv *testutil.TestServe
 	t.Parallel()
 
 	srv.PopulateKV(t, map[string][]byte{
-		consulutil.HostsPoolPrefix + "/host17/status":                 []byte("free"),
-		consulutil.HostsPoolPrefix + "/host17/connection/host":        []byte("1.2.3.4"),
-		consulutil.HostsPoolPrefix + "/host17/connection/port":        []byte("22"),
-		consulutil.HostsPoolPrefix + "/host17/connection/private_key": []byte("test/cert1.pem"),
-		consulutil.HostsPoolPrefix + "/host17/connection/user":        []byte("user1"),
+		consulutil.HostsPoolPrefix + "/default/host17/status":                 []byte("free"),
+		consulutil.HostsPoolPrefix + "/default/host17/connection/host":        []byte("1.2.3.4"),
+		consulutil.HostsPoolPrefix + "/default/host17/connection/port":        []byte("22"),
+		consulutil.HostsPoolPrefix + "/default/host17/connection/private_key": []byte("test/cert1.pem"),
+		consulutil.HostsPoolPrefix + "/default/host17/connection/user":        []byte("user1"),
 	})
 
-	req := httptest.NewRequest("GET", "/hosts_pool/host17", nil)
+	req := httptest.NewRequest("GET", "/hosts_pool/default/host17", nil)
 	req.Header.Add("Accept", "application/json")
 	resp := newTestHTTPRouter(client, req)
 	body, err := ioutil.ReadAll(resp.Body)
@@ -260,5 +278,51 @@ func testGetHostInPool(t *testing.T, client *api.Client, srv *testutil.TestServe
 
 	require.Equal(t, hostspool.HostStatusFree, host.Status, "unexpected not free host status")
 
-	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/host17", nil)
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host17", nil)
+}
+
+func testRenewHostAllocationLease(t *testing.T, client *api.Client, srv *testutil.TestServer) {
+	t.Parallel()
+
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.HostsPoolPrefix + "/default/host18/status":                                    []byte("allocated"),
+		consulutil.HostsPoolPrefix + "/default/host18/connection/host":                           []byte("1.2.3.4"),
+		consulutil.HostsPoolPrefix + "/default/host18/connection/private_key":                    []byte("test/cert1.pem"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0":                    []byte("dep-node-0"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/node_name":          []byte("node"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/instance":           []byte("0"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/deployment_id":      []byte("dep"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/lease_duration":     []byte("1h0m0s"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/lease_expiry":       []byte("2018-01-01T00:00:00Z"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/lease_expired":      []byte("true"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/resources/cpus":     []byte("2"),
+		consulutil.HostsPoolPrefix + "/default/host18/allocations/dep-node-0/resources/mem_size": []byte("2 GB"),
+	})
+
+	req := httptest.NewRequest("POST", "/hosts_pool/default/host18/allocations/dep-node-0/renew?duration=2h", nil)
+	req.Header.Add("Accept", "application/json")
+	resp := newTestHTTPRouter(client, req)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.Nil(t, err, "unexpected error reading body response")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusOK)
+
+	var allocation hostspool.Allocation
+	err = json.Unmarshal(body, &allocation)
+	require.Nil(t, err, "unexpected error unmarshalling json body")
+	require.Equal(t, "dep-node-0", allocation.ID)
+	require.NotNil(t, allocation.LeaseExpiry)
+	require.True(t, allocation.LeaseExpiry.After(time.Now().Add(time.Hour)), "unexpected lease expiry %v", allocation.LeaseExpiry)
+	require.False(t, allocation.LeaseExpired)
+
+	req = httptest.NewRequest("POST", "/hosts_pool/default/host18/allocations/dep-node-0/renew?duration=bad", nil)
+	req.Header.Add("Accept", "application/json")
+	resp = newTestHTTPRouter(client, req)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusBadRequest)
+
+	req = httptest.NewRequest("POST", "/hosts_pool/default/host18/allocations/unknown/renew", nil)
+	req.Header.Add("Accept", "application/json")
+	resp = newTestHTTPRouter(client, req)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusNotFound)
+
+	client.KV().DeleteTree(consulutil.HostsPoolPrefix+"/default/host18", nil)
 }
@@ -83,6 +83,7 @@ type Server struct {
 	tasksCollector *collector.Collector
 	config         config.Configuration
 	hostsPoolMgr   hostspool.Manager
+	authenticator  *authenticator
 }
 
 // Shutdown stops the HTTP server
@@ -106,6 +107,10 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to bind on %s", addr)
 	}
+	auth, err := newAuthenticator(configuration.Auth)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to setup REST API authentication")
+	}
 	sslEnabled := (configuration.CertFile != "" && configuration.KeyFile != "")
 	if sslEnabled {
 		listener, err = wrapListenerTLS(listener, configuration)
@@ -121,6 +126,7 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 		tasksCollector: collector.NewCollector(client),
 		config:         configuration,
 		hostsPoolMgr:   hostspool.NewManager(client),
+		authenticator:  auth,
 	}
 
 	httpServer.registerHandlers()
@@ -135,65 +141,93 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 	} else {
 		log.Printf("Starting HTTPServer on address %s", listener.Addr())
 	}
+	if auth == nil {
+		log.Printf("[WARNING] REST API authentication is disabled, anyone reaching the HTTP port will be able to manage deployments")
+	}
 	go http.Serve(httpServer.listener, httpServer.router)
 
 	return httpServer, nil
 }
 
 func (s *Server) registerHandlers() {
-	commonHandlers := alice.New(telemetryHandler, loggingHandler, recoverHandler)
+	commonHandlers := alice.New(telemetryHandler, loggingHandler, s.auditHandler, recoverHandler)
+	// Routes are protected according to the minimum role required to use them.
+	// When authentication is disabled those handlers are equivalent to commonHandlers.
+	viewerHandlers := commonHandlers.Append(s.authHandler(RoleViewer))
+	operatorHandlers := commonHandlers.Append(s.authHandler(RoleOperator))
+	adminHandlers := commonHandlers.Append(s.authHandler(RoleAdmin))
 	s.router.Get("/health", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getHealthHandler))
-	s.router.Post("/deployments", commonHandlers.Append(contentTypeHandler("application/zip")).ThenFunc(s.newDeploymentHandler))
-	s.router.Put("/deployments/:id", commonHandlers.Append(contentTypeHandler("application/zip")).ThenFunc(s.newDeploymentHandler))
-	s.router.Delete("/deployments/:id", commonHandlers.ThenFunc(s.deleteDeploymentHandler))
-	s.router.Get("/deployments/:id", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getDeploymentHandler))
-	s.router.Get("/deployments", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listDeploymentsHandler))
-	s.router.Get("/deployments/:id/events", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.pollEvents))
-	s.router.Get("/events", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.pollEvents))
-	s.router.Head("/deployments/:id/events", commonHandlers.ThenFunc(s.headEventsIndex))
-	s.router.Head("/events", commonHandlers.ThenFunc(s.headEventsIndex))
-	s.router.Get("/deployments/:id/logs", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.pollLogs))
-	s.router.Get("/logs", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.pollLogs))
-	s.router.Head("/deployments/:id/logs", commonHandlers.ThenFunc(s.headLogsEventsIndex))
-	s.router.Head("/logs", commonHandlers.ThenFunc(s.headLogsEventsIndex))
-	s.router.Get("/deployments/:id/nodes/:nodeName", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeHandler))
-	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceHandler))
-	s.router.Get("/deployments/:id/outputs", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listOutputsHandler))
-	s.router.Get("/deployments/:id/outputs/:opt", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getOutputHandler))
-	s.router.Get("/deployments/:id/tasks/:taskId", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskHandler))
-	s.router.Get("/deployments/:id/tasks/:taskId/steps", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskStepsHandler))
-	s.router.Delete("/deployments/:id/tasks/:taskId", commonHandlers.ThenFunc(s.cancelTaskHandler))
-	s.router.Put("/deployments/:id/tasks/:taskId", commonHandlers.ThenFunc(s.resumeTaskHandler))
-	s.router.Put("/deployments/:id/tasks/:taskId/steps/:stepId", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.updateTaskStepStatusHandler))
-	s.router.Post("/deployments/:id/scale/:nodeName", commonHandlers.ThenFunc(s.scaleHandler))
-	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceAttributesListHandler))
-	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes/:attributeName", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceAttributeHandler))
-	s.router.Post("/deployments/:id/custom", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newCustomCommandHandler))
-	s.router.Post("/deployments/:id/workflows/:workflowName", commonHandlers.ThenFunc(s.newWorkflowHandler))
-	s.router.Get("/deployments/:id/workflows/:workflowName", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getWorkflowHandler))
-	s.router.Get("/deployments/:id/workflows", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listWorkflowsHandler))
-
-	s.router.Get("/registry/delegates", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryDelegatesHandler))
-	s.router.Get("/registry/implementations", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryImplementationsHandler))
-	s.router.Get("/registry/definitions", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryDefinitionsHandler))
-	s.router.Get("/registry/vaults", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listVaultsBuilderHandler))
-	s.router.Get("/registry/infra_usage_collectors", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listInfraHandler))
-
-	s.router.Post("/infra_usage/:infraName", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.postInfraUsageHandler))
-	s.router.Get("/infra_usage/:infraName/tasks/:taskId", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskQueryHandler))
-	s.router.Delete("/infra_usage/:infraName/tasks/:taskId", commonHandlers.ThenFunc(s.deleteTaskQueryHandler))
-	s.router.Get("/infra_usage", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listTaskQueryHandler))
-
-	s.router.Put("/hosts_pool/:host", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newHostInPool))
-	s.router.Patch("/hosts_pool/:host", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.updateHostInPool))
-	s.router.Delete("/hosts_pool/:host", commonHandlers.ThenFunc(s.deleteHostInPool))
-	s.router.Post("/hosts_pool", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.applyHostsPool))
-	s.router.Put("/hosts_pool", commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.applyHostsPool))
-	s.router.Get("/hosts_pool", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listHostsInPool))
-	s.router.Get("/hosts_pool/:host", commonHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getHostInPool))
+	s.router.Post("/deployments", operatorHandlers.Append(contentTypeHandler("application/zip")).ThenFunc(s.newDeploymentHandler))
+	s.router.Put("/deployments/:id", operatorHandlers.Append(contentTypeHandler("application/zip")).ThenFunc(s.newDeploymentHandler))
+	s.router.Delete("/deployments/:id", operatorHandlers.ThenFunc(s.deleteDeploymentHandler))
+	s.router.Get("/deployments/:id", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getDeploymentHandler))
+	s.router.Get("/deployments", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listDeploymentsHandler))
+	s.router.Get("/deployments/:id/events", viewerHandlers.Append(streamAcceptHandler("application/json")).ThenFunc(s.pollEvents))
+	s.router.Get("/events", viewerHandlers.Append(streamAcceptHandler("application/json")).ThenFunc(s.pollEvents))
+	s.router.Head("/deployments/:id/events", viewerHandlers.ThenFunc(s.headEventsIndex))
+	s.router.Head("/events", viewerHandlers.ThenFunc(s.headEventsIndex))
+	s.router.Get("/deployments/:id/logs", viewerHandlers.Append(streamAcceptHandler("application/json")).ThenFunc(s.pollLogs))
+	s.router.Get("/logs", viewerHandlers.Append(streamAcceptHandler("application/json")).ThenFunc(s.pollLogs))
+	s.router.Head("/deployments/:id/logs", viewerHandlers.ThenFunc(s.headLogsEventsIndex))
+	s.router.Head("/logs", viewerHandlers.ThenFunc(s.headLogsEventsIndex))
+	s.router.Get("/deployments/:id/nodes/:nodeName", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeHandler))
+	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceHandler))
+	s.router.Get("/deployments/:id/outputs", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listOutputsHandler))
+	s.router.Get("/deployments/:id/outputs/:opt", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getOutputHandler))
+	s.router.Get("/deployments/:id/tasks/:taskId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskHandler))
+	s.router.Get("/deployments/:id/tasks/:taskId/steps", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskStepsHandler))
+	s.router.Delete("/deployments/:id/tasks/:taskId", operatorHandlers.ThenFunc(s.cancelTaskHandler))
+	s.router.Put("/deployments/:id/tasks/:taskId", operatorHandlers.ThenFunc(s.resumeTaskHandler))
+	s.router.Put("/deployments/:id/tasks/:taskId/steps/:stepId", operatorHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.updateTaskStepStatusHandler))
+	s.router.Post("/deployments/:id/scale/:nodeName", operatorHandlers.ThenFunc(s.scaleHandler))
+	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceAttributesListHandler))
+	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes/:attributeName", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getNodeInstanceAttributeHandler))
+	s.router.Post("/deployments/:id/custom", operatorHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newCustomCommandHandler))
+	s.router.Post("/deployments/:id/workflows/:workflowName", operatorHandlers.ThenFunc(s.newWorkflowHandler))
+	s.router.Get("/deployments/:id/workflows/:workflowName", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getWorkflowHandler))
+	s.router.Get("/deployments/:id/workflows", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listWorkflowsHandler))
+	s.router.Get("/deployments/:id/checks", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listCheckReportsHandler))
+	s.router.Get("/deployments/:id/checks/:nodeName", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listCheckReportsHandler))
+	s.router.Get("/deployments/:id/checks/:nodeName/:instanceId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getCheckReportHandler))
+	s.router.Post("/deployments/:id/schedules", operatorHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newScheduleHandler))
+	s.router.Get("/deployments/:id/schedules", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listSchedulesHandler))
+	s.router.Get("/deployments/:id/schedules/:scheduleId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getScheduleHandler))
+	s.router.Delete("/deployments/:id/schedules/:scheduleId", operatorHandlers.ThenFunc(s.deleteScheduleHandler))
+	s.router.Put("/deployments/:id/schedules/:scheduleId/pause", operatorHandlers.ThenFunc(s.pauseScheduleHandler))
+	s.router.Put("/deployments/:id/schedules/:scheduleId/resume", operatorHandlers.ThenFunc(s.resumeScheduleHandler))
+
+	s.router.Get("/registry/delegates", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryDelegatesHandler))
+	s.router.Get("/registry/implementations", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryImplementationsHandler))
+	s.router.Get("/registry/definitions", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listRegistryDefinitionsHandler))
+	s.router.Get("/registry/vaults", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listVaultsBuilderHandler))
+	s.router.Get("/registry/infra_usage_collectors", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listInfraHandler))
+
+	s.router.Post("/infra_usage/:infraName", operatorHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.postInfraUsageHandler))
+	s.router.Get("/infra_usage/:infraName/tasks/:taskId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getTaskQueryHandler))
+	s.router.Delete("/infra_usage/:infraName/tasks/:taskId", operatorHandlers.ThenFunc(s.deleteTaskQueryHandler))
+	s.router.Get("/infra_usage", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listTaskQueryHandler))
+
+	s.router.Post("/webhooks", operatorHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newWebhookHandler))
+	s.router.Get("/webhooks", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listWebhooksHandler))
+	s.router.Get("/webhooks/:webhookId", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getWebhookHandler))
+	s.router.Delete("/webhooks/:webhookId", operatorHandlers.ThenFunc(s.deleteWebhookHandler))
+	s.router.Get("/webhooks/:webhookId/dead_letters", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listWebhookDeadLettersHandler))
+	s.router.Delete("/webhooks/:webhookId/dead_letters", operatorHandlers.ThenFunc(s.deleteWebhookDeadLettersHandler))
+
+	s.router.Get("/audit", adminHandlers.ThenFunc(s.listAuditRecordsHandler))
+
+	s.router.Get("/hosts_pool", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listHostsPools))
+	s.router.Put("/hosts_pool/:pool/:host", adminHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.newHostInPool))
+	s.router.Patch("/hosts_pool/:pool/:host", adminHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.updateHostInPool))
+	s.router.Delete("/hosts_pool/:pool/:host", adminHandlers.ThenFunc(s.deleteHostInPool))
+	s.router.Post("/hosts_pool/:pool", adminHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.applyHostsPool))
+	s.router.Put("/hosts_pool/:pool", adminHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.applyHostsPool))
+	s.router.Get("/hosts_pool/:pool", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.listHostsInPool))
+	s.router.Get("/hosts_pool/:pool/:host", viewerHandlers.Append(acceptHandler("application/json")).ThenFunc(s.getHostInPool))
+	s.router.Post("/hosts_pool/:pool/:host/allocations/:allocation/renew", operatorHandlers.Append(acceptHandler("application/json")).ThenFunc(s.renewHostAllocationLease))
 
 	if s.config.Telemetry.PrometheusEndpoint {
-		s.router.Get("/metrics", commonHandlers.Then(promhttp.Handler()))
+		s.router.Get("/metrics", viewerHandlers.Then(promhttp.Handler()))
 	}
 }
 
@@ -15,11 +15,14 @@
 package rest
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v3/helper/metricsutil"
 	"github.com/ystia/yorc/v3/log"
@@ -93,6 +96,25 @@ func (w *statusRecorderResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Flush implements http.Flusher, it is required by streaming endpoints
+func (w *statusRecorderResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, it is required by WebSocket endpoints
+func (w *statusRecorderResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("underlying response writer does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
 func telemetryHandler(next http.Handler) http.Handler {
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
@@ -19,7 +19,11 @@ package rest
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
+	"time"
 
+	"github.com/ystia/yorc/v3/audit"
+	"github.com/ystia/yorc/v3/notifications"
 	"github.com/ystia/yorc/v3/prov/hostspool"
 	"github.com/ystia/yorc/v3/registry"
 	"github.com/ystia/yorc/v3/tosca"
@@ -44,6 +48,12 @@ const (
 	LinkRelWorkflow string = "workflow"
 	// LinkRelHost defines the AtomLink Rel attribute for relationships of the "host" (for hostspool)
 	LinkRelHost string = "host"
+	// LinkRelHostsPool defines the AtomLink Rel attribute for relationships of the "hosts_pool" (for hostspool)
+	LinkRelHostsPool string = "hosts_pool"
+	// LinkRelSchedule defines the AtomLink Rel attribute for relationships of the "schedule"
+	LinkRelSchedule string = "schedule"
+	// LinkRelWebhook defines the AtomLink Rel attribute for relationships of the "webhook"
+	LinkRelWebhook string = "webhook"
 )
 
 const (
@@ -178,6 +188,43 @@ type WorkflowsCollection struct {
 	Workflows []AtomLink `json:"workflows"`
 }
 
+// ScheduleRequest is the representation of a request to schedule executions of a custom workflow or of a custom command
+//
+// Exactly one of Cron and Interval should be defined as well as exactly one of WorkflowName and CustomCommand.
+type ScheduleRequest struct {
+	Cron            string                `json:"cron,omitempty"`
+	Interval        string                `json:"interval,omitempty"`
+	WorkflowName    string                `json:"workflow,omitempty"`
+	ContinueOnError bool                  `json:"continue_on_error,omitempty"`
+	CustomCommand   *CustomCommandRequest `json:"custom_command,omitempty"`
+	// Inputs are the inputs of the workflow operations, custom commands inputs are defined within CustomCommand
+	Inputs map[string]*tosca.ValueAssignment `json:"inputs,omitempty"`
+}
+
+// Schedule is the representation of scheduled executions of a custom workflow or of a custom command
+//
+// Links are of type LinkRelSelf, LinkRelDeployment and LinkRelTask for the latest task launched by this schedule.
+type Schedule struct {
+	ID              string            `json:"id"`
+	Type            string            `json:"type"`
+	Cron            string            `json:"cron,omitempty"`
+	Interval        string            `json:"interval,omitempty"`
+	WorkflowName    string            `json:"workflow,omitempty"`
+	ContinueOnError bool              `json:"continue_on_error,omitempty"`
+	NodeName        string            `json:"node,omitempty"`
+	CustomCommand   string            `json:"custom_command,omitempty"`
+	Inputs          map[string]string `json:"inputs,omitempty"`
+	Paused          bool              `json:"paused"`
+	LastRun         *time.Time        `json:"last_run,omitempty"`
+	LastTaskStatus  string            `json:"last_task_status,omitempty"`
+	Links           []AtomLink        `json:"links"`
+}
+
+// SchedulesCollection is a collection of schedules of a deployment
+type SchedulesCollection struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
 // Workflow is a workflow representation.
 type Workflow struct {
 	Name string `json:"name"`
@@ -210,6 +257,35 @@ func (o *MapEntryOperation) UnmarshalJSON(b []byte) error {
 	return err
 }
 
+// Role is an enumeration of the roles that could be granted to a REST API client.
+//
+// Roles are ordered, a role grants all the permissions of the roles declared before it.
+// ENUM(
+// Viewer,
+// Operator,
+// Admin
+// )
+type Role int
+
+// MarshalJSON is used to represent this enumeration as a string instead of an int
+func (r Role) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(strings.ToLower(r.String()))
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is used to read this enumeration from a string
+func (r *Role) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	*r, err = ParseRole(s)
+	return err
+}
+
 // A MapEntry allows to manipulate a Map collection by performing operations (Op) on entries.
 // It is inspired (with many simplifications) by the JSON Patch RFC https://tools.ietf.org/html/rfc6902
 type MapEntry struct {
@@ -239,6 +315,13 @@ type HostRequest struct {
 	Labels     []MapEntry            `json:"labels,omitempty"`
 }
 
+// HostsPoolsCollection is a collection of hosts pools links
+//
+// Links are all of type LinkRelHostsPool.
+type HostsPoolsCollection struct {
+	Pools []AtomLink `json:"pools"`
+}
+
 // HostsCollection is a collection of hosts registered in the host pool links
 //
 // Links are all of type LinkRelHost.
@@ -256,6 +339,51 @@ type Host struct {
 	Links []AtomLink `json:"links"`
 }
 
+// WebhooksCollection is a collection of webhooks subscriptions links
+//
+// Links are all of type LinkRelWebhook.
+type WebhooksCollection struct {
+	Webhooks []AtomLink `json:"webhooks"`
+}
+
+// Webhook is the representation of a webhook subscription, its secret is never returned
+//
+// Links are all of type LinkRelSelf.
+type Webhook struct {
+	notifications.Subscription
+	HasSecret bool       `json:"has_secret"`
+	Links     []AtomLink `json:"links"`
+}
+
+// DeadLettersCollection is the collection of events that couldn't be delivered to a webhook
+type DeadLettersCollection struct {
+	DeadLetters []notifications.DeadLetter `json:"dead_letters"`
+}
+
+// CheckReport is the representation of the report of a monitoring check on a node instance
+//
+// Links are of type LinkRelDeployment, LinkRelNode and LinkRelInstance.
+type CheckReport struct {
+	NodeName       string     `json:"node"`
+	Instance       string     `json:"instance"`
+	Status         string     `json:"status"`
+	Type           string     `json:"type"`
+	Interval       string     `json:"interval,omitempty"`
+	LastTransition *time.Time `json:"last_transition,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Links          []AtomLink `json:"links"`
+}
+
+// CheckReportsCollection is a collection of monitoring checks reports of a deployment
+type CheckReportsCollection struct {
+	Reports []CheckReport `json:"reports"`
+}
+
+// AuditRecordsCollection is the collection of audit records of the actions performed through the REST API
+type AuditRecordsCollection struct {
+	Records []audit.Record `json:"records"`
+}
+
 // RegistryDelegatesCollection is the collection of Delegates executors registered in the Yorc registry
 type RegistryDelegatesCollection struct {
 	Delegates []registry.DelegateMatch `json:"delegates"`
@@ -20,12 +20,84 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/ystia/yorc/v3/deployments"
 	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/tasks"
 )
 
 // updateDeployment updates a deployment
 func (s *Server) updateDeployment(w http.ResponseWriter, r *http.Request, id string) {
-	msg := fmt.Sprintf("Deployment with id %q already exists - Update supported only in premium versions", id)
-	log.Printf("[ERROR]: %s", msg)
-	writeError(w, r, newConflictRequest(msg))
+	update := s.updateDeploymentDefinition(w, r, id)
+	if update == nil {
+		return
+	}
+	if update.Workflow != "" {
+		data := map[string]string{
+			"workflowName": update.Workflow,
+		}
+		taskID, err := s.tasksCollector.RegisterTaskWithData(id, tasks.TaskTypeUpdate, data)
+		if err != nil {
+			if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+				writeError(w, r, newBadRequestError(err))
+				return
+			}
+			log.Panic(err)
+		}
+		w.Header().Set("Location", fmt.Sprintf("/deployments/%s/tasks/%s", id, taskID))
+	}
+	encodeJSONResponse(w, r, update)
+}
+
+// updateDeploymentDefinition stores the new definition of a deployment and returns the resulting update
+//
+// The deployment lock is held from the check of running tasks to the change of the deployment status,
+// so no task can be registered meanwhile. A nil update is returned when an error response was written.
+func (s *Server) updateDeploymentDefinition(w http.ResponseWriter, r *http.Request, id string) *deployments.DeploymentUpdate {
+	lock, err := deployments.LockDeployment(s.consulClient, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer lock.Unlock()
+
+	kv := s.consulClient.KV()
+	status, err := deployments.GetDeploymentStatus(kv, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	switch status {
+	case deployments.DEPLOYED, deployments.UPDATED, deployments.UPDATE_FAILURE:
+	default:
+		writeError(w, r, newConflictRequest(fmt.Sprintf("Deployment with id %q can't be updated while in status %q", id, status)))
+		return nil
+	}
+	hasLivingTask, livingTaskID, livingTaskStatus, err := tasks.TargetHasLivingTasks(kv, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if hasLivingTask {
+		writeError(w, r, newBadRequestError(tasks.NewAnotherLivingTaskAlreadyExistsError(livingTaskID, id, livingTaskStatus)))
+		return nil
+	}
+
+	log.Printf("Analyzing update of deployment %s\n", id)
+	yamlFile, archiveErr := unzipArchiveGetTopology(s.config.WorkingDirectory, id, r, true)
+	if archiveErr != nil {
+		log.Printf("Error analyzing archive for deployment update %s\n", id)
+		writeError(w, r, archiveErr)
+		return nil
+	}
+
+	update, err := deployments.UpdateDeploymentDefinition(r.Context(), kv, id, yamlFile)
+	if err != nil {
+		// The previous definition is restored, so is the deployment directory
+		restoreDeploymentBackup(s.config.WorkingDirectory, id)
+		if deployments.IsInvalidDefinitionError(err) || deployments.IsTypeMissingError(err) {
+			log.Printf("Invalid definition for deployment update %s: %v", id, err)
+			writeError(w, r, newBadRequestError(err))
+			return nil
+		}
+		log.Panic(err)
+	}
+	removeDeploymentBackup(s.config.WorkingDirectory, id)
+	return update
 }
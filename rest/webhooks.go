@@ -0,0 +1,128 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/notifications"
+)
+
+func (s *Server) newWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var subscription notifications.Subscription
+	if err = json.Unmarshal(body, &subscription); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	if err = subscription.Validate(); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	id, err := notifications.CreateSubscription(s.consulClient.KV(), subscription)
+	if err != nil {
+		log.Panic(err)
+	}
+	w.Header().Set("Location", path.Join("/webhooks", id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) listWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := notifications.ListSubscriptions(s.consulClient.KV())
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(subscriptions) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	col := WebhooksCollection{Webhooks: make([]AtomLink, len(subscriptions))}
+	for i, subscription := range subscriptions {
+		col.Webhooks[i] = newAtomLink(LinkRelWebhook, path.Join("/webhooks", subscription.ID))
+	}
+	encodeJSONResponse(w, r, col)
+}
+
+func (s *Server) getWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription := s.getWebhookFromRequest(w, r)
+	if subscription == nil {
+		return
+	}
+	webhook := Webhook{
+		Subscription: *subscription,
+		HasSecret:    subscription.Secret != "",
+		Links:        []AtomLink{newAtomLink(LinkRelSelf, r.URL.Path)},
+	}
+	webhook.Secret = ""
+	encodeJSONResponse(w, r, webhook)
+}
+
+func (s *Server) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription := s.getWebhookFromRequest(w, r)
+	if subscription == nil {
+		return
+	}
+	if err := notifications.DeleteSubscription(s.consulClient.KV(), subscription.ID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) listWebhookDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	subscription := s.getWebhookFromRequest(w, r)
+	if subscription == nil {
+		return
+	}
+	deadLetters, err := notifications.ListDeadLetters(s.consulClient.KV(), subscription.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+	encodeJSONResponse(w, r, DeadLettersCollection{DeadLetters: deadLetters})
+}
+
+func (s *Server) deleteWebhookDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	subscription := s.getWebhookFromRequest(w, r)
+	if subscription == nil {
+		return
+	}
+	if err := notifications.DeleteDeadLetters(s.consulClient.KV(), subscription.ID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// getWebhookFromRequest returns the subscription referenced in the request or nil if it doesn't exist (in this case an error is written)
+func (s *Server) getWebhookFromRequest(w http.ResponseWriter, r *http.Request) *notifications.Subscription {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	subscription, err := notifications.GetSubscription(s.consulClient.KV(), params.ByName("webhookId"))
+	if err != nil {
+		log.Panic(err)
+	}
+	if subscription == nil {
+		writeError(w, r, errNotFound)
+	}
+	return subscription
+}
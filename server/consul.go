@@ -27,6 +27,7 @@ import (
 var upgradeToMap = map[string]func(*api.KV, <-chan struct{}) error{
 	"1.0.0": upgradeschema.UpgradeFromPre31,
 	"1.1.0": upgradeschema.UpgradeTo110,
+	"1.2.0": upgradeschema.UpgradeTo120,
 }
 
 var orderedUpgradesVersions []semver.Version
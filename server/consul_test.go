@@ -15,6 +15,7 @@
 package server
 
 import (
+	"path"
 	"testing"
 
 	"github.com/blang/semver"
@@ -23,6 +24,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/prov/hostspool"
+	"github.com/ystia/yorc/v3/server/upgradeschema"
 	"github.com/ystia/yorc/v3/testutil"
 )
 
@@ -34,6 +37,9 @@ func TestConsulServerPackage(t *testing.T) {
 		t.Run("testSetupVersion", func(t *testing.T) {
 			testSetupVersion(t, client)
 		})
+		t.Run("testUpgradeTo120", func(t *testing.T) {
+			testUpgradeTo120(t, client)
+		})
 	})
 }
 
@@ -88,3 +94,43 @@ func testSetupVersion(t *testing.T, client *api.Client) {
 	checkSchemaVersion(t, kv, v.String())
 
 }
+
+func testUpgradeTo120(t *testing.T, client *api.Client) {
+	kv := client.KV()
+	// Forget about previous upgrades done by other tests
+	_, err := kv.DeleteTree(path.Join(consulutil.YorcManagementPrefix, "upgrade_120")+"/", nil)
+	require.NoError(t, err)
+	// A host is named as the default pool
+	hosts := []string{"host1", hostspool.DefaultPool, "host2"}
+	for _, host := range hosts {
+		_, err := kv.Put(&api.KVPair{Key: path.Join(consulutil.HostsPoolPrefix, host, "status"), Value: []byte("free")}, nil)
+		require.NoError(t, err)
+		_, err = kv.Put(&api.KVPair{Key: path.Join(consulutil.HostsPoolPrefix, host, "connection", "host"), Value: []byte(host + ".example.com")}, nil)
+		require.NoError(t, err)
+	}
+
+	checkHosts := func() {
+		t.Helper()
+		keys, _, err := kv.Keys(consulutil.HostsPoolPrefix+"/", "/", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{consulutil.HostsPoolPrefix + "/" + hostspool.DefaultPool + "/"}, keys)
+		for _, host := range hosts {
+			kvp, _, err := kv.Get(path.Join(consulutil.HostsPoolPrefix, hostspool.DefaultPool, host, "connection", "host"), nil)
+			require.NoError(t, err)
+			require.NotNil(t, kvp, "host %q not moved into the default pool", host)
+			assert.Equal(t, host+".example.com", string(kvp.Value))
+		}
+		keys, _, err = kv.Keys(path.Join(consulutil.HostsPoolPrefix, hostspool.DefaultPool)+"/", "/", nil)
+		require.NoError(t, err)
+		assert.Len(t, keys, len(hosts))
+	}
+
+	err = upgradeschema.UpgradeTo120(kv, nil)
+	require.NoError(t, err)
+	checkHosts()
+
+	// Running the upgrade again doesn't move the default pool
+	err = upgradeschema.UpgradeTo120(kv, nil)
+	require.NoError(t, err)
+	checkHosts()
+}
@@ -30,6 +30,8 @@ import (
 	_ "github.com/ystia/yorc/v3/prov/slurm"
 	// Registering hosts pool delegate executor in the registry
 	_ "github.com/ystia/yorc/v3/prov/hostspool"
+	// Registering docker delegate executor in the registry
+	_ "github.com/ystia/yorc/v3/prov/docker"
 	// Registering builtin Tosca definition files
 	_ "github.com/ystia/yorc/v3/tosca"
 	// Registering builtin HashiCorp Vault Client Builder
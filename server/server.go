@@ -24,10 +24,14 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v3/audit"
 	"github.com/ystia/yorc/v3/config"
 	"github.com/ystia/yorc/v3/deployments"
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/notifications"
+	"github.com/ystia/yorc/v3/prov/autoscaling"
+	"github.com/ystia/yorc/v3/prov/hostspool"
 	"github.com/ystia/yorc/v3/prov/monitoring"
 	"github.com/ystia/yorc/v3/prov/scheduling/scheduler"
 	"github.com/ystia/yorc/v3/rest"
@@ -102,6 +106,26 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 	scheduler.Start(configuration, client)
 	defer scheduler.Stop()
 
+	// Start webhooks notifications
+	notifications.Start(configuration, client)
+	defer notifications.Stop()
+
+	// Start hosts pool leases reaper
+	hostspool.StartLeaseReaper(configuration, client)
+	defer hostspool.StopLeaseReaper()
+
+	// Start hosts pool health sweeper
+	hostspool.StartHealthSweeper(configuration, client)
+	defer hostspool.StopHealthSweeper()
+
+	// Start metric-driven autoscaling
+	autoscaling.Start(configuration, client)
+	defer autoscaling.Stop()
+
+	// Start audit records purger
+	audit.StartPurger(configuration, client)
+	defer audit.StopPurger()
+
 WAIT:
 	signalCh := make(chan os.Signal, 4)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
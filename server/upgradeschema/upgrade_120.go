@@ -0,0 +1,133 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradeschema
+
+import (
+	"path"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/helper/consulutil"
+	"github.com/ystia/yorc/v3/log"
+	"github.com/ystia/yorc/v3/prov/hostspool"
+)
+
+// up120StatePrefix stores the progress of the upgrade to 1.2.0 so that it could safely be run again if interrupted
+var up120StatePrefix = path.Join(consulutil.YorcManagementPrefix, "upgrade_120")
+
+const (
+	up120PhaseStaged = "staged"
+	up120PhaseDone   = "done"
+)
+
+// UpgradeTo120 allows to upgrade Consul schema from 1.1.0 to 1.2.0
+//
+// Hosts of the hosts pool are moved into the default pool.
+// As a host could be named as the default pool, hosts are first moved to a staging area and then into the default pool.
+// The progress of the upgrade is stored so that it can be run again after an interruption.
+func UpgradeTo120(kv *api.KV, leaderch <-chan struct{}) error {
+	log.Print("Upgrading to database version 1.2.0...")
+	phase, err := up120GetState(kv, "phase")
+	if err != nil || phase == up120PhaseDone {
+		return err
+	}
+	hosts, err := up120GetHostsToMove(kv)
+	if err != nil {
+		return err
+	}
+	stagingPrefix := path.Join(up120StatePrefix, "staging")
+	if phase != up120PhaseStaged {
+		for _, host := range hosts {
+			if err = up120MoveTree(kv, path.Join(consulutil.HostsPoolPrefix, host), path.Join(stagingPrefix, host)); err != nil {
+				return err
+			}
+		}
+		if err = up120SetState(kv, "phase", up120PhaseStaged); err != nil {
+			return err
+		}
+	}
+	for _, host := range hosts {
+		if err = up120MoveTree(kv, path.Join(stagingPrefix, host), path.Join(consulutil.HostsPoolPrefix, hostspool.DefaultPool, host)); err != nil {
+			return err
+		}
+	}
+	return up120SetState(kv, "phase", up120PhaseDone)
+}
+
+// up120GetHostsToMove returns the hosts of the hosts pool to move into the default pool
+//
+// The list is computed on the first run and stored, as on later runs the hosts pool could already contain the default pool.
+func up120GetHostsToMove(kv *api.KV) ([]string, error) {
+	kvp, _, err := kv.Get(path.Join(up120StatePrefix, "hosts"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp != nil {
+		if len(kvp.Value) == 0 {
+			return nil, nil
+		}
+		return strings.Split(string(kvp.Value), "\n"), nil
+	}
+
+	keys, _, err := kv.Keys(consulutil.HostsPoolPrefix+"/", "/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	hosts := make([]string, 0, len(keys))
+	for _, hostPrefix := range keys {
+		if !strings.HasSuffix(hostPrefix, "/") {
+			continue
+		}
+		hosts = append(hosts, path.Base(hostPrefix))
+	}
+	return hosts, up120SetState(kv, "hosts", strings.Join(hosts, "\n"))
+}
+
+// up120MoveTree copies all keys under a prefix to another one then deletes the original prefix
+//
+// Running it again after an interruption is safe as the original keys are only deleted once all of them are copied.
+func up120MoveTree(kv *api.KV, fromPrefix, toPrefix string) error {
+	kvps, _, err := kv.List(fromPrefix+"/", nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, kvp := range kvps {
+		newKey := path.Join(toPrefix, strings.TrimPrefix(kvp.Key, fromPrefix))
+		_, err = kv.Put(&api.KVPair{Key: newKey, Value: kvp.Value, Flags: kvp.Flags}, nil)
+		if err != nil {
+			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+	}
+	_, err = kv.DeleteTree(fromPrefix+"/", nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+func up120GetState(kv *api.KV, name string) (string, error) {
+	kvp, _, err := kv.Get(path.Join(up120StatePrefix, name), nil)
+	if err != nil {
+		return "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil {
+		return "", nil
+	}
+	return string(kvp.Value), nil
+}
+
+func up120SetState(kv *api.KV, name, value string) error {
+	_, err := kv.Put(&api.KVPair{Key: path.Join(up120StatePrefix, name), Value: []byte(value)}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
@@ -67,6 +67,40 @@ func (c *Collector) RegisterTask(targetID string, taskType tasks.TaskType) (stri
 	return c.RegisterTaskWithData(targetID, taskType, nil)
 }
 
+// RegisterScaleOutTask registers a task adding the given number of instances to a node using the install workflow
+//
+// data contains optional additional task data (it may be nil), it is completed by this function.
+// The task id is returned.
+func (c *Collector) RegisterScaleOutTask(deploymentID, nodeName string, instancesDelta uint32, data map[string]string) (string, error) {
+	if data == nil {
+		data = make(map[string]string)
+	}
+	data["instancesDelta"] = strconv.Itoa(int(instancesDelta))
+	data["workflowName"] = "install"
+	data["nodeName"] = nodeName
+	return c.RegisterTaskWithData(deploymentID, tasks.TaskTypeScaleOut, data)
+}
+
+// RegisterScaleInTask registers a task removing the given number of instances of a node using the uninstall workflow
+//
+// Removed instances are selected by deployments.SelectNodeStackInstances.
+// data contains optional additional task data (it may be nil), it is completed by this function.
+// The task id is returned.
+func (c *Collector) RegisterScaleInTask(deploymentID, nodeName string, instancesDelta uint32, data map[string]string) (string, error) {
+	instancesByNodes, err := deployments.SelectNodeStackInstances(c.consulClient.KV(), deploymentID, nodeName, int(instancesDelta))
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		data = make(map[string]string)
+	}
+	for scalableNode, nodeInstances := range instancesByNodes {
+		data[path.Join("nodes", scalableNode)] = nodeInstances
+	}
+	data["workflowName"] = "uninstall"
+	return c.RegisterTaskWithData(deploymentID, tasks.TaskTypeScaleIn, data)
+}
+
 // ResumeTask allows to resume a task previously failed
 func (c *Collector) ResumeTask(taskID string) error {
 	taskType, err := tasks.GetTaskType(c.consulClient.KV(), taskID)
@@ -117,6 +151,12 @@ func (c *Collector) ResumeTask(taskID string) error {
 func (c *Collector) registerTask(targetID string, taskType tasks.TaskType, data map[string]string) (string, error) {
 	// First check if other tasks are running for this target before creating a new one except for Action tasks
 	if tasks.TaskTypeAction != taskType {
+		// The deployment lock prevents a deployment update or another task to be registered until this one is stored
+		lock, err := deployments.LockDeployment(c.consulClient, targetID)
+		if err != nil {
+			return "", err
+		}
+		defer lock.Unlock()
 		hasLivingTask, livingTaskID, livingTaskStatus, err := tasks.TargetHasLivingTasks(c.consulClient.KV(), targetID)
 		if err != nil {
 			return "", err
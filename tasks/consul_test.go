@@ -60,6 +60,9 @@ func TestRunConsulTasksPackageTests(t *testing.T) {
 		t.Run("TestGetInstances", func(t *testing.T) {
 			testGetInstances(t, kv)
 		})
+		t.Run("TestGetInstancesWithContext", func(t *testing.T) {
+			testGetInstancesWithContext(t, kv)
+		})
 		t.Run("TestGetTaskRelatedNodes", func(t *testing.T) {
 			testGetTaskRelatedNodes(t, kv)
 		})
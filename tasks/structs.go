@@ -27,6 +27,7 @@ package tasks
 // Query,
 // Action
 // ForcePurge
+// Update
 // )
 type TaskType int
 
@@ -14,6 +14,8 @@
 
 package tasks
 
+import "time"
+
 //go:generate go-enum -f=structs_step.go --lower
 
 // TaskStepStatus x ENUM(
@@ -21,7 +23,8 @@ package tasks
 // RUNNING,
 // DONE,
 // ERROR,
-// CANCELED
+// CANCELED,
+// SKIPPED
 // )
 type TaskStepStatus int
 
@@ -29,4 +32,16 @@ type TaskStepStatus int
 type TaskStep struct {
 	Name   string `json:"name"`
 	Status string `json:"status"`
+	// Attempts are only recorded for steps having a timeout or a retry policy
+	Attempts []TaskStepAttempt `json:"attempts,omitempty"`
+}
+
+// TaskStepAttempt represents an attempt to run an activity of a workflow step
+type TaskStepAttempt struct {
+	Activity  string     `json:"activity"`
+	Attempt   int        `json:"attempt"`
+	Status    string     `json:"status"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	Error     string     `json:"error,omitempty"`
 }
@@ -33,9 +33,11 @@ const (
 	TaskStepStatusERROR
 	// TaskStepStatusCANCELED is a TaskStepStatus of type CANCELED
 	TaskStepStatusCANCELED
+	// TaskStepStatusSKIPPED is a TaskStepStatus of type SKIPPED
+	TaskStepStatusSKIPPED
 )
 
-const _TaskStepStatusName = "INITIALRUNNINGDONEERRORCANCELED"
+const _TaskStepStatusName = "INITIALRUNNINGDONEERRORCANCELEDSKIPPED"
 
 var _TaskStepStatusMap = map[TaskStepStatus]string{
 	0: _TaskStepStatusName[0:7],
@@ -43,6 +45,7 @@ var _TaskStepStatusMap = map[TaskStepStatus]string{
 	2: _TaskStepStatusName[14:18],
 	3: _TaskStepStatusName[18:23],
 	4: _TaskStepStatusName[23:31],
+	5: _TaskStepStatusName[31:38],
 }
 
 func (i TaskStepStatus) String() string {
@@ -63,6 +66,8 @@ var _TaskStepStatusValue = map[string]TaskStepStatus{
 	strings.ToLower(_TaskStepStatusName[18:23]): 3,
 	_TaskStepStatusName[23:31]:                  4,
 	strings.ToLower(_TaskStepStatusName[23:31]): 4,
+	_TaskStepStatusName[31:38]:                  5,
+	strings.ToLower(_TaskStepStatusName[31:38]): 5,
 }
 
 // ParseTaskStepStatus attempts to convert a string to a TaskStepStatus
@@ -17,6 +17,7 @@ package tasks
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"path"
 	"strconv"
@@ -59,7 +60,7 @@ func IsAnotherLivingTaskAlreadyExistsError(err error) (bool, string) {
 
 // IsWorkflowTask returns true if the task type is related to workflow
 func IsWorkflowTask(taskType TaskType) bool {
-	return taskType == TaskTypeDeploy || taskType == TaskTypeUnDeploy || taskType == TaskTypePurge || taskType == TaskTypeScaleIn || taskType == TaskTypeScaleOut || taskType == TaskTypeCustomWorkflow
+	return taskType == TaskTypeDeploy || taskType == TaskTypeUnDeploy || taskType == TaskTypePurge || taskType == TaskTypeScaleIn || taskType == TaskTypeScaleOut || taskType == TaskTypeCustomWorkflow || taskType == TaskTypeUpdate
 }
 
 type taskDataNotFound struct {
@@ -143,7 +144,7 @@ func GetTaskType(kv *api.KV, taskID string) (TaskType, error) {
 	if err != nil {
 		return TaskTypeDeploy, errors.Wrapf(err, "Invalid task type:")
 	}
-	if typeInt < 0 || typeInt > int(TaskTypeForcePurge) {
+	if typeInt < 0 || typeInt > int(TaskTypeUpdate) {
 		return TaskTypeDeploy, errors.Errorf("Invalid type for task with id %q: %q", taskID, string(kvp.Value))
 	}
 	return TaskType(typeInt), nil
@@ -210,7 +211,7 @@ func DeleteTask(kv *api.KV, taskID string) error {
 
 // TargetHasLivingTasks checks if a targetID has associated tasks in status INITIAL or RUNNING and returns the id and status of the first one found
 //
-// Only Deploy, UnDeploy, ScaleOut, ScaleIn and Purge task type are considered.
+// Only Deploy, UnDeploy, ScaleOut, ScaleIn, Purge and Update task type are considered.
 func TargetHasLivingTasks(kv *api.KV, targetID string) (bool, string, string, error) {
 
 	taskIDs, err := GetTasksIdsForTarget(kv, targetID)
@@ -230,7 +231,7 @@ func TargetHasLivingTasks(kv *api.KV, targetID string) (bool, string, string, er
 		}
 
 		switch tType {
-		case TaskTypeDeploy, TaskTypeUnDeploy, TaskTypePurge, TaskTypeScaleIn, TaskTypeScaleOut:
+		case TaskTypeDeploy, TaskTypeUnDeploy, TaskTypePurge, TaskTypeScaleIn, TaskTypeScaleOut, TaskTypeUpdate:
 			if tStatus == TaskStatusINITIAL || tStatus == TaskStatusRUNNING {
 				return true, taskID, tStatus.String(), nil
 			}
@@ -302,6 +303,42 @@ func GetInstances(kv *api.KV, taskID, deploymentID, nodeName string) ([]string,
 	return strings.Split(string(kvp.Value), ","), nil
 }
 
+// contextKey is an unexported type for keys defined in this package.
+// This prevents collisions with keys defined in other packages.
+type contextKey int
+
+// nodesInstancesKey is the key for the instances of nodes restricted for an execution in Contexts.
+var nodesInstancesKey contextKey
+
+// WithNodeInstances returns a new Context restricting the instances of the given node to the given ones.
+//
+// This allows to run an operation on a subset of the instances of a node (a batch of instances for example)
+// without altering the task data shared by the other steps of the task. Those instances are returned by
+// GetInstancesWithContext.
+func WithNodeInstances(ctx context.Context, nodeName string, instances []string) context.Context {
+	nodesInstances := make(map[string][]string)
+	if existing, ok := ctx.Value(nodesInstancesKey).(map[string][]string); ok {
+		for k, v := range existing {
+			nodesInstances[k] = v
+		}
+	}
+	nodesInstances[nodeName] = instances
+	return context.WithValue(ctx, nodesInstancesKey, nodesInstances)
+}
+
+// GetInstancesWithContext works as GetInstances but returns instances restricted by WithNodeInstances for the
+// given node if any.
+func GetInstancesWithContext(ctx context.Context, kv *api.KV, taskID, deploymentID, nodeName string) ([]string, error) {
+	if ctx != nil {
+		if nodesInstances, ok := ctx.Value(nodesInstancesKey).(map[string][]string); ok {
+			if instances, ok := nodesInstances[nodeName]; ok {
+				return instances, nil
+			}
+		}
+	}
+	return GetInstances(kv, taskID, deploymentID, nodeName)
+}
+
 // GetTaskRelatedNodes returns the list of nodes that are specifically targeted by this task
 //
 // Currently it only appens for scaling tasks
@@ -340,7 +377,7 @@ func EmitTaskEventWithContextualLogs(ctx context.Context, kv *api.KV, deployment
 	switch taskType {
 	case TaskTypeCustomCommand:
 		return events.PublishAndLogCustomCommandStatusChange(ctx, kv, deploymentID, taskID, strings.ToLower(status))
-	case TaskTypeCustomWorkflow, TaskTypeDeploy, TaskTypeUnDeploy, TaskTypePurge:
+	case TaskTypeCustomWorkflow, TaskTypeDeploy, TaskTypeUnDeploy, TaskTypePurge, TaskTypeUpdate:
 		return events.PublishAndLogWorkflowStatusChange(ctx, kv, deploymentID, taskID, workflowName, strings.ToLower(status))
 	case TaskTypeScaleIn, TaskTypeScaleOut:
 		return events.PublishAndLogScalingStatusChange(ctx, kv, deploymentID, taskID, strings.ToLower(status))
@@ -357,12 +394,71 @@ func GetTaskRelatedSteps(kv *api.KV, taskID string) ([]TaskStep, error) {
 		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
 
+	attempts, err := getTaskStepsAttempts(kv, taskID)
+	if err != nil {
+		return nil, err
+	}
 	for _, kvp := range kvps {
-		steps = append(steps, TaskStep{Name: path.Base(kvp.Key), Status: string(kvp.Value)})
+		stepName := path.Base(kvp.Key)
+		steps = append(steps, TaskStep{Name: stepName, Status: string(kvp.Value), Attempts: attempts[stepName]})
 	}
 	return steps, nil
 }
 
+func getTaskStepsAttempts(kv *api.KV, taskID string) (map[string][]TaskStepAttempt, error) {
+	kvps, _, err := kv.List(path.Join(consulutil.TasksPrefix, taskID, "attempts")+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	attempts := make(map[string][]TaskStepAttempt, len(kvps))
+	for _, kvp := range kvps {
+		var stepAttempts []TaskStepAttempt
+		if err = json.Unmarshal(kvp.Value, &stepAttempts); err != nil {
+			return nil, errors.Wrapf(err, "failed to read attempts of step %q for task %q", path.Base(kvp.Key), taskID)
+		}
+		attempts[path.Base(kvp.Key)] = stepAttempts
+	}
+	return attempts, nil
+}
+
+// GetTaskStepAttempts returns the recorded attempts of a task step
+func GetTaskStepAttempts(kv *api.KV, taskID, stepName string) ([]TaskStepAttempt, error) {
+	kvp, _, err := kv.Get(path.Join(consulutil.TasksPrefix, taskID, "attempts", stepName), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || len(kvp.Value) == 0 {
+		return nil, nil
+	}
+	var attempts []TaskStepAttempt
+	err = json.Unmarshal(kvp.Value, &attempts)
+	return attempts, errors.Wrapf(err, "failed to read attempts of step %q for task %q", stepName, taskID)
+}
+
+// StoreTaskStepAttempt records an attempt of a task step
+//
+// An existing attempt with the same activity and number is replaced. Storing the first attempt of an activity
+// removes the attempts recorded for this activity by a previous run of the step (for instance before a task is resumed).
+func StoreTaskStepAttempt(kv *api.KV, taskID, stepName string, attempt TaskStepAttempt) error {
+	previous, err := GetTaskStepAttempts(kv, taskID, stepName)
+	if err != nil {
+		return err
+	}
+	attempts := make([]TaskStepAttempt, 0, len(previous)+1)
+	for _, a := range previous {
+		if a.Activity == attempt.Activity && (a.Attempt == attempt.Attempt || attempt.Attempt == 1) {
+			continue
+		}
+		attempts = append(attempts, a)
+	}
+	attempts = append(attempts, attempt)
+	b, err := json.Marshal(attempts)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store attempts of step %q for task %q", stepName, taskID)
+	}
+	return consulutil.StoreConsulKey(path.Join(consulutil.TasksPrefix, taskID, "attempts", stepName), b)
+}
+
 // GetTaskStepStatus returns the step status of the related step name
 func GetTaskStepStatus(kv *api.KV, taskID, stepName string) (TaskStepStatus, error) {
 	kvp, _, err := kv.Get(path.Join(consulutil.WorkflowsPrefix, taskID, stepName), nil)
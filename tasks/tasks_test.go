@@ -15,6 +15,7 @@
 package tasks
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path"
@@ -404,6 +405,47 @@ func testGetInstances(t *testing.T, kv *api.KV) {
 	}
 }
 
+func testGetInstancesWithContext(t *testing.T, kv *api.KV) {
+	ctx := WithNodeInstances(context.Background(), "node1", []string{"1"})
+	ctx = WithNodeInstances(ctx, "node2", []string{"0"})
+	type args struct {
+		ctx      context.Context
+		taskID   string
+		nodeName string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"RestrictedTaskRelatedNode", args{ctx, "t1", "node1"}, []string{"1"}},
+		{"RestrictedNode", args{ctx, "t1", "node2"}, []string{"0"}},
+		{"NotRestrictedNode", args{WithNodeInstances(context.Background(), "node2", []string{"0"}), "t1", "node1"}, []string{"0", "1", "2"}},
+		{"NoRestriction", args{context.Background(), "t2", "node2"}, []string{"0", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetInstancesWithContext(tt.args.ctx, kv, tt.args.taskID, "id1", tt.args.nodeName)
+			if err != nil {
+				t.Errorf("GetInstancesWithContext() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetInstancesWithContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// Task data are not altered
+	got, err := GetInstances(kv, "t1", "id1", "node1")
+	if err != nil {
+		t.Errorf("GetInstances() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, []string{"0", "1", "2"}) {
+		t.Errorf("GetInstances() = %v, want %v", got, []string{"0", "1", "2"})
+	}
+}
+
 func testGetTaskRelatedNodes(t *testing.T, kv *api.KV) {
 	type args struct {
 		kv     *api.KV
@@ -106,8 +106,18 @@ func buildStepFromWFStep(kv *api.KV, deploymentID, wfName, stepName string, wfSt
 		OperationHost:      wfStep.OperationHost,
 		TargetRelationship: wfStep.TargetRelationShip,
 		Target:             wfStep.Target,
+		Filter:             wfStep.Filter,
 		Activities:         make([]Activity, 0, len(wfStep.Activities)),
 	}
+	var err error
+	s.ExecutionPolicy, err = deployments.GetStepExecutionPolicy(wfStep)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid execution policy for step %q", stepName)
+	}
+	s.BatchPolicy, err = deployments.GetStepBatchPolicy(wfStep)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid batch policy for step %q", stepName)
+	}
 	var targetIsMandatory bool
 	for _, wfActivity := range wfStep.Activities {
 		if wfActivity.Delegate != "" {
@@ -134,7 +144,6 @@ func buildStepFromWFStep(kv *api.KV, deploymentID, wfName, stepName string, wfSt
 	}
 
 	s.Previous = make([]*Step, 0)
-	var err error
 	s.Next, err = buildStepsFromList(kv, deploymentID, wfName, stepName, s, wfSteps, wfStep.OnSuccess, visitedMap)
 	if err != nil {
 		return nil, err
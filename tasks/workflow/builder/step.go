@@ -14,6 +14,11 @@
 
 package builder
 
+import (
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
 // Step represents the workflow step
 type Step struct {
 	Name               string
@@ -29,6 +34,12 @@ type Step struct {
 	Async              bool
 	IsOnFailurePath    bool
 	IsOnCancelPath     bool
+	// ExecutionPolicy defines the timeout and retries of the step activities
+	ExecutionPolicy deployments.ExecutionPolicy
+	// BatchPolicy defines how the target instances are split into batches
+	BatchPolicy deployments.BatchPolicy
+	// Filter defines the conditions to be met for the step to be run
+	Filter []tosca.ConditionClause
 }
 
 type visitStep struct {
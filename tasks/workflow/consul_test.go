@@ -29,5 +29,17 @@ func TestRunConsulWorkflowPackageTests(t *testing.T) {
 		t.Run("testRunStep", func(t *testing.T) {
 			testRunStep(t, srv, client)
 		})
+		t.Run("testRunStepWithExecutionPolicy", func(t *testing.T) {
+			testRunStepWithExecutionPolicy(t, srv, client)
+		})
+		t.Run("testRunStepWithFilter", func(t *testing.T) {
+			testRunStepWithFilter(t, srv, client)
+		})
+		t.Run("testRunStepByBatches", func(t *testing.T) {
+			testRunStepByBatches(t, srv, client)
+		})
+		t.Run("testBuildPlan", func(t *testing.T) {
+			testBuildPlan(t, client)
+		})
 	})
 }
@@ -0,0 +1,250 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/prov/operations"
+	"github.com/ystia/yorc/v3/tasks"
+	"github.com/ystia/yorc/v3/tasks/workflow/builder"
+	"github.com/ystia/yorc/v3/tosca"
+)
+
+// redactedValue replaces the values of secret inputs in execution plans
+const redactedValue = "<secret value redacted>"
+
+// A Plan describes what a workflow would execute without actually running it
+type Plan struct {
+	DeploymentID string     `json:"deployment_id"`
+	WorkflowName string     `json:"workflow_name"`
+	Steps        []PlanStep `json:"steps"`
+
+	// Preconditions are evaluated at runtime, steps are skipped if they are not met
+	Preconditions []tosca.Precondition `json:"preconditions,omitempty"`
+}
+
+// A PlanStep describes a workflow step of an execution plan
+//
+// Steps that are only reachable on failure or cancellation are flagged as such and are listed after the other ones.
+type PlanStep struct {
+	Name               string         `json:"name"`
+	Target             string         `json:"target,omitempty"`
+	TargetRelationship string         `json:"target_relationship,omitempty"`
+	OperationHost      string         `json:"operation_host,omitempty"`
+	Instances          []string       `json:"instances,omitempty"`
+	Previous           []string       `json:"previous,omitempty"`
+	Next               []string       `json:"next,omitempty"`
+	OnFailure          []string       `json:"on_failure,omitempty"`
+	OnCancel           []string       `json:"on_cancel,omitempty"`
+	IsOnFailurePath    bool           `json:"is_on_failure_path,omitempty"`
+	IsOnCancelPath     bool           `json:"is_on_cancel_path,omitempty"`
+	Activities         []PlanActivity `json:"activities"`
+
+	// Filter is evaluated at runtime, the step is skipped if it is not met
+	Filter []tosca.ConditionClause `json:"filter,omitempty"`
+}
+
+// A PlanActivity describes an activity of a workflow step and how it would be executed
+type PlanActivity struct {
+	Type                   string        `json:"type"`
+	Value                  string        `json:"value"`
+	Executor               *PlanExecutor `json:"executor,omitempty"`
+	ImplementationArtifact string        `json:"implementation_artifact,omitempty"`
+	Inputs                 []PlanInput   `json:"inputs,omitempty"`
+	// Skipped is true for operations that are not implemented and so that are bypassed at runtime
+	Skipped bool `json:"skipped,omitempty"`
+	// Error reports why this activity would fail at runtime
+	Error string `json:"error,omitempty"`
+}
+
+// A PlanExecutor describes the registry entry selected to execute an activity
+type PlanExecutor struct {
+	Match  string `json:"match"`
+	Origin string `json:"origin"`
+}
+
+// A PlanInput is a resolved operation input, values of secrets are redacted
+type PlanInput struct {
+	Name         string `json:"name"`
+	InstanceName string `json:"instance_name,omitempty"`
+	Value        string `json:"value"`
+	IsSecret     bool   `json:"is_secret,omitempty"`
+}
+
+// BuildPlan computes the execution plan of a deployment workflow.
+//
+// Operations inputs are resolved and executors are selected exactly as they would be when running the workflow
+// but no executor is called.
+func BuildPlan(ctx context.Context, kv *api.KV, deploymentID, workflowName string) (*Plan, error) {
+	wfSteps, err := builder.BuildWorkFlow(kv, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{DeploymentID: deploymentID, WorkflowName: workflowName, Steps: make([]PlanStep, 0, len(wfSteps))}
+	plan.Preconditions, err = deployments.GetWorkflowPreconditions(kv, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range orderSteps(wfSteps) {
+		ps := PlanStep{
+			Name:               s.Name,
+			Target:             s.Target,
+			TargetRelationship: s.TargetRelationship,
+			OperationHost:      s.OperationHost,
+			Previous:           stepsNames(s.Previous),
+			Next:               stepsNames(s.Next),
+			OnFailure:          stepsNames(s.OnFailure),
+			OnCancel:           stepsNames(s.OnCancel),
+			IsOnFailurePath:    s.IsOnFailurePath,
+			IsOnCancelPath:     s.IsOnCancelPath,
+			Filter:             s.Filter,
+			Activities:         make([]PlanActivity, 0, len(s.Activities)),
+		}
+		if s.Target != "" {
+			ps.Instances, err = tasks.GetInstances(kv, "", deploymentID, s.Target)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, a := range s.Activities {
+			pa, err := planActivity(ctx, kv, deploymentID, s, a)
+			if err != nil {
+				return nil, err
+			}
+			ps.Activities = append(ps.Activities, pa)
+		}
+		plan.Steps = append(plan.Steps, ps)
+	}
+	return plan, nil
+}
+
+func planActivity(ctx context.Context, kv *api.KV, deploymentID string, s *builder.Step, activity builder.Activity) (PlanActivity, error) {
+	pa := PlanActivity{Type: activity.Type().String(), Value: activity.Value()}
+	switch activity.Type() {
+	case builder.ActivityTypeDelegate:
+		nodeType, err := deployments.GetNodeType(kv, deploymentID, s.Target)
+		if err != nil {
+			return pa, err
+		}
+		m, err := resolveDelegateExecutor(nodeType)
+		if err != nil {
+			pa.Error = err.Error()
+			return pa, nil
+		}
+		pa.Executor = &PlanExecutor{Match: m.Match, Origin: m.Origin}
+	case builder.ActivityTypeCallOperation:
+		op, err := operations.GetOperation(ctx, kv, deploymentID, s.Target, activity.Value(), s.TargetRelationship, s.OperationHost)
+		if err != nil {
+			if deployments.IsOperationNotImplemented(err) {
+				pa.Skipped = true
+				return pa, nil
+			}
+			pa.Error = err.Error()
+			return pa, nil
+		}
+		pa.ImplementationArtifact = op.ImplementationArtifact
+		m, err := resolveOperationExecutor(kv, deploymentID, op.ImplementationArtifact)
+		if err != nil {
+			pa.Error = err.Error()
+			return pa, nil
+		}
+		pa.Executor = &PlanExecutor{Match: m.Artifact, Origin: m.Origin}
+		envInputs, _, err := operations.ResolveInputs(ctx, kv, deploymentID, s.Target, "", op)
+		if err != nil {
+			pa.Error = errors.Wrap(err, "failed to resolve operation inputs").Error()
+			return pa, nil
+		}
+		pa.Inputs = make([]PlanInput, len(envInputs))
+		for i, ei := range envInputs {
+			pa.Inputs[i] = PlanInput{Name: ei.Name, InstanceName: ei.InstanceName, Value: ei.Value, IsSecret: ei.IsSecret}
+			if ei.IsSecret {
+				pa.Inputs[i].Value = redactedValue
+			}
+		}
+	}
+	return pa, nil
+}
+
+// orderSteps sorts workflow steps in an order compatible with their execution.
+//
+// Steps that can run concurrently are sorted by name and steps only reachable on failure or cancellation come last.
+func orderSteps(wfSteps map[string]*builder.Step) []*builder.Step {
+	names := make([]string, 0, len(wfSteps))
+	for name := range wfSteps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Only on_success links are considered, on_failure and on_cancel ones are handled below
+	refCount := make(map[string]int, len(wfSteps))
+	for _, s := range wfSteps {
+		for _, next := range s.Next {
+			refCount[next.Name]++
+		}
+	}
+	ready := make([]string, 0)
+	for _, name := range names {
+		if refCount[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+	ordered := make([]*builder.Step, 0, len(wfSteps))
+	visited := make(map[string]bool, len(wfSteps))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		visited[name] = true
+		ordered = append(ordered, wfSteps[name])
+		for _, next := range wfSteps[name].Next {
+			refCount[next.Name]--
+			if refCount[next.Name] == 0 {
+				ready = append(ready, next.Name)
+			}
+		}
+	}
+	// Remaining steps are part of a cycle, keep them anyway
+	for _, name := range names {
+		if !visited[name] {
+			ordered = append(ordered, wfSteps[name])
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !isOnErrorPath(ordered[i]) && isOnErrorPath(ordered[j])
+	})
+	return ordered
+}
+
+func isOnErrorPath(s *builder.Step) bool {
+	return s.IsOnFailurePath || s.IsOnCancelPath
+}
+
+func stepsNames(steps []*builder.Step) []string {
+	if len(steps) == 0 {
+		return nil
+	}
+	names := make([]string, len(steps))
+	for i := range steps {
+		names[i] = steps[i].Name
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v3/deployments"
+	"github.com/ystia/yorc/v3/registry"
+	"github.com/ystia/yorc/v3/tasks/workflow/builder"
+)
+
+func testBuildPlan(t *testing.T, cc *api.Client) {
+	kv := cc.KV()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/plan.yaml")
+	require.NoError(t, err)
+
+	mockExecutor := &mockExecutor{}
+	registry.GetRegistry().RegisterDelegates([]string{"ystia.yorc.tests.nodes.PlanCompute"}, mockExecutor, "plan-tests")
+	registry.GetRegistry().RegisterOperationExecutor([]string{"ystia.yorc.tests.artifacts.Implementation.Plan"}, mockExecutor, "plan-tests")
+
+	plan, err := BuildPlan(context.Background(), kv, deploymentID, "install")
+	require.NoError(t, err)
+	require.False(t, mockExecutor.delegateCalled, "no executor should be called by a plan")
+	require.False(t, mockExecutor.callOpsCalled, "no executor should be called by a plan")
+	require.Equal(t, deploymentID, plan.DeploymentID)
+	require.Equal(t, "install", plan.WorkflowName)
+
+	stepsNames := make([]string, len(plan.Steps))
+	for i, s := range plan.Steps {
+		stepsNames[i] = s.Name
+	}
+	require.Equal(t, []string{"Compute_install", "PlanNode_create", "PlanNode_configure", "PlanNode_start", "PlanNode_error"}, stepsNames)
+
+	computeInstall := plan.Steps[0]
+	require.Equal(t, []string{"0", "1"}, computeInstall.Instances)
+	require.Len(t, computeInstall.Activities, 1)
+	require.Equal(t, builder.ActivityTypeDelegate.String(), computeInstall.Activities[0].Type)
+	require.Equal(t, &PlanExecutor{Match: "ystia.yorc.tests.nodes.PlanCompute", Origin: "plan-tests"}, computeInstall.Activities[0].Executor)
+
+	create := plan.Steps[1].Activities[0]
+	require.Equal(t, "ystia.yorc.tests.artifacts.Implementation.PlanChild", create.ImplementationArtifact)
+	// Executor is found using the parent artifact type
+	require.Equal(t, &PlanExecutor{Match: "ystia.yorc.tests.artifacts.Implementation.Plan", Origin: "plan-tests"}, create.Executor)
+	require.Empty(t, create.Error)
+	require.Len(t, create.Inputs, 4)
+	for _, input := range create.Inputs {
+		switch input.Name {
+		case "GREETING":
+			require.Equal(t, "hello", input.Value)
+		case "LITERAL":
+			require.Equal(t, "literal value", input.Value)
+		default:
+			t.Errorf("unexpected input %q", input.Name)
+		}
+	}
+
+	configure := plan.Steps[2]
+	require.Equal(t, []string{"PlanNode_error"}, configure.OnFailure)
+	require.Nil(t, configure.Activities[0].Executor)
+	require.Contains(t, configure.Activities[0].Error, "ystia.yorc.tests.artifacts.Implementation.Unsupported")
+
+	require.True(t, plan.Steps[3].Activities[0].Skipped, "not implemented operations should be skipped")
+
+	require.True(t, plan.Steps[4].IsOnFailurePath)
+	require.Equal(t, builder.ActivityTypeSetState.String(), plan.Steps[4].Activities[0].Type)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,7 +34,6 @@ import (
 	"github.com/ystia/yorc/v3/log"
 	"github.com/ystia/yorc/v3/prov/operations"
 	"github.com/ystia/yorc/v3/prov/scheduling"
-	"github.com/ystia/yorc/v3/registry"
 	"github.com/ystia/yorc/v3/tasks"
 	"github.com/ystia/yorc/v3/tasks/workflow/builder"
 	"github.com/ystia/yorc/v3/tosca"
@@ -154,6 +154,58 @@ func (s *step) isRunnable() (bool, error) {
 	return true, nil
 }
 
+// conditionsMet checks if the preconditions of the workflow and the filter of the step are met
+//
+// Workflow preconditions are evaluated only once by task, their result is stored in the task data.
+func (s *step) conditionsMet(kv *api.KV, deploymentID string) (bool, error) {
+	met, err := s.workflowPreconditionsMet(kv, deploymentID)
+	if err != nil || !met || len(s.Filter) == 0 {
+		return met, err
+	}
+	var instances []string
+	if s.Target != "" {
+		instances, err = tasks.GetInstances(kv, s.t.taskID, deploymentID, s.Target)
+		if err != nil {
+			return false, err
+		}
+	}
+	met, err = deployments.EvaluateConditions(kv, deploymentID, s.Target, instances, s.Filter)
+	return met, errors.Wrapf(err, "failed to evaluate filter of step %q", s.Name)
+}
+
+func (s *step) workflowPreconditionsMet(kv *api.KV, deploymentID string) (bool, error) {
+	dataName := path.Join("preconditionsMet", s.WorkflowName)
+	res, err := tasks.GetTaskData(kv, s.t.taskID, dataName)
+	if err == nil {
+		return strconv.ParseBool(res)
+	}
+	if !tasks.IsTaskDataNotFoundError(err) {
+		return false, err
+	}
+	preconditions, err := deployments.GetWorkflowPreconditions(kv, deploymentID, s.WorkflowName)
+	if err != nil {
+		return false, err
+	}
+	met := true
+	for _, p := range preconditions {
+		var instances []string
+		if p.Target != "" {
+			instances, err = tasks.GetInstances(kv, s.t.taskID, deploymentID, p.Target)
+			if err != nil {
+				return false, err
+			}
+		}
+		met, err = deployments.EvaluateConditions(kv, deploymentID, p.Target, instances, p.Condition)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to evaluate preconditions of workflow %q", s.WorkflowName)
+		}
+		if !met {
+			break
+		}
+	}
+	return met, tasks.SetTaskData(kv, s.t.taskID, dataName, strconv.FormatBool(met))
+}
+
 // run allows to execute a workflow step
 func (s *step) run(ctx context.Context, cfg config.Configuration, kv *api.KV, deploymentID string, bypassErrors bool, workflowName string, w *worker) error {
 	// Fill log optional fields for log registration
@@ -167,6 +219,19 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, kv *api.KV, de
 		s.setStatus(tasks.TaskStepStatusDONE)
 		return nil
 	}
+	if met, err := s.conditionsMet(kv, deploymentID); err != nil {
+		return err
+	} else if !met {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Skipping TaskStep %q as its conditions are not met", s.Name)
+		// Next steps are registered by the caller of synchronous steps only
+		s.Async = false
+		s.setStatus(tasks.TaskStepStatusSKIPPED)
+		return nil
+	}
+	batches, pause, err := s.getInstancesBatches(ctx, kv, deploymentID)
+	if err != nil {
+		return err
+	}
 	s.setStatus(tasks.TaskStepStatusRUNNING)
 
 	ctx, cancelWf := context.WithCancel(ctx)
@@ -197,6 +262,30 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, kv *api.KV, de
 	}
 
 	log.Debugf("Processing Step %q", s.Name)
+	if len(batches) > 1 {
+		err = s.runBatches(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, batches, pause)
+	} else {
+		err = s.runActivities(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if !s.Async {
+		log.Debugf("Task execution:%q for step:%q, workflow:%q, taskID:%q done without error.", s.t.id, s.Name, s.WorkflowName, s.t.taskID)
+		s.setStatus(tasks.TaskStepStatusDONE)
+	}
+	return nil
+}
+
+// runActivities runs sequentially all the activities of the step
+//
+// If instances is not nil, activities are run on those instances of the target only, otherwise they are run on all instances
+// of the target in the context of the task.
+func (s *step) runActivities(ctx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, instances []string) error {
+	if instances != nil {
+		// Executors, hooks and state changes retrieve instances from the context
+		ctx = tasks.WithNodeInstances(ctx, s.Target, instances)
+	}
 	for _, activity := range s.Activities {
 		err := func() error {
 			for _, hook := range preActivityHooks {
@@ -207,7 +296,7 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, kv *api.KV, de
 					hook(ctx, cfg, s.t.taskID, deploymentID, s.Target, activity)
 				}
 			}()
-			err := s.runActivity(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity)
+			err := s.runActivity(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity, instances)
 			if err != nil {
 				setNodeStatus(ctx, kv, s.t.taskID, deploymentID, s.Target, tosca.NodeStateError.String())
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("TaskStep %q: error details: %+v", s.Name, err)
@@ -229,19 +318,213 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, kv *api.KV, de
 			return err
 		}
 	}
-	if !s.Async {
-		log.Debugf("Task execution:%q for step:%q, workflow:%q, taskID:%q done without error.", s.t.id, s.Name, s.WorkflowName, s.t.taskID)
-		s.setStatus(tasks.TaskStepStatusDONE)
-	}
 	return nil
 }
 
-func (s *step) runActivity(wfCtx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, activity builder.Activity) error {
-	// Get activity related instances
+// getInstancesBatches returns the batches of target instances the step activities should be run on
+//
+// Batch policy parameters defined on the task override the ones defined on the step.
+// Asynchronous steps are never run by batches as they return before the end of their operations.
+func (s *step) getInstancesBatches(ctx context.Context, kv *api.KV, deploymentID string) ([][]string, time.Duration, error) {
+	policy := s.BatchPolicy
+	batchSize, err := tasks.GetTaskData(kv, s.t.taskID, "batchSize")
+	if err != nil && !tasks.IsTaskDataNotFoundError(err) {
+		return nil, 0, err
+	}
+	if err == nil {
+		policy.BatchSize, err = strconv.Atoi(batchSize)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to parse \"batchSize\" task parameter")
+		}
+	}
+	pause, err := tasks.GetTaskData(kv, s.t.taskID, "pauseBetweenBatches")
+	if err != nil && !tasks.IsTaskDataNotFoundError(err) {
+		return nil, 0, err
+	}
+	if err == nil {
+		policy.PauseBetweenBatches, err = time.ParseDuration(pause)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to parse \"pauseBetweenBatches\" task parameter")
+		}
+	}
+	if policy.BatchSize == 0 || s.Target == "" {
+		return nil, 0, nil
+	}
+	if s.Async {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("TaskStep %q: batches are ignored for asynchronous operations", s.Name)
+		return nil, 0, nil
+	}
 	instances, err := tasks.GetInstances(kv, s.t.taskID, deploymentID, s.Target)
+	if err != nil {
+		return nil, 0, err
+	}
+	return policy.Batches(instances), policy.PauseBetweenBatches, nil
+}
+
+// runBatches runs the step activities on batches of instances one after the other
+//
+// Instances of the current batch are given to the activities of this step only, the task data shared by concurrent steps
+// are left untouched. Remaining batches are not run if a batch fails.
+func (s *step) runBatches(ctx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, batches [][]string, pause time.Duration) error {
+	for i, batch := range batches {
+		if i > 0 && pause > 0 {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("TaskStep %q: waiting %s before running next batch", s.Name, pause)
+			select {
+			case <-time.After(pause):
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "TaskStep %q interrupted between batches", s.Name)
+			}
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("TaskStep %q: running batch %d/%d on instances %s", s.Name, i+1, len(batches), strings.Join(batch, ", "))
+		err := s.runActivities(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, batch)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runActivity runs an activity according to the execution policy of the step and of the called operation if any
+//
+// Each attempt is run with the policy timeout, failed attempts are retried after a backoff delay if the policy allows it.
+func (s *step) runActivity(wfCtx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, activity builder.Activity, instances []string) error {
+	policy, err := s.getExecutionPolicy(wfCtx, kv, deploymentID, activity)
 	if err != nil {
 		return err
 	}
+	if policy.IsZero() {
+		return s.runActivityAttempt(wfCtx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity, instances)
+	}
+
+	activityName := fmt.Sprintf("%s %s", activity.Type(), activity.Value())
+	maxAttempts := policy.MaxRetries + 1
+	for attempt := 1; ; attempt++ {
+		record := tasks.TaskStepAttempt{Activity: activityName, Attempt: attempt, Status: tasks.TaskStepStatusRUNNING.String(), StartDate: time.Now()}
+		s.storeAttempt(wfCtx, deploymentID, record)
+		events.WithContextOptionalFields(wfCtx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("TaskStep %q: running attempt %d/%d of activity %s", s.Name, attempt, maxAttempts, activityName)
+
+		timedOut, err := s.runActivityAttemptWithTimeout(wfCtx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity, instances, policy.Timeout)
+		endDate := time.Now()
+		record.EndDate = &endDate
+		if err == nil {
+			record.Status = tasks.TaskStepStatusDONE.String()
+			s.storeAttempt(wfCtx, deploymentID, record)
+			return nil
+		}
+		record.Status = tasks.TaskStepStatusERROR.String()
+		record.Error = err.Error()
+		s.storeAttempt(wfCtx, deploymentID, record)
+
+		errorClass := deployments.RetryOnError
+		if timedOut {
+			errorClass = deployments.RetryOnTimeout
+		}
+		if wfCtx.Err() != nil || attempt >= maxAttempts || !policy.ShouldRetry(errorClass) || isAttemptStillRunningError(err) {
+			return err
+		}
+		delay := policy.Backoff(attempt)
+		events.WithContextOptionalFields(wfCtx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("TaskStep %q: attempt %d/%d of activity %s failed: %v. Retrying in %s.", s.Name, attempt, maxAttempts, activityName, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-wfCtx.Done():
+			return err
+		}
+	}
+}
+
+// getExecutionPolicy returns the execution policy of an activity
+//
+// Delegate and call-operation activities only are concerned, values defined on the step take precedence over the ones defined by operation metadata.
+func (s *step) getExecutionPolicy(ctx context.Context, kv *api.KV, deploymentID string, activity builder.Activity) (deployments.ExecutionPolicy, error) {
+	switch activity.Type() {
+	case builder.ActivityTypeDelegate:
+		return s.ExecutionPolicy, nil
+	case builder.ActivityTypeCallOperation:
+		op, err := operations.GetOperation(ctx, kv, deploymentID, s.Target, activity.Value(), s.TargetRelationship, s.OperationHost)
+		if err != nil {
+			if deployments.IsOperationNotImplemented(err) {
+				return s.ExecutionPolicy, nil
+			}
+			return deployments.ExecutionPolicy{}, err
+		}
+		opPolicy, err := deployments.GetOperationExecutionPolicy(kv, deploymentID, op)
+		if err != nil {
+			return deployments.ExecutionPolicy{}, err
+		}
+		return s.ExecutionPolicy.Merge(opPolicy), nil
+	}
+	return deployments.ExecutionPolicy{}, nil
+}
+
+func (s *step) storeAttempt(ctx context.Context, deploymentID string, attempt tasks.TaskStepAttempt) {
+	err := tasks.StoreTaskStepAttempt(s.cc.KV(), s.t.taskID, s.Name, attempt)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("TaskStep %q: failed to record attempt %d: %v", s.Name, attempt.Attempt, err)
+	}
+}
+
+// attemptStopTimeout is the maximum duration to wait for a timed out attempt to stop once its context is cancelled
+var attemptStopTimeout = time.Minute
+
+// attemptStillRunningError is returned when a timed out attempt doesn't stop, such an attempt should not be retried
+// as a new attempt would run concurrently
+type attemptStillRunningError struct {
+	cause error
+}
+
+func (e attemptStillRunningError) Error() string {
+	return fmt.Sprintf("%v, the attempt did not stop within %s after being cancelled", e.cause, attemptStopTimeout)
+}
+
+func isAttemptStillRunningError(err error) bool {
+	_, ok := errors.Cause(err).(attemptStillRunningError)
+	return ok
+}
+
+// runActivityAttemptWithTimeout runs an activity attempt and returns when the given timeout (if not 0) is reached
+//
+// The attempt context is cancelled on timeout, then the attempt is waited for at most attemptStopTimeout.
+// An attemptStillRunningError is returned if executors do not honor the cancellation within this delay.
+func (s *step) runActivityAttemptWithTimeout(wfCtx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, activity builder.Activity, instances []string, timeout time.Duration) (bool, error) {
+	if timeout == 0 {
+		return false, s.runActivityAttempt(wfCtx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity, instances)
+	}
+	ctx, cancel := context.WithTimeout(wfCtx, timeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.runActivityAttempt(ctx, kv, cfg, deploymentID, workflowName, bypassErrors, w, activity, instances)
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			return true, errors.Wrapf(err, "activity %s %s timed out after %s", activity.Type(), activity.Value(), timeout)
+		}
+		return false, err
+	case <-ctx.Done():
+		if wfCtx.Err() != nil {
+			// Workflow cancellation, let the executor gracefully stop
+			return false, <-errCh
+		}
+		err := errors.Errorf("activity %s %s timed out after %s", activity.Type(), activity.Value(), timeout)
+		select {
+		case <-errCh:
+			return true, err
+		case <-time.After(attemptStopTimeout):
+			return true, attemptStillRunningError{cause: err}
+		}
+	}
+}
+
+func (s *step) runActivityAttempt(wfCtx context.Context, kv *api.KV, cfg config.Configuration, deploymentID, workflowName string, bypassErrors bool, w *worker, activity builder.Activity, instances []string) error {
+	// Get activity related instances if they are not restricted to a batch
+	var err error
+	if instances == nil {
+		instances, err = tasks.GetInstances(kv, s.t.taskID, deploymentID, s.Target)
+		if err != nil {
+			return err
+		}
+	}
 
 	eventInfo := &events.WorkflowStepInfo{WorkflowName: workflowName, NodeName: s.Target, StepName: s.Name}
 	switch activity.Type() {
@@ -250,10 +533,11 @@ func (s *step) runActivity(wfCtx context.Context, kv *api.KV, cfg config.Configu
 		if err != nil {
 			return err
 		}
-		provisioner, err := registry.GetRegistry().GetDelegateExecutor(nodeType)
+		delegateMatch, err := resolveDelegateExecutor(nodeType)
 		if err != nil {
 			return err
 		}
+		provisioner := delegateMatch.Executor
 		delegateOp := activity.Value()
 		wfCtx = events.AddLogOptionalFields(wfCtx, events.LogOptionalFields{events.InterfaceName: "delegate", events.OperationName: delegateOp})
 		for _, instanceName := range instances {
@@ -290,10 +574,11 @@ func (s *step) runActivity(wfCtx context.Context, kv *api.KV, cfg config.Configu
 			return err
 		}
 
-		exec, err := getOperationExecutor(kv, deploymentID, op.ImplementationArtifact)
+		execMatch, err := resolveOperationExecutor(kv, deploymentID, op.ImplementationArtifact)
 		if err != nil {
 			return err
 		}
+		exec := execMatch.Executor
 		nodeType, err := deployments.GetNodeType(kv, deploymentID, s.Target)
 		if err != nil {
 			return err
@@ -391,7 +676,7 @@ func (s *step) checkIfPreviousOfNextStepAreDone(ctx context.Context, nextStep *s
 		if err != nil {
 			return false, errors.Wrapf(err, "Failed to retrieve step status with TaskID:%q, step:%q", s.t.taskID, step.Name)
 		}
-		if stepStatus == tasks.TaskStepStatusDONE {
+		if stepStatus == tasks.TaskStepStatusDONE || stepStatus == tasks.TaskStepStatusSKIPPED {
 			cpt++
 		} else if stepStatus == tasks.TaskStepStatusCANCELED || stepStatus == tasks.TaskStepStatusERROR {
 			return false, errors.Errorf("An error has been detected on other step:%q for workflow:%q, deploymentID:%q, taskID:%q. No more steps will be executed", step.Name, workflowName, s.t.targetID, s.t.taskID)
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -92,7 +93,7 @@ func (w *worker) Start() {
 }
 
 func setNodeStatus(ctx context.Context, kv *api.KV, taskID, deploymentID, nodeName, status string) error {
-	instancesIDs, err := tasks.GetInstances(kv, taskID, deploymentID, nodeName)
+	instancesIDs, err := tasks.GetInstancesWithContext(ctx, kv, taskID, deploymentID, nodeName)
 	if err != nil {
 		return err
 	}
@@ -107,25 +108,45 @@ func setNodeStatus(ctx context.Context, kv *api.KV, taskID, deploymentID, nodeNa
 	return nil
 }
 
-func getOperationExecutor(kv *api.KV, deploymentID, artifact string) (prov.OperationExecutor, error) {
-	reg := registry.GetRegistry()
+// resolveDelegateExecutor returns the registry entry of the executor running delegate operations of the given node type
+//
+// It is used to run delegate operations as well as to compute execution plans.
+func resolveDelegateExecutor(nodeType string) (registry.DelegateMatch, error) {
+	for _, m := range registry.GetRegistry().ListDelegateExecutors() {
+		ok, err := regexp.MatchString(m.Match, nodeType)
+		if err != nil {
+			return registry.DelegateMatch{}, errors.Wrapf(err, "Failed to match delegate executor from nodeType %q", nodeType)
+		}
+		if ok {
+			return m, nil
+		}
+	}
+	return registry.DelegateMatch{}, errors.Errorf("Unsupported node type %q for a delegate operation", nodeType)
+}
 
-	exec, originalErr := reg.GetOperationExecutor(artifact)
-	if originalErr == nil {
-		return exec, nil
+// resolveOperationExecutor returns the registry entry of the executor running operations implemented by the given artifact type
+//
+// If no executor is registered for this artifact type, executors of its parent types are looked up.
+// It is used to run operations as well as to compute execution plans.
+func resolveOperationExecutor(kv *api.KV, deploymentID, artifact string) (registry.OperationExecMatch, error) {
+	for _, m := range registry.GetRegistry().ListOperationExecutors() {
+		if m.Artifact == artifact {
+			return m, nil
+		}
 	}
 	// Try to get an executor for artifact parent type but return the original error if we do not found any executors
+	originalErr := errors.Errorf("Unsupported artifact implementation %q for a call-operation", artifact)
 	parentArt, err := deployments.GetParentType(kv, deploymentID, artifact)
 	if err != nil {
-		return nil, err
+		return registry.OperationExecMatch{}, err
 	}
 	if parentArt != "" {
-		exec, err := getOperationExecutor(kv, deploymentID, parentArt)
+		m, err := resolveOperationExecutor(kv, deploymentID, parentArt)
 		if err == nil {
-			return exec, nil
+			return m, nil
 		}
 	}
-	return nil, originalErr
+	return registry.OperationExecMatch{}, originalErr
 }
 
 // cleanupScaledDownNodes removes nodes instances from Consul
@@ -216,6 +237,8 @@ func (w *worker) handleExecution(t *taskExecution) {
 		err = w.runScaleIn(ctx, t)
 	case tasks.TaskTypeCustomWorkflow:
 		err = w.runCustomWorkflow(ctx, t, wfName)
+	case tasks.TaskTypeUpdate:
+		err = w.runUpdate(ctx, t)
 	case tasks.TaskTypeAction:
 		err = w.runAction(ctx, t)
 	case tasks.TaskTypeQuery, tasks.TaskTypeCustomCommand, tasks.TaskTypeForcePurge:
@@ -307,7 +330,7 @@ func (w *worker) runCustomCommand(ctx context.Context, t *taskExecution) (contex
 		}
 		return ctx, errors.Wrapf(err, "Command TaskExecution failed for node %q", nodeName)
 	}
-	exec, err := getOperationExecutor(kv, t.targetID, op.ImplementationArtifact)
+	execMatch, err := resolveOperationExecutor(kv, t.targetID, op.ImplementationArtifact)
 	if err != nil {
 		err = setNodeStatus(ctx, t.cc.KV(), t.taskID, t.targetID, nodeName, tosca.NodeStateError.String())
 		if err != nil {
@@ -315,6 +338,7 @@ func (w *worker) runCustomCommand(ctx context.Context, t *taskExecution) (contex
 		}
 		return ctx, errors.Wrapf(err, "Command TaskExecution failed for node %q", nodeName)
 	}
+	exec := execMatch.Executor
 
 	ctx = operations.SetOperationLogFields(ctx, op)
 	ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.NodeID: nodeName, events.OperationName: op.Name})
@@ -587,7 +611,12 @@ func (w *worker) runUndeploy(ctx context.Context, t *taskExecution) error {
 
 func (w *worker) runPurge(ctx context.Context, t *taskExecution) error {
 	kv := w.consulClient.KV()
-	_, err := kv.DeleteTree(path.Join(consulutil.DeploymentKVPrefix, t.targetID), nil)
+	// Stop scheduled actions of this deployment (like scheduled workflows)
+	err := scheduling.UnregisterDeploymentActions(w.consulClient, t.targetID)
+	if err != nil {
+		return err
+	}
+	_, err = kv.DeleteTree(path.Join(consulutil.DeploymentKVPrefix, t.targetID), nil)
 	if err != nil {
 		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
 	}
@@ -636,7 +665,7 @@ func (w *worker) runScaleOut(ctx context.Context, t *taskExecution) error {
 	if err != nil {
 		return err
 	}
-	if status == deployments.DEPLOYED {
+	if status == deployments.DEPLOYED || status == deployments.UPDATED {
 		nodeName, err := tasks.GetTaskData(t.cc.KV(), t.taskID, "nodeName")
 		if err != nil {
 			return errors.Wrap(err, "failed to retrieve scale out node name")
@@ -693,6 +722,31 @@ func (w *worker) runScaleIn(ctx context.Context, t *taskExecution) error {
 	return w.runWorkflowStep(ctx, t, "uninstall", true)
 }
 
+func (w *worker) runUpdate(ctx context.Context, t *taskExecution) error {
+	kv := w.consulClient.KV()
+	err := deployments.SetDeploymentStatus(ctx, kv, t.targetID, deployments.UPDATE_IN_PROGRESS)
+	if err != nil {
+		return err
+	}
+	t.finalFunction = func() error {
+		taskStatus, err := updateTaskStatusAccordingToWorkflowStatus(ctx, kv, t.targetID, t.taskID, deployments.UpdateWorkflowName)
+		if err != nil {
+			return err
+		}
+		if taskStatus != tasks.TaskStatusDONE {
+			// Keep removed nodes to allow to resume the update
+			return deployments.SetDeploymentStatus(ctx, kv, t.targetID, deployments.UPDATE_FAILURE)
+		}
+		err = deployments.CleanupRemovedNodes(kv, t.targetID)
+		if err != nil {
+			return err
+		}
+		return deployments.SetDeploymentStatus(ctx, kv, t.targetID, deployments.UPDATED)
+	}
+
+	return w.runWorkflowStep(ctx, t, deployments.UpdateWorkflowName, false)
+}
+
 func (w *worker) runCustomWorkflow(ctx context.Context, t *taskExecution, wfName string) error {
 	kv := w.consulClient.KV()
 	if wfName == "" {
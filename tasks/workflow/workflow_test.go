@@ -17,6 +17,7 @@ package workflow
 import (
 	"context"
 	"path"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -31,7 +32,9 @@ import (
 	"github.com/ystia/yorc/v3/helper/consulutil"
 	"github.com/ystia/yorc/v3/prov"
 	"github.com/ystia/yorc/v3/registry"
+	"github.com/ystia/yorc/v3/tasks"
 	"github.com/ystia/yorc/v3/tasks/workflow/builder"
+	"github.com/ystia/yorc/v3/tosca"
 )
 
 type mockExecutor struct {
@@ -180,6 +183,199 @@ func testRunStep(t *testing.T, srv1 *testutil.TestServer, cc *api.Client) {
 	}
 }
 
+type flakyExecutor struct {
+	mockExecutor
+	nbFailures int
+	calls      int
+	// if hang is set calls block until cancellation, or for an hour if ignoreCancel is also set
+	hang         bool
+	ignoreCancel bool
+	// if set instances of each call are recorded
+	kv        *api.KV
+	instances []string
+}
+
+func (m *flakyExecutor) ExecDelegate(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	m.calls++
+	if m.kv != nil {
+		instances, err := tasks.GetInstancesWithContext(ctx, m.kv, taskID, deploymentID, nodeName)
+		if err != nil {
+			return err
+		}
+		m.instances = append(m.instances, strings.Join(instances, ","))
+	}
+	if m.hang && m.ignoreCancel {
+		// Ignore cancellation to check that timeouts are enforced anyway
+		time.Sleep(time.Hour)
+	} else if m.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if m.calls <= m.nbFailures {
+		return errors.New("Failed required for mock")
+	}
+	return nil
+}
+
+func testRunStepWithExecutionPolicy(t *testing.T, srv1 *testutil.TestServer, cc *api.Client) {
+	kv := cc.KV()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/workflow.yaml")
+	require.Nil(t, err)
+
+	defer func(d time.Duration) { attemptStopTimeout = d }(attemptStopTimeout)
+	attemptStopTimeout = 200 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		policy       deployments.ExecutionPolicy
+		nbFailures   int
+		hang         bool
+		ignoreCancel bool
+		wantCalls    int
+		wantAttempts []string
+		wantErr      bool
+	}{
+		{"RetryUntilSuccess", deployments.ExecutionPolicy{MaxRetries: 3, RetryBackoff: time.Millisecond}, 2, false, false, 3, []string{"error", "error", "done"}, false},
+		{"RetriesExhausted", deployments.ExecutionPolicy{MaxRetries: 1}, 5, false, false, 2, []string{"error", "error"}, true},
+		{"NoRetryOnErrors", deployments.ExecutionPolicy{MaxRetries: 3, RetryOn: []string{deployments.RetryOnTimeout}}, 5, false, false, 1, []string{"error"}, true},
+		{"Timeout", deployments.ExecutionPolicy{Timeout: 100 * time.Millisecond}, 0, true, false, 1, []string{"error"}, true},
+		{"RetryOnTimeout", deployments.ExecutionPolicy{Timeout: 100 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond}, 0, true, false, 2, []string{"error", "error"}, true},
+		{"NoRetryOfRunningAttempt", deployments.ExecutionPolicy{Timeout: 100 * time.Millisecond, MaxRetries: 3, RetryBackoff: time.Millisecond}, 0, true, true, 1, []string{"error"}, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &flakyExecutor{nbFailures: tt.nbFailures, hang: tt.hang, ignoreCancel: tt.ignoreCancel}
+			registry.GetRegistry().RegisterDelegates([]string{"ystia.yorc.tests.nodes.WFCompute"}, exec, "tests")
+
+			wfSteps, err := builder.BuildWorkFlow(kv, deploymentID, "install")
+			require.Nil(t, err)
+			bs := wfSteps["Compute_install"]
+			require.NotNil(t, bs)
+			bs.Next = nil
+			bs.ExecutionPolicy = tt.policy
+
+			te := &taskExecution{id: "taskExecutionID", taskID: "taskPolicy" + strconv.Itoa(i), targetID: deploymentID}
+			s := wrapBuilderStep(bs, cc, te)
+			srv1.SetKV(t, path.Join(consulutil.WorkflowsPrefix, s.t.taskID, "Compute_install"), []byte("initial"))
+			err = s.run(context.Background(), config.Configuration{}, kv, deploymentID, false, "install", &worker{})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantCalls, exec.calls)
+
+			attempts, err := tasks.GetTaskStepAttempts(kv, s.t.taskID, "Compute_install")
+			require.NoError(t, err)
+			statuses := make([]string, len(attempts))
+			for i, a := range attempts {
+				require.Equal(t, i+1, a.Attempt)
+				require.NotNil(t, a.EndDate)
+				statuses[i] = a.Status
+			}
+			require.Equal(t, tt.wantAttempts, statuses)
+		})
+	}
+}
+
+func testRunStepWithFilter(t *testing.T, srv1 *testutil.TestServer, cc *api.Client) {
+	kv := cc.KV()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/workflow.yaml")
+	require.Nil(t, err)
+
+	tests := []struct {
+		name       string
+		filter     []tosca.ConditionClause
+		wantCalls  int
+		wantStatus tasks.TaskStepStatus
+	}{
+		{"FilterMet", []tosca.ConditionClause{{Value: "prod", Constraints: []tosca.ConstraintClause{{Operator: tosca.ConstraintEqual, Values: []string{"prod"}}}}}, 1, tasks.TaskStepStatusDONE},
+		{"FilterNotMet", []tosca.ConditionClause{{Value: "dev", Constraints: []tosca.ConstraintClause{{Operator: tosca.ConstraintEqual, Values: []string{"prod"}}}}}, 0, tasks.TaskStepStatusSKIPPED},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &flakyExecutor{}
+			registry.GetRegistry().RegisterDelegates([]string{"ystia.yorc.tests.nodes.WFCompute"}, exec, "tests")
+
+			wfSteps, err := builder.BuildWorkFlow(kv, deploymentID, "install")
+			require.Nil(t, err)
+			bs := wfSteps["Compute_install"]
+			require.NotNil(t, bs)
+			bs.Next = nil
+			bs.Filter = tt.filter
+
+			te := &taskExecution{id: "taskExecutionID", taskID: "taskFilter" + strconv.Itoa(i), targetID: deploymentID}
+			s := wrapBuilderStep(bs, cc, te)
+			srv1.SetKV(t, path.Join(consulutil.WorkflowsPrefix, s.t.taskID, "Compute_install"), []byte("initial"))
+			err = s.run(context.Background(), config.Configuration{}, kv, deploymentID, false, "install", &worker{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCalls, exec.calls)
+
+			status, err := tasks.GetTaskStepStatus(kv, s.t.taskID, "Compute_install")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func testRunStepByBatches(t *testing.T, srv1 *testutil.TestServer, cc *api.Client) {
+	kv := cc.KV()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(context.Background(), kv, deploymentID, "testdata/workflow.yaml")
+	require.Nil(t, err)
+
+	tests := []struct {
+		name          string
+		policy        deployments.BatchPolicy
+		taskData      map[string]string
+		nbFailures    int
+		wantInstances []string
+		wantErr       bool
+	}{
+		{"AllAtOnce", deployments.BatchPolicy{}, nil, 0, []string{"0,1,2,3,4"}, false},
+		{"Batches", deployments.BatchPolicy{BatchSize: 2, PauseBetweenBatches: time.Millisecond}, nil, 0, []string{"0,1", "2,3", "4"}, false},
+		{"StopOnFailingBatch", deployments.BatchPolicy{BatchSize: 2}, nil, 1, []string{"0,1"}, true},
+		{"TaskOverride", deployments.BatchPolicy{BatchSize: 1}, map[string]string{"batchSize": "3"}, 0, []string{"0,1,2", "3,4"}, false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &flakyExecutor{nbFailures: tt.nbFailures, kv: kv}
+			registry.GetRegistry().RegisterDelegates([]string{"ystia.yorc.tests.nodes.WFCompute"}, exec, "tests")
+
+			wfSteps, err := builder.BuildWorkFlow(kv, deploymentID, "install")
+			require.Nil(t, err)
+			bs := wfSteps["Compute_install"]
+			require.NotNil(t, bs)
+			bs.Next = nil
+			bs.BatchPolicy = tt.policy
+
+			te := &taskExecution{id: "taskExecutionID", taskID: "taskBatches" + strconv.Itoa(i), targetID: deploymentID}
+			data := map[string]string{path.Join("nodes", bs.Target): "0,1,2,3,4"}
+			for k, v := range tt.taskData {
+				data[k] = v
+			}
+			err = tasks.SetTaskDataList(kv, te.taskID, data)
+			require.NoError(t, err)
+			s := wrapBuilderStep(bs, cc, te)
+			srv1.SetKV(t, path.Join(consulutil.WorkflowsPrefix, s.t.taskID, "Compute_install"), []byte("initial"))
+			err = s.run(context.Background(), config.Configuration{}, kv, deploymentID, false, "install", &worker{})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantInstances, exec.instances)
+
+			// Instances of the task should be left untouched
+			instances, err := tasks.GetTaskData(kv, te.taskID, path.Join("nodes", bs.Target))
+			require.NoError(t, err)
+			require.Equal(t, "0,1,2,3,4", instances)
+		})
+	}
+}
+
 func clearActivityHooks() {
 	preActivityHooks = make([]ActivityHook, 0)
 	postActivityHooks = make([]ActivityHook, 0)
@@ -0,0 +1,202 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Constraints operators supported in condition clauses
+const (
+	ConstraintEqual          = "equal"
+	ConstraintGreaterThan    = "greater_than"
+	ConstraintGreaterOrEqual = "greater_or_equal"
+	ConstraintLessThan       = "less_than"
+	ConstraintLessOrEqual    = "less_or_equal"
+	ConstraintInRange        = "in_range"
+	ConstraintValidValues    = "valid_values"
+	ConstraintLength         = "length"
+	ConstraintMinLength      = "min_length"
+	ConstraintMaxLength      = "max_length"
+	ConstraintPattern        = "pattern"
+)
+
+// A ConstraintClause is the representation of a TOSCA Constraint Clause
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.2/TOSCA-Simple-Profile-YAML-v1.2.html#DEFN_ELEMENT_CONSTRAINTS_OPERATORS
+// for more details
+type ConstraintClause struct {
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// UnmarshalYAML unmarshals a yaml into a ConstraintClause
+func (c *ConstraintClause) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m map[string]interface{}
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	if len(m) != 1 {
+		return errors.Errorf("a constraint clause should have exactly one operator, got %d", len(m))
+	}
+	for op, v := range m {
+		c.Operator = op
+		c.Values = nil
+		if l, ok := v.([]interface{}); ok {
+			for _, lv := range l {
+				c.Values = append(c.Values, fmt.Sprint(lv))
+			}
+		} else {
+			c.Values = []string{fmt.Sprint(v)}
+		}
+	}
+	return c.check()
+}
+
+// check validates the operator and the number of values of a ConstraintClause
+func (c ConstraintClause) check() error {
+	nbValues := 1
+	switch c.Operator {
+	case ConstraintEqual, ConstraintGreaterThan, ConstraintGreaterOrEqual, ConstraintLessThan, ConstraintLessOrEqual:
+	case ConstraintInRange:
+		nbValues = 2
+	case ConstraintValidValues:
+		if len(c.Values) == 0 {
+			return errors.Errorf("constraint %q expects at least one value", c.Operator)
+		}
+		return nil
+	case ConstraintLength, ConstraintMinLength, ConstraintMaxLength:
+		if len(c.Values) == 1 {
+			if _, err := strconv.Atoi(c.Values[0]); err != nil {
+				return errors.Errorf("constraint %q expects an integer value, got %q", c.Operator, c.Values[0])
+			}
+		}
+	case ConstraintPattern:
+		if len(c.Values) == 1 {
+			if _, err := regexp.Compile(c.Values[0]); err != nil {
+				return errors.Wrapf(err, "invalid pattern %q", c.Values[0])
+			}
+		}
+	default:
+		return errors.Errorf("unsupported constraint operator %q", c.Operator)
+	}
+	if len(c.Values) != nbValues {
+		return errors.Errorf("constraint %q expects %d value(s), got %d", c.Operator, nbValues, len(c.Values))
+	}
+	return nil
+}
+
+// A ConditionClause is the representation of a TOSCA Condition Clause used in workflows steps filters and preconditions
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ELEMENT_CONDITION_CLAUSE_DEFN
+// for more details.
+//
+// Clauses defined in a single ConditionClause should all be met.
+type ConditionClause struct {
+	And []ConditionClause `json:"and,omitempty"`
+	Or  []ConditionClause `json:"or,omitempty"`
+	Not []ConditionClause `json:"not,omitempty"`
+	// Attributes maps names of attributes of the targeted node to the constraints they should meet
+	Attributes map[string][]ConstraintClause `json:"attributes,omitempty"`
+
+	// Non standard, allows to define constraints on the result of a value assignment like a get_input, get_property
+	// or get_attribute function. Value is the textual representation of the ValueAssignment.
+	Value       string             `json:"value,omitempty"`
+	Constraints []ConstraintClause `json:"constraints,omitempty"`
+}
+
+// conditionClauseUnmarshaler allows to delay the parsing of a condition clause element until its keyword is known
+type conditionClauseUnmarshaler struct {
+	unmarshal func(interface{}) error
+}
+
+func (u *conditionClauseUnmarshaler) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	u.unmarshal = unmarshal
+	return nil
+}
+
+// UnmarshalYAML unmarshals a yaml into a ConditionClause
+func (c *ConditionClause) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m map[string]*conditionClauseUnmarshaler
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// Sort keys to report errors consistently
+	sort.Strings(keys)
+
+	_, hasValue := m["value"]
+	_, hasConstraints := m["constraints"]
+	if hasValue != hasConstraints {
+		return errors.New(`"value" and "constraints" keywords should be used together in a condition clause`)
+	}
+	for _, k := range keys {
+		u := m[k]
+		if u == nil {
+			return errors.Errorf("missing definition for %q in condition clause", k)
+		}
+		var err error
+		switch k {
+		case "and":
+			err = u.unmarshal(&c.And)
+		case "or":
+			err = u.unmarshal(&c.Or)
+		case "not":
+			err = u.unmarshal(&c.Not)
+		case "assert":
+			// assert is a list of assertions on attributes, they are all required to be met
+			var assertions []map[string][]ConstraintClause
+			err = u.unmarshal(&assertions)
+			for _, a := range assertions {
+				c.And = append(c.And, ConditionClause{Attributes: a})
+			}
+		case "value":
+			va := ValueAssignment{}
+			err = u.unmarshal(&va)
+			c.Value = va.String()
+		case "constraints":
+			err = u.unmarshal(&c.Constraints)
+		default:
+			var constraints []ConstraintClause
+			err = u.unmarshal(&constraints)
+			if c.Attributes == nil {
+				c.Attributes = make(map[string][]ConstraintClause)
+			}
+			c.Attributes[k] = constraints
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %q in condition clause", k)
+		}
+	}
+	return nil
+}
+
+// HasAttributesAssertions checks if some of the given condition clauses define assertions on attributes of the targeted node
+func HasAttributesAssertions(clauses []ConditionClause) bool {
+	for _, c := range clauses {
+		if len(c.Attributes) > 0 || HasAttributesAssertions(c.And) || HasAttributesAssertions(c.Or) || HasAttributesAssertions(c.Not) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConstraintClauseUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    ConstraintClause
+		wantErr bool
+	}{
+		{"Equal", `equal: production`, ConstraintClause{Operator: ConstraintEqual, Values: []string{"production"}}, false},
+		{"GreaterThanInt", `greater_than: 2`, ConstraintClause{Operator: ConstraintGreaterThan, Values: []string{"2"}}, false},
+		{"InRange", `in_range: [1, 5]`, ConstraintClause{Operator: ConstraintInRange, Values: []string{"1", "5"}}, false},
+		{"ValidValues", `valid_values: [dev, test]`, ConstraintClause{Operator: ConstraintValidValues, Values: []string{"dev", "test"}}, false},
+		{"Pattern", `pattern: "^[a-z]+$"`, ConstraintClause{Operator: ConstraintPattern, Values: []string{"^[a-z]+$"}}, false},
+		{"UnknownOperator", `different: 2`, ConstraintClause{}, true},
+		{"InRangeMissingBound", `in_range: [1]`, ConstraintClause{}, true},
+		{"InvalidLength", `min_length: two`, ConstraintClause{}, true},
+		{"InvalidPattern", `pattern: "[a-z"`, ConstraintClause{}, true},
+		{"SeveralOperators", `{equal: 1, less_than: 2}`, ConstraintClause{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ConstraintClause{}
+			err := yaml.Unmarshal([]byte(tt.input), &c)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestStepFilterUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+target: Node
+filter:
+  - state: [{equal: started}]
+  - value: { get_input: environment }
+    constraints:
+      - valid_values: [dev, test]
+  - or:
+      - assert:
+          - port: [{greater_or_equal: 1024}]
+      - not:
+          - value: { get_property: [SELF, secured] }
+            constraints: [{equal: true}]
+activities:
+  - call_operation: Standard.start
+`
+	step := Step{}
+	err := yaml.Unmarshal([]byte(inputYaml), &step)
+	require.NoError(t, err)
+	require.Equal(t, []ConditionClause{
+		{Attributes: map[string][]ConstraintClause{"state": {{Operator: ConstraintEqual, Values: []string{"started"}}}}},
+		{Value: "get_input: environment", Constraints: []ConstraintClause{{Operator: ConstraintValidValues, Values: []string{"dev", "test"}}}},
+		{Or: []ConditionClause{
+			{And: []ConditionClause{{Attributes: map[string][]ConstraintClause{"port": {{Operator: ConstraintGreaterOrEqual, Values: []string{"1024"}}}}}}},
+			{Not: []ConditionClause{{Value: "get_property: [SELF, secured]", Constraints: []ConstraintClause{{Operator: ConstraintEqual, Values: []string{"true"}}}}}},
+		}},
+	}, step.Filter)
+	require.True(t, HasAttributesAssertions(step.Filter))
+	require.False(t, HasAttributesAssertions(step.Filter[1:2]))
+
+	err = yaml.Unmarshal([]byte(`filter: [{value: {get_input: environment}}]`), &step)
+	require.Error(t, err, "value without constraints should be rejected")
+}
+
+func TestWorkflowPreconditionsUnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+preconditions:
+  - target: Database
+    condition:
+      - state: [{equal: started}]
+steps: {}
+`
+	wf := Workflow{}
+	err := yaml.Unmarshal([]byte(inputYaml), &wf)
+	require.NoError(t, err)
+	require.Len(t, wf.Preconditions, 1)
+	require.Equal(t, "Database", wf.Preconditions[0].Target)
+	require.Len(t, wf.Preconditions[0].Condition, 1)
+	require.Contains(t, wf.Preconditions[0].Condition[0].Attributes, "state")
+}
@@ -21,6 +21,7 @@ type InterfaceDefinition struct {
 	Type        string                         `yaml:"type,omitempty"`
 	Description string                         `yaml:"description,omitempty"`
 	Inputs      map[string]Input               `yaml:"inputs,omitempty"`
+	Metadata    map[string]string              `yaml:"metadata,omitempty"`
 	Operations  map[string]OperationDefinition `yaml:",inline,omitempty"`
 }
 
@@ -31,6 +32,8 @@ type OperationDefinition struct {
 	Inputs         map[string]Input `yaml:"inputs,omitempty"`
 	Description    string           `yaml:"description,omitempty"`
 	Implementation Implementation   `yaml:"implementation,omitempty"`
+	// Non standard, metadata override the ones defined on the interface for this operation
+	Metadata map[string]string `yaml:"metadata,omitempty"`
 }
 
 // UnmarshalYAML unmarshals a yaml into an InterfaceDefinition
@@ -41,9 +44,10 @@ func (i *OperationDefinition) UnmarshalYAML(unmarshal func(interface{}) error) e
 		return nil
 	}
 	var str struct {
-		Inputs         map[string]Input `yaml:"inputs,omitempty"`
-		Description    string           `yaml:"description,omitempty"`
-		Implementation Implementation   `yaml:"implementation,omitempty"`
+		Inputs         map[string]Input  `yaml:"inputs,omitempty"`
+		Description    string            `yaml:"description,omitempty"`
+		Implementation Implementation    `yaml:"implementation,omitempty"`
+		Metadata       map[string]string `yaml:"metadata,omitempty"`
 	}
 	if err := unmarshal(&str); err != nil {
 		return err
@@ -51,5 +55,6 @@ func (i *OperationDefinition) UnmarshalYAML(unmarshal func(interface{}) error) e
 	i.Inputs = str.Inputs
 	i.Implementation = str.Implementation
 	i.Description = str.Description
+	i.Metadata = str.Metadata
 	return nil
 }
@@ -19,6 +19,17 @@ package tosca
 // Currently Workflows are not part of the TOSCA specification
 type Workflow struct {
 	Steps map[string]*Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+	// Preconditions should be met for the workflow steps to be run, otherwise they are skipped
+	Preconditions []Precondition `yaml:"preconditions,omitempty" json:"preconditions,omitempty"`
+}
+
+// A Precondition is the representation of a TOSCA Workflow Precondition
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ENTITY_WORKFLOW_PRECONDITION_DEFN
+// for more details
+type Precondition struct {
+	Target    string            `yaml:"target,omitempty" json:"target,omitempty"`
+	Condition []ConditionClause `yaml:"condition,omitempty" json:"condition,omitempty"`
 }
 
 // An Step is the representation of a TOSCA Workflow Step
@@ -32,9 +43,23 @@ type Step struct {
 	OnSuccess          []string   `yaml:"on_success,omitempty" json:"on_success,omitempty"`
 	OnFailure          []string   `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
 	OperationHost      string     `yaml:"operation_host,omitempty" json:"operation_host,omitempty"`
+	// Filter should be met for the step to be run, otherwise it is skipped
+	Filter []ConditionClause `yaml:"filter,omitempty" json:"filter,omitempty"`
 
 	// Non standard
 	OnCancel []string `yaml:"on_cancel,omitempty" json:"on_cancel,omitempty"`
+	// Timeout is the maximum duration of an attempt to run the step activities (ex: "10m")
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// MaxRetries is the number of times the step activities are retried on failure
+	MaxRetries int `yaml:"max_retries,omitempty" json:"max_retries,omitempty"`
+	// RetryBackoff is the delay before the first retry, it is doubled for each subsequent retry (ex: "30s")
+	RetryBackoff string `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
+	// RetryOn lists the classes of errors that trigger a retry ("timeout" or "error"), all of them by default
+	RetryOn []string `yaml:"retry_on,omitempty" json:"retry_on,omitempty"`
+	// BatchSize is the maximum number of instances of the target processed at once, all of them by default
+	BatchSize int `yaml:"batch_size,omitempty" json:"batch_size,omitempty"`
+	// PauseBetweenBatches is the delay between the end of a batch of instances and the start of the next one (ex: "1m")
+	PauseBetweenBatches string `yaml:"pause_between_batches,omitempty" json:"pause_between_batches,omitempty"`
 }
 
 // An Activity is the representation of a TOSCA Workflow Step Activity